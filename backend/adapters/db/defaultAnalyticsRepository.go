@@ -0,0 +1,50 @@
+package db
+
+import (
+	"database/sql"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+)
+
+type DefaultAnalyticsRepository struct {
+	db *sql.DB
+}
+
+func NewAnalyticsRepository(db *sql.DB) *DefaultAnalyticsRepository {
+	return &DefaultAnalyticsRepository{
+		db: db,
+	}
+}
+
+func (ar *DefaultAnalyticsRepository) SaveClick(click *u.Click) error {
+	_, err := ar.db.Exec(
+		`INSERT INTO url_clicks (domain,short_id,variant,create_time) VALUES ($1,$2,$3,$4)`,
+		click.Domain,
+		click.ShortID,
+		click.Variant,
+		click.CreateTime,
+	)
+	return err
+}
+
+func (ar *DefaultAnalyticsRepository) ClickCounts(domain string, shortID string) (u.ClickCounts, error) {
+	rows, err := ar.db.Query(
+		`SELECT variant, COUNT(*) FROM url_clicks WHERE domain = $1 AND short_id = $2 GROUP BY variant`,
+		domain,
+		shortID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := u.ClickCounts{}
+	for rows.Next() {
+		var variant string
+		var clicks int64
+		if err = rows.Scan(&variant, &clicks); err != nil {
+			return nil, err
+		}
+		counts[variant] = clicks
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"database/sql"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"os"
+	"testing"
+	"time"
+)
+
+type AnalyticsRepositoryTestSuite struct {
+	suite.Suite
+	db            *sql.DB
+	urlRepo       *DefaultURLRepository
+	analyticsRepo *DefaultAnalyticsRepository
+}
+
+func TestAnalyticsRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AnalyticsRepositoryTestSuite))
+}
+
+func (suite *AnalyticsRepositoryTestSuite) SetupTest() {
+	connStr := os.Getenv("TEST_DB_CONN_STRING")
+	if len(connStr) == 0 {
+		connStr = "postgres://localhost/url_shortener_test?sslmode=disable"
+	}
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		panic(err)
+	}
+
+	if err = db.Ping(); err != nil {
+		panic(err)
+	}
+
+	suite.db = db
+	suite.urlRepo = NewURLRepository(suite.db)
+	suite.analyticsRepo = NewAnalyticsRepository(suite.db)
+
+	_, err = suite.urlRepo.SaveRecord(&u.URLRecord{
+		LongURL: savedLongURL,
+		Domain:  savedDomain,
+		ShortID: savedShortID,
+		Destinations: []u.Destination{
+			{Variant: "A", LongURL: savedLongURL + "/a", Weight: 70},
+			{Variant: "B", LongURL: savedLongURL + "/b", Weight: 30},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (suite *AnalyticsRepositoryTestSuite) TearDownTest() {
+	_, err := suite.db.Exec("DELETE FROM url_records")
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (suite *AnalyticsRepositoryTestSuite) TestClickCountsGroupedByVariant() {
+	for _, variant := range []string{"A", "A", "B"} {
+		err := suite.analyticsRepo.SaveClick(&u.Click{Domain: savedDomain, ShortID: savedShortID, Variant: variant, CreateTime: time.Now()})
+		assert.Nil(suite.T(), err, "Expected: save click. Got: %s", err)
+	}
+
+	counts, err := suite.analyticsRepo.ClickCounts(savedDomain, savedShortID)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), u.ClickCounts{"A": 2, "B": 1}, counts)
+}
+
+func (suite *AnalyticsRepositoryTestSuite) TestDestinationsAreSavedInOrder() {
+	record, err := suite.urlRepo.LongURL(savedDomain, savedShortID)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []u.Destination{
+		{Variant: "A", LongURL: savedLongURL + "/a", Weight: 70},
+		{Variant: "B", LongURL: savedLongURL + "/b", Weight: 30},
+	}, record.Destinations)
+}
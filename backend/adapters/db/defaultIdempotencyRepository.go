@@ -0,0 +1,142 @@
+package db
+
+import (
+	"database/sql"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"github.com/w-k-s/short-url/log"
+	"time"
+)
+
+type DefaultIdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *DefaultIdempotencyRepository {
+	return &DefaultIdempotencyRepository{
+		db: db,
+	}
+}
+
+// ReserveKey inserts the record, or replaces the record of the owner's key if it has expired.
+// Expired records of other keys are deleted by DeleteExpiredKeys.
+func (ir *DefaultIdempotencyRepository) ReserveKey(record *u.IdempotencyRecord) (bool, error) {
+	result, err := ir.db.Exec(
+		`INSERT INTO idempotency_keys (owner,key,fingerprint,create_time,expire_time) VALUES ($1,$2,$3,$4,$5)
+		ON CONFLICT (owner,key) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, response = NULL, create_time = EXCLUDED.create_time, expire_time = EXCLUDED.expire_time
+		WHERE idempotency_keys.expire_time <= EXCLUDED.create_time`,
+		record.Owner,
+		record.Key,
+		record.Fingerprint,
+		record.CreateTime,
+		record.ExpireTime,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	reserved, err := result.RowsAffected()
+	return reserved == 1, err
+}
+
+func (ir *DefaultIdempotencyRepository) FindKey(owner string, key string) (*u.IdempotencyRecord, error) {
+	var record u.IdempotencyRecord
+	var response sql.NullString
+
+	err := ir.db.QueryRow(
+		`SELECT owner, key, fingerprint, response, create_time, expire_time FROM idempotency_keys WHERE owner = $1 AND key = $2`,
+		owner,
+		key,
+	).Scan(&record.Owner, &record.Key, &record.Fingerprint, &response, &record.CreateTime, &record.ExpireTime)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Valid {
+		record.Response = []byte(response.String)
+	}
+	return &record, nil
+}
+
+func (ir *DefaultIdempotencyRepository) SaveResponse(owner string, key string, response []byte) error {
+	_, err := ir.db.Exec(
+		`UPDATE idempotency_keys SET response = $3 WHERE owner = $1 AND key = $2`,
+		owner,
+		key,
+		string(response),
+	)
+	return err
+}
+
+func (ir *DefaultIdempotencyRepository) ReleaseKey(owner string, key string) error {
+	_, err := ir.db.Exec(
+		`DELETE FROM idempotency_keys WHERE owner = $1 AND key = $2`,
+		owner,
+		key,
+	)
+	return err
+}
+
+// DeleteExpiredKeys deletes up to limit records that expired before the time, and returns how many were deleted.
+// The limit keeps each statement short, so that it does not hold locks on the table for long.
+func (ir *DefaultIdempotencyRepository) DeleteExpiredKeys(before time.Time, limit int) (int64, error) {
+	result, err := ir.db.Exec(
+		`DELETE FROM idempotency_keys WHERE (owner,key) IN (SELECT owner, key FROM idempotency_keys WHERE expire_time <= $1 LIMIT $2)`,
+		before,
+		limit,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CleanUp deletes the expired records in batches of batchSize every interval until the returned function is called,
+// so that the table does not grow with keys that are never retried.
+func (ir *DefaultIdempotencyRepository) CleanUp(interval time.Duration, batchSize int) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				ir.deleteExpiredKeys(batchSize, done)
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// deleteExpiredKeys deletes batches until there are no expired records left, or until the clean up is stopped
+func (ir *DefaultIdempotencyRepository) deleteExpiredKeys(batchSize int, done <-chan struct{}) {
+	now := time.Now()
+	for {
+		deleted, err := ir.DeleteExpiredKeys(now, batchSize)
+		if err != nil {
+			log.Printf("Failed to delete expired idempotency keys: %s", err)
+			return
+		}
+		if deleted < int64(batchSize) {
+			return
+		}
+
+		select {
+		case <-done:
+			return
+		default:
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"database/sql"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"os"
+	"testing"
+	"time"
+)
+
+type IdempotencyRepositoryTestSuite struct {
+	suite.Suite
+	db              *sql.DB
+	idempotencyRepo *DefaultIdempotencyRepository
+}
+
+func TestIdempotencyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyRepositoryTestSuite))
+}
+
+func (suite *IdempotencyRepositoryTestSuite) SetupTest() {
+	connStr := os.Getenv("TEST_DB_CONN_STRING")
+	if len(connStr) == 0 {
+		connStr = "postgres://localhost/url_shortener_test?sslmode=disable"
+	}
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		panic(err)
+	}
+
+	if err = db.Ping(); err != nil {
+		panic(err)
+	}
+
+	suite.db = db
+	suite.idempotencyRepo = NewIdempotencyRepository(suite.db)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TearDownTest() {
+	_, err := suite.db.Exec("DELETE FROM idempotency_keys")
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (suite *IdempotencyRepositoryTestSuite) reserve(key string, fingerprint string, createTime time.Time) bool {
+	return suite.reserveForOwner("owner", key, fingerprint, createTime)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) reserveForOwner(owner string, key string, fingerprint string, createTime time.Time) bool {
+	reserved, err := suite.idempotencyRepo.ReserveKey(&u.IdempotencyRecord{
+		Owner:       owner,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreateTime:  createTime,
+		ExpireTime:  createTime.Add(time.Hour),
+	})
+	assert.Nil(suite.T(), err, "Expected: reserve key. Got: %s", err)
+	return reserved
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestReservedKeyCanNotBeReservedAgain() {
+	now := time.Now()
+	assert.True(suite.T(), suite.reserve("key", "first", now))
+
+	reserved := suite.reserve("key", "second", now.Add(time.Minute))
+
+	assert.False(suite.T(), reserved)
+	record, err := suite.idempotencyRepo.FindKey("owner", "key")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "first", record.Fingerprint)
+	assert.Nil(suite.T(), record.Response)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestExpiredKeyCanBeReservedAgain() {
+	now := time.Now()
+	assert.True(suite.T(), suite.reserve("key", "first", now.Add(-2*time.Hour)))
+
+	reserved := suite.reserve("key", "second", now)
+
+	assert.True(suite.T(), reserved)
+	record, err := suite.idempotencyRepo.FindKey("owner", "key")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "second", record.Fingerprint)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestSavedResponseIsFound() {
+	assert.True(suite.T(), suite.reserve("key", "fingerprint", time.Now()))
+
+	err := suite.idempotencyRepo.SaveResponse("owner", "key", []byte(`{"shortUrl":"https://small.ml/abc"}`))
+
+	assert.Nil(suite.T(), err)
+	record, err := suite.idempotencyRepo.FindKey("owner", "key")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), `{"shortUrl":"https://small.ml/abc"}`, string(record.Response))
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestReleasedKeyIsNotFound() {
+	assert.True(suite.T(), suite.reserve("key", "fingerprint", time.Now()))
+
+	err := suite.idempotencyRepo.ReleaseKey("owner", "key")
+
+	assert.Nil(suite.T(), err)
+	record, err := suite.idempotencyRepo.FindKey("owner", "key")
+	assert.Nil(suite.T(), err)
+	assert.Nil(suite.T(), record)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestKeyOfOtherOwnerCanBeReserved() {
+	now := time.Now()
+	assert.True(suite.T(), suite.reserveForOwner("alice", "key", "first", now))
+
+	reserved := suite.reserveForOwner("bob", "key", "second", now)
+
+	assert.True(suite.T(), reserved)
+	record, err := suite.idempotencyRepo.FindKey("alice", "key")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "first", record.Fingerprint)
+}
+
+func (suite *IdempotencyRepositoryTestSuite) TestDeleteExpiredKeysDeletesUpToLimit() {
+	now := time.Now()
+	assert.True(suite.T(), suite.reserve("expired-1", "fingerprint", now.Add(-3*time.Hour)))
+	assert.True(suite.T(), suite.reserve("expired-2", "fingerprint", now.Add(-2*time.Hour)))
+	assert.True(suite.T(), suite.reserve("live", "fingerprint", now))
+
+	deleted, err := suite.idempotencyRepo.DeleteExpiredKeys(now, 1)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), deleted)
+
+	deleted, err = suite.idempotencyRepo.DeleteExpiredKeys(now, 10)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), deleted)
+
+	record, err := suite.idempotencyRepo.FindKey("owner", "live")
+	assert.Nil(suite.T(), err)
+	assert.NotNil(suite.T(), record)
+}
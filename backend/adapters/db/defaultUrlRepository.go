@@ -3,10 +3,22 @@ package db
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/lib/pq"
 	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"net/url"
+	"strings"
 )
 
+// recordColumns is the number of parameters that inserting a record binds.
+// Postgres allows at most 65535 parameters in a statement, so larger batches are inserted in several statements.
+const (
+	recordColumns       = 11
+	maxRecordsPerInsert = 65535 / recordColumns
+)
+
+const urlRecordColumns = "long_url, domain, short_id, owner, create_time, update_time, disabled, disabled_reason, redirect_status, query_merge, wildcard, social_title, social_description, social_image"
+
 type DefaultURLRepository struct {
 	db *sql.DB
 }
@@ -18,17 +30,158 @@ func NewURLRepository(db *sql.DB) *DefaultURLRepository {
 }
 
 func (ur *DefaultURLRepository) SaveRecord(record *u.URLRecord) (*u.URLRecord, error) {
-	_, err := ur.db.Exec(
-		`INSERT INTO url_records (long_url,short_id) VALUES ($1,$2)`,
-		record.LongURL,
-		record.ShortID,
-	)
+	err := ur.inTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			`INSERT INTO url_records (long_url,domain,short_id,owner,redirect_status,query_merge,wildcard,social_title,social_description,social_image,long_url_host) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
+			record.LongURL,
+			record.Domain,
+			record.ShortID,
+			record.Owner,
+			record.RedirectStatus,
+			record.QueryMerge,
+			record.Wildcard,
+			record.Social.Title,
+			record.Social.Description,
+			record.Social.ImageURL,
+			longURLHost(record.LongURL),
+		)
+		if err != nil {
+			return err
+		}
+		return insertChildRows(tx, record)
+	})
 
 	return record, err
 }
 
-func (ur *DefaultURLRepository) LongURL(shortID string) (*u.URLRecord, error) {
-	rows, err := ur.db.Query("SELECT * FROM url_records WHERE short_id = $1", shortID)
+func (ur *DefaultURLRepository) SaveRecords(records []*u.URLRecord) ([]*u.URLRecord, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	var saved []*u.URLRecord
+	err := ur.inTransaction(func(tx *sql.Tx) error {
+		inserted := map[string]bool{}
+		for start := 0; start < len(records); start += maxRecordsPerInsert {
+			end := start + maxRecordsPerInsert
+			if end > len(records) {
+				end = len(records)
+			}
+			if err := insertRecords(tx, records[start:end], inserted); err != nil {
+				return err
+			}
+		}
+
+		for _, record := range records {
+			// If the batch has the same shortId more than once, only the first record was inserted
+			if !inserted[recordKey(record.Domain, record.ShortID)] {
+				continue
+			}
+			delete(inserted, recordKey(record.Domain, record.ShortID))
+
+			if err := insertChildRows(tx, record); err != nil {
+				return err
+			}
+			saved = append(saved, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// insertRecords inserts the records in one statement, and adds the keys of the records that were inserted.
+// Records whose shortId is in use are skipped rather than failing the whole batch.
+func insertRecords(tx *sql.Tx, records []*u.URLRecord, inserted map[string]bool) error {
+	values := make([]string, 0, len(records))
+	args := make([]interface{}, 0, len(records)*recordColumns)
+	for i, record := range records {
+		n := i * recordColumns
+		values = append(values, fmt.Sprintf("($%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11))
+		args = append(args,
+			record.LongURL,
+			record.Domain,
+			record.ShortID,
+			record.Owner,
+			record.RedirectStatus,
+			record.QueryMerge,
+			record.Wildcard,
+			record.Social.Title,
+			record.Social.Description,
+			record.Social.ImageURL,
+			longURLHost(record.LongURL),
+		)
+	}
+
+	rows, err := tx.Query(
+		`INSERT INTO url_records (long_url,domain,short_id,owner,redirect_status,query_merge,wildcard,social_title,social_description,social_image,long_url_host) VALUES `+
+			strings.Join(values, ",")+
+			` ON CONFLICT (domain, short_id) DO NOTHING RETURNING domain, short_id`,
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var domain, shortID string
+		if err = rows.Scan(&domain, &shortID); err != nil {
+			return err
+		}
+		inserted[recordKey(domain, shortID)] = true
+	}
+	return rows.Err()
+}
+
+func (ur *DefaultURLRepository) UpdateRecord(record *u.URLRecord) error {
+	return ur.inTransaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(
+			`UPDATE url_records SET disabled = $2, disabled_reason = $3, redirect_status = $4, query_merge = $5, wildcard = $6, social_title = $7, social_description = $8, social_image = $9, update_time = CURRENT_TIMESTAMP WHERE short_id = $1 AND domain = $10`,
+			record.ShortID,
+			record.Disabled,
+			record.DisabledReason,
+			record.RedirectStatus,
+			record.QueryMerge,
+			record.Wildcard,
+			record.Social.Title,
+			record.Social.Description,
+			record.Social.ImageURL,
+			record.Domain,
+		)
+		if err != nil {
+			return err
+		}
+
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return errors.New("Not Found")
+		}
+
+		for _, table := range []string{"url_targeting_rules", "url_destinations", "url_tags", "url_metadata"} {
+			if _, err = tx.Exec(`DELETE FROM `+table+` WHERE domain = $1 AND short_id = $2`, record.Domain, record.ShortID); err != nil {
+				return err
+			}
+		}
+		return insertChildRows(tx, record)
+	})
+}
+
+func (ur *DefaultURLRepository) LongURL(domain string, shortID string) (*u.URLRecord, error) {
+	return ur.findRecord("SELECT "+urlRecordColumns+" FROM url_records WHERE domain = $1 AND short_id = $2", domain, shortID)
+}
+
+func (ur *DefaultURLRepository) ShortURL(domain string, longURL string) (*u.URLRecord, error) {
+	return ur.findRecord("SELECT "+urlRecordColumns+" FROM url_records WHERE long_url = $1 AND domain = $2 ORDER BY create_time LIMIT 1", longURL, domain)
+}
+
+func (ur *DefaultURLRepository) ShortURLForOwner(domain string, longURL string, owner string) (*u.URLRecord, error) {
+	return ur.findRecord("SELECT "+urlRecordColumns+" FROM url_records WHERE long_url = $1 AND owner = $2 AND domain = $3 ORDER BY create_time LIMIT 1", longURL, owner, domain)
+}
+
+func (ur *DefaultURLRepository) findRecord(query string, args ...interface{}) (*u.URLRecord, error) {
+	rows, err := ur.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,33 +191,324 @@ func (ur *DefaultURLRepository) LongURL(shortID string) (*u.URLRecord, error) {
 		return nil, errors.New("Not Found")
 	}
 
-	var record u.URLRecord
-	if err = rows.Scan(&record.LongURL, &record.ShortID, &record.CreateTime); err != nil {
+	record, err := scanRecord(rows)
+	if err != nil {
 		return nil, err
 	}
+	rows.Close()
 
-	return &record, nil
+	if record.TargetingRules, err = ur.targetingRules(record.Domain, record.ShortID); err != nil {
+		return nil, err
+	}
+	if record.Destinations, err = ur.destinations(record.Domain, record.ShortID); err != nil {
+		return nil, err
+	}
+	if err = ur.loadTagsAndMetadata([]*u.URLRecord{record}); err != nil {
+		return nil, err
+	}
+
+	return record, nil
 }
 
-func (ur *DefaultURLRepository) ShortURL(longURL string) (*u.URLRecord, error) {
-	rows, err := ur.db.Query("SELECT * FROM url_records WHERE long_url = $1", longURL)
+// ListRecords returns a page of records without their targeting rules and destinations
+func (ur *DefaultURLRepository) ListRecords(query u.RecordQuery) ([]*u.URLRecord, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if query.Domain != "" {
+		conditions = append(conditions, "domain = "+arg(query.Domain))
+	}
+	if query.Owner != "" {
+		conditions = append(conditions, "owner = "+arg(query.Owner))
+	}
+	if !query.CreatedAfter.IsZero() {
+		conditions = append(conditions, "create_time >= "+arg(query.CreatedAfter))
+	}
+	if !query.CreatedBefore.IsZero() {
+		conditions = append(conditions, "create_time < "+arg(query.CreatedBefore))
+	}
+	if query.Host != "" {
+		conditions = append(conditions, "long_url_host = "+arg(strings.ToLower(query.Host)))
+	}
+	if len(query.Tags) > 0 {
+		conditions = append(conditions, "(domain, short_id) IN (SELECT domain, short_id FROM url_tags WHERE tag = ANY("+arg(pq.Array(query.Tags))+") GROUP BY domain, short_id HAVING count(*) = "+arg(len(query.Tags))+")")
+	}
+	if query.Search != "" {
+		pattern := arg("%" + escapeLike(strings.ToLower(query.Search)) + "%")
+		conditions = append(conditions, "(lower(long_url) LIKE "+pattern+" OR lower(short_id) LIKE "+pattern+")")
+	}
+
+	sortColumn := "create_time"
+	switch query.SortBy {
+	case u.SortByLongURL:
+		sortColumn = "long_url"
+	case u.SortByShortID:
+		sortColumn = "short_id"
+	}
+	comparison, direction := ">", "ASC"
+	if query.Descending {
+		comparison, direction = "<", "DESC"
+	}
+
+	if after := query.After; after != nil {
+		switch query.SortBy {
+		case u.SortByShortID:
+			conditions = append(conditions, "(short_id, domain) "+comparison+" ("+arg(after.ShortID)+", "+arg(after.Domain)+")")
+		case u.SortByLongURL:
+			conditions = append(conditions, "(long_url, short_id, domain) "+comparison+" ("+arg(after.LongURL)+", "+arg(after.ShortID)+", "+arg(after.Domain)+")")
+		default:
+			conditions = append(conditions, "(create_time, short_id, domain) "+comparison+" ("+arg(after.CreateTime)+", "+arg(after.ShortID)+", "+arg(after.Domain)+")")
+		}
+	}
+
+	statement := "SELECT " + urlRecordColumns + " FROM url_records"
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	statement += " ORDER BY " + sortColumn + " " + direction
+	if sortColumn != "short_id" {
+		statement += ", short_id " + direction
+	}
+	statement += ", domain " + direction
+	if query.Limit > 0 {
+		statement += " LIMIT " + arg(query.Limit)
+	}
+
+	rows, err := ur.db.Query(statement, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	if !rows.Next() {
-		return nil, errors.New("Not Found")
+	records := []*u.URLRecord{}
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
 
-	var record u.URLRecord
-	if err = rows.Scan(&record.LongURL, &record.ShortID, &record.CreateTime); err != nil {
+	if err = ur.loadTagsAndMetadata(records); err != nil {
 		return nil, err
 	}
+	return records, nil
+}
 
+func scanRecord(rows *sql.Rows) (*u.URLRecord, error) {
+	var record u.URLRecord
+	err := rows.Scan(&record.LongURL, &record.Domain, &record.ShortID, &record.Owner, &record.CreateTime, &record.UpdateTime, &record.Disabled, &record.DisabledReason, &record.RedirectStatus, &record.QueryMerge, &record.Wildcard, &record.Social.Title, &record.Social.Description, &record.Social.ImageURL)
+	if err != nil {
+		return nil, err
+	}
 	return &record, nil
 }
 
+// longURLHost is the lowercase host of the long url, which is stored so that records can be listed by host
+func longURLHost(longURL string) string {
+	parsed, err := url.Parse(longURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// escapeLike escapes the characters that have a special meaning in a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (ur *DefaultURLRepository) targetingRules(domain string, shortID string) ([]u.TargetingRule, error) {
+	rows, err := ur.db.Query(
+		`SELECT platform, language, country, long_url FROM url_targeting_rules WHERE domain = $1 AND short_id = $2 ORDER BY position`,
+		domain,
+		shortID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []u.TargetingRule
+	for rows.Next() {
+		var rule u.TargetingRule
+		if err = rows.Scan(&rule.Platform, &rule.Language, &rule.Country, &rule.LongURL); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func insertTargetingRules(tx *sql.Tx, record *u.URLRecord) error {
+	for position, rule := range record.TargetingRules {
+		_, err := tx.Exec(
+			`INSERT INTO url_targeting_rules (domain,short_id,position,platform,language,country,long_url) VALUES ($1,$2,$3,$4,$5,$6,$7)`,
+			record.Domain,
+			record.ShortID,
+			position,
+			rule.Platform,
+			rule.Language,
+			rule.Country,
+			rule.LongURL,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ur *DefaultURLRepository) destinations(domain string, shortID string) ([]u.Destination, error) {
+	rows, err := ur.db.Query(
+		`SELECT variant, long_url, weight FROM url_destinations WHERE domain = $1 AND short_id = $2 ORDER BY position`,
+		domain,
+		shortID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var destinations []u.Destination
+	for rows.Next() {
+		var destination u.Destination
+		if err = rows.Scan(&destination.Variant, &destination.LongURL, &destination.Weight); err != nil {
+			return nil, err
+		}
+		destinations = append(destinations, destination)
+	}
+	return destinations, rows.Err()
+}
+
+func insertDestinations(tx *sql.Tx, record *u.URLRecord) error {
+	for position, destination := range record.Destinations {
+		_, err := tx.Exec(
+			`INSERT INTO url_destinations (domain,short_id,position,variant,long_url,weight) VALUES ($1,$2,$3,$4,$5,$6)`,
+			record.Domain,
+			record.ShortID,
+			position,
+			destination.Variant,
+			destination.LongURL,
+			destination.Weight,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTagsAndMetadata sets the tags and metadata of the records with one query for each
+func (ur *DefaultURLRepository) loadTagsAndMetadata(records []*u.URLRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	byKey := make(map[string]*u.URLRecord, len(records))
+	domains := make([]string, 0, len(records))
+	shortIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		byKey[recordKey(record.Domain, record.ShortID)] = record
+		domains = append(domains, record.Domain)
+		shortIDs = append(shortIDs, record.ShortID)
+	}
+	const selected = `(domain, short_id) IN (SELECT * FROM unnest($1::text[], $2::text[]))`
+
+	rows, err := ur.db.Query(`SELECT domain, short_id, tag FROM url_tags WHERE `+selected+` ORDER BY tag`, pq.Array(domains), pq.Array(shortIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var domain, shortID, tag string
+		if err = rows.Scan(&domain, &shortID, &tag); err != nil {
+			return err
+		}
+		record := byKey[recordKey(domain, shortID)]
+		record.Tags = append(record.Tags, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	rows, err = ur.db.Query(`SELECT domain, short_id, key, value FROM url_metadata WHERE `+selected, pq.Array(domains), pq.Array(shortIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var domain, shortID, key, value string
+		if err = rows.Scan(&domain, &shortID, &key, &value); err != nil {
+			return err
+		}
+		record := byKey[recordKey(domain, shortID)]
+		if record.Metadata == nil {
+			record.Metadata = map[string]string{}
+		}
+		record.Metadata[key] = value
+	}
+	return rows.Err()
+}
+
+func insertTags(tx *sql.Tx, record *u.URLRecord) error {
+	for _, tag := range record.Tags {
+		if _, err := tx.Exec(`INSERT INTO url_tags (domain,short_id,tag) VALUES ($1,$2,$3)`, record.Domain, record.ShortID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertMetadata(tx *sql.Tx, record *u.URLRecord) error {
+	for key, value := range record.Metadata {
+		if _, err := tx.Exec(`INSERT INTO url_metadata (domain,short_id,key,value) VALUES ($1,$2,$3,$4)`, record.Domain, record.ShortID, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordKey identifies a record, since shortIds are only unique per domain
+func recordKey(domain string, shortID string) string {
+	return domain + "/" + shortID
+}
+
+// insertChildRows saves the parts of the record that are stored in their own tables
+func insertChildRows(tx *sql.Tx, record *u.URLRecord) error {
+	if err := insertTargetingRules(tx, record); err != nil {
+		return err
+	}
+	if err := insertDestinations(tx, record); err != nil {
+		return err
+	}
+	if err := insertTags(tx, record); err != nil {
+		return err
+	}
+	return insertMetadata(tx, record)
+}
+
+func (ur *DefaultURLRepository) inTransaction(f func(tx *sql.Tx) error) error {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err = f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
 func (ur *DefaultURLRepository) IsDup(err error) bool {
 	if pqError, ok := err.(*pq.Error); ok {
 		return pqError.Code.Name() == "unique_violation"
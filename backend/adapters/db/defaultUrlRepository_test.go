@@ -2,6 +2,7 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -11,9 +12,10 @@ import (
 	"time"
 )
 
+const savedDomain = "small.ml"
 const savedShortID = "shorty"
 const savedLongURL = "http://www.examply.com"
-const savedShortURL = "http://small.ml/" + savedShortID
+const savedShortURL = "http://" + savedDomain + "/" + savedShortID
 
 type URLRepositoryTestSuite struct {
 	suite.Suite
@@ -47,6 +49,7 @@ func (suite *URLRepositoryTestSuite) SetupTest() {
 
 	suite.record = &u.URLRecord{
 		LongURL:    savedLongURL,
+		Domain:     savedDomain,
 		ShortID:    savedShortID,
 		CreateTime: time.Now(),
 	}
@@ -79,14 +82,14 @@ func (suite *URLRepositoryTestSuite) TestFindExistingShortURL() {
 		panic(err)
 	}
 
-	result, err := suite.urlRepo.ShortURL(suite.record.LongURL)
+	result, err := suite.urlRepo.ShortURL(savedDomain, suite.record.LongURL)
 	expectation := result != nil && result.ShortID == suite.record.ShortID
 	assert.True(suite.T(), expectation, "Expected Matching ShortId '%s'. Got: '%v' (error: '%s')", suite.record.ShortID, result.ShortID, err)
 }
 
 func (suite *URLRepositoryTestSuite) TestFindAbsentShortURL() {
 
-	result, err := suite.urlRepo.ShortURL("http://www.nil.com")
+	result, err := suite.urlRepo.ShortURL(savedDomain, "http://www.nil.com")
 	assert.NotNil(suite.T(), err, "Expected err when shortId not found. Got: nil. (record: %v)", result)
 }
 
@@ -96,7 +99,7 @@ func (suite *URLRepositoryTestSuite) TestFindExistingLongURL() {
 		panic(err)
 	}
 
-	result, err := suite.urlRepo.LongURL(suite.record.ShortID)
+	result, err := suite.urlRepo.LongURL(savedDomain, suite.record.ShortID)
 	expectation := result != nil && result.LongURL == suite.record.LongURL
 
 	assert.True(suite.T(), expectation, "Expected Matching LongURL '%s'. Got: '%v' (error: '%s')", suite.record.LongURL, result.LongURL, err)
@@ -104,7 +107,200 @@ func (suite *URLRepositoryTestSuite) TestFindExistingLongURL() {
 
 func (suite *URLRepositoryTestSuite) TestFindAbsentLongURL() {
 
-	result, err := suite.urlRepo.LongURL("nil")
+	result, err := suite.urlRepo.LongURL(savedDomain, "nil")
 	assert.NotNil(suite.T(), err, "Expected err when longUrl not found. Got: nil. (record: %v)", result)
 
 }
+
+func (suite *URLRepositoryTestSuite) TestFindExistingShortURLForOwner() {
+	suite.record.Owner = "marketing"
+	_, err := suite.urlRepo.SaveRecord(suite.record)
+	if err != nil {
+		panic(err)
+	}
+
+	result, err := suite.urlRepo.ShortURLForOwner(savedDomain, suite.record.LongURL, "marketing")
+	expectation := result != nil && result.ShortID == suite.record.ShortID
+	assert.True(suite.T(), expectation, "Expected Matching ShortId '%s'. Got: '%v' (error: '%s')", suite.record.ShortID, result, err)
+
+	result, err = suite.urlRepo.ShortURLForOwner(savedDomain, suite.record.LongURL, "sales")
+	assert.NotNil(suite.T(), err, "Expected err when owner has no record. Got: nil. (record: %v)", result)
+}
+
+func (suite *URLRepositoryTestSuite) TestUpdateRecord() {
+	_, err := suite.urlRepo.SaveRecord(suite.record)
+	if err != nil {
+		panic(err)
+	}
+
+	suite.record.Disabled = true
+	suite.record.DisabledReason = "phishing"
+	err = suite.urlRepo.UpdateRecord(suite.record)
+	assert.Nil(suite.T(), err, "Expected: update record. Got: %s", err)
+
+	result, err := suite.urlRepo.LongURL(savedDomain, suite.record.ShortID)
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), result.Disabled)
+	assert.Equal(suite.T(), "phishing", result.DisabledReason)
+	assert.True(suite.T(), result.UpdateTime.After(result.CreateTime), "Expected update time %s to be after create time %s", result.UpdateTime, result.CreateTime)
+}
+
+func (suite *URLRepositoryTestSuite) TestUpdateAbsentRecordFails() {
+	err := suite.urlRepo.UpdateRecord(suite.record)
+	assert.NotNil(suite.T(), err, "Expected err when updating absent record. Got: nil")
+}
+
+func (suite *URLRepositoryTestSuite) TestTargetingRulesAreSavedInOrder() {
+	suite.record.TargetingRules = []u.TargetingRule{
+		{Platform: "ios", LongURL: "https://apps.apple.com/app/example"},
+		{Language: "ar", LongURL: "https://example.com/ar"},
+	}
+	_, err := suite.urlRepo.SaveRecord(suite.record)
+	if err != nil {
+		panic(err)
+	}
+
+	result, err := suite.urlRepo.LongURL(savedDomain, suite.record.ShortID)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), suite.record.TargetingRules, result.TargetingRules)
+
+	suite.record.TargetingRules = suite.record.TargetingRules[1:]
+	err = suite.urlRepo.UpdateRecord(suite.record)
+	assert.Nil(suite.T(), err)
+
+	result, err = suite.urlRepo.LongURL(savedDomain, suite.record.ShortID)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), suite.record.TargetingRules, result.TargetingRules)
+}
+
+func (suite *URLRepositoryTestSuite) TestSocialMetadataIsSaved() {
+	suite.record.Social = u.SocialMetadata{
+		Title:       "Example",
+		Description: "An example page",
+		ImageURL:    "https://example.com/card.png",
+	}
+	_, err := suite.urlRepo.SaveRecord(suite.record)
+	if err != nil {
+		panic(err)
+	}
+
+	result, err := suite.urlRepo.LongURL(savedDomain, suite.record.ShortID)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), suite.record.Social, result.Social)
+}
+
+func (suite *URLRepositoryTestSuite) TestSaveRecordsSkipsShortIDsInUse() {
+	suite.urlRepo.SaveRecord(suite.record)
+
+	records := []*u.URLRecord{
+		{LongURL: "https://example.com/1", Domain: savedDomain, ShortID: "batch1"},
+		{LongURL: "https://example.com/2", Domain: savedDomain, ShortID: suite.record.ShortID},
+		{LongURL: "https://example.com/3", Domain: savedDomain, ShortID: "batch3", Destinations: []u.Destination{
+			{Variant: "A", LongURL: "https://example.com/3a", Weight: 1},
+			{Variant: "B", LongURL: "https://example.com/3b", Weight: 1},
+		}},
+		{LongURL: "https://example.com/4", Domain: savedDomain, ShortID: "batch1"},
+	}
+
+	saved, err := suite.urlRepo.SaveRecords(records)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []*u.URLRecord{records[0], records[2]}, saved)
+
+	result, err := suite.urlRepo.LongURL(savedDomain, "batch3")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), records[2].Destinations, result.Destinations)
+
+	result, err = suite.urlRepo.LongURL(savedDomain, "batch1")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "https://example.com/1", result.LongURL)
+}
+
+func (suite *URLRepositoryTestSuite) TestSaveRecordsMoreThanOneStatementCanBind() {
+	records := make([]*u.URLRecord, maxRecordsPerInsert+1)
+	for i := range records {
+		records[i] = &u.URLRecord{LongURL: fmt.Sprintf("https://example.com/%d", i), Domain: savedDomain, ShortID: fmt.Sprintf("many%d", i)}
+	}
+
+	saved, err := suite.urlRepo.SaveRecords(records)
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), saved, len(records))
+
+	result, err := suite.urlRepo.LongURL(savedDomain, fmt.Sprintf("many%d", maxRecordsPerInsert))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), fmt.Sprintf("https://example.com/%d", maxRecordsPerInsert), result.LongURL)
+}
+
+func (suite *URLRepositoryTestSuite) TestListRecordsFiltersAndPages() {
+	suite.urlRepo.SaveRecords([]*u.URLRecord{
+		{LongURL: "https://Shop.example.com/summer-sale", Domain: savedDomain, ShortID: "list1", Owner: "alice"},
+		{LongURL: "https://shop.example.com/winter_sale", Domain: savedDomain, ShortID: "list2", Owner: "alice"},
+		{LongURL: "https://blog.example.com/sale", Domain: savedDomain, ShortID: "list3", Owner: "alice"},
+		{LongURL: "https://shop.example.com/sale", Domain: savedDomain, ShortID: "list4", Owner: "bob"},
+	})
+
+	page, err := suite.urlRepo.ListRecords(u.RecordQuery{Owner: "alice", Host: "SHOP.example.com", SortBy: u.SortByShortID, Limit: 1})
+	assert.Nil(suite.T(), err)
+	if assert.Len(suite.T(), page, 1) {
+		assert.Equal(suite.T(), "list1", page[0].ShortID)
+	}
+
+	position := u.PositionOf(page[0])
+	page, err = suite.urlRepo.ListRecords(u.RecordQuery{Owner: "alice", Host: "shop.example.com", SortBy: u.SortByShortID, After: &position, Limit: 1})
+	assert.Nil(suite.T(), err)
+	if assert.Len(suite.T(), page, 1) {
+		assert.Equal(suite.T(), "list2", page[0].ShortID)
+	}
+
+	page, err = suite.urlRepo.ListRecords(u.RecordQuery{Search: "R_SALE", SortBy: u.SortByLongURL, Descending: true})
+	assert.Nil(suite.T(), err)
+	if assert.Len(suite.T(), page, 1, "Expected '_' to be matched literally") {
+		assert.Equal(suite.T(), "list2", page[0].ShortID)
+	}
+
+	page, err = suite.urlRepo.ListRecords(u.RecordQuery{CreatedAfter: time.Now().Add(time.Hour)})
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), page)
+}
+
+func (suite *URLRepositoryTestSuite) TestTagsAndMetadataAreSavedAndFiltered() {
+	suite.record.Tags = []string{"email", "summer"}
+	suite.record.Metadata = map[string]string{"team": "growth"}
+	suite.urlRepo.SaveRecord(suite.record)
+	suite.urlRepo.SaveRecord(&u.URLRecord{LongURL: "https://example.com/winter", Domain: savedDomain, ShortID: "winter", Tags: []string{"email"}})
+
+	result, err := suite.urlRepo.LongURL(savedDomain, suite.record.ShortID)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), suite.record.Tags, result.Tags)
+	assert.Equal(suite.T(), suite.record.Metadata, result.Metadata)
+
+	page, err := suite.urlRepo.ListRecords(u.RecordQuery{Tags: []string{"email", "summer"}})
+	assert.Nil(suite.T(), err)
+	if assert.Len(suite.T(), page, 1) {
+		assert.Equal(suite.T(), suite.record.ShortID, page[0].ShortID)
+		assert.Equal(suite.T(), suite.record.Metadata, page[0].Metadata)
+	}
+
+	suite.record.Tags = nil
+	suite.record.Metadata = map[string]string{"team": "brand"}
+	assert.Nil(suite.T(), suite.urlRepo.UpdateRecord(suite.record))
+
+	result, err = suite.urlRepo.LongURL(savedDomain, suite.record.ShortID)
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), result.Tags)
+	assert.Equal(suite.T(), "brand", result.Metadata["team"])
+}
+
+func (suite *URLRepositoryTestSuite) TestSameShortIDOnDifferentDomains() {
+	suite.urlRepo.SaveRecord(suite.record)
+
+	_, err := suite.urlRepo.SaveRecord(&u.URLRecord{LongURL: "https://example.com/brand", Domain: "go.brand.com", ShortID: suite.record.ShortID})
+	assert.Nil(suite.T(), err, "Expected: save record on another domain. Got: %s", err)
+
+	result, err := suite.urlRepo.LongURL("go.brand.com", suite.record.ShortID)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "https://example.com/brand", result.LongURL)
+
+	result, err = suite.urlRepo.LongURL(savedDomain, suite.record.ShortID)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), savedLongURL, result.LongURL)
+}
@@ -5,29 +5,67 @@ import (
 	_ "github.com/lib/pq"
 	persistence "github.com/w-k-s/short-url/adapters/db"
 	"github.com/w-k-s/short-url/adapters/logging"
+	"github.com/w-k-s/short-url/adapters/qrcode"
+	"github.com/w-k-s/short-url/adapters/screening"
+	"github.com/w-k-s/short-url/adapters/social"
 	"github.com/w-k-s/short-url/adapters/web"
 	"github.com/w-k-s/short-url/config"
 	"github.com/w-k-s/short-url/domain/urlshortener"
 	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
 	"log"
+	"net"
 	"net/url"
 )
 
 var Db *sql.DB
 var urlRepo urlshortener.URLRepository
+var analyticsRepo urlshortener.AnalyticsRepository
+var idempotencyRepo urlshortener.IdempotencyRepository
 var baseURL *url.URL
 var ShortenURLUseCase *usecase.ShortenURLUseCase
+var BatchShortenURLUseCase *usecase.BatchShortenURLUseCase
+var ListURLsUseCase *usecase.ListURLsUseCase
 var RetrieveOriginalURLUseCase *usecase.RetrieveOriginalURLUseCase
+var UpdateURLUseCase *usecase.UpdateURLUseCase
+var ClickStatsUseCase *usecase.ClickStatsUseCase
+var QRCodeUseCase *usecase.QRCodeUseCase
+var urlScreener usecase.URLScreener
+var stopBlocklistWatch func()
+var stopIdempotencyCleanUp func()
 var LogRepository *logging.LogRepository
+var RedirectCachePolicy web.RedirectCachePolicy
+var CORSPolicy web.CORSPolicy
 var JsonFmt web.JsonFmt
+var ResponseFmt web.NegotiatingFmt
 
 func Init() {
 	initDB()
 	initURLRepository()
+	initAnalyticsRepository()
+	initIdempotencyRepository()
+	initURLScreener()
 	initShortenURLUseCase()
+	initBatchShortenURLUseCase()
+	initListURLsUseCase()
 	initRetrieveOriginalUseCase()
+	initUpdateURLUseCase()
+	initClickStatsUseCase()
+	initQRCodeUseCase()
 	initLogRepository()
+	initRedirectCachePolicy()
+	initCORSPolicy()
 	initJsonFmt()
+	initResponseFmt()
+}
+
+// Close stops the background work of the dependencies, e.g. reloading blocklists
+func Close() {
+	if stopBlocklistWatch != nil {
+		stopBlocklistWatch()
+	}
+	if stopIdempotencyCleanUp != nil {
+		stopIdempotencyCleanUp()
+	}
 }
 
 func initDB() {
@@ -43,20 +81,192 @@ func initURLRepository() {
 	urlRepo = persistence.NewURLRepository(Db)
 }
 
+func initAnalyticsRepository() {
+	analyticsRepo = persistence.NewAnalyticsRepository(Db)
+}
+
+func initIdempotencyRepository() {
+	if config.Settings.IdempotencyCleanUpInterval < 0 {
+		log.Fatalf("Invalid IDEMPOTENCY_CLEANUP_INTERVAL %q. Expected a duration of 0 or more; 0 does not delete expired keys", config.Settings.IdempotencyCleanUpInterval)
+	}
+	if config.Settings.IdempotencyCleanUpBatchSize <= 0 {
+		log.Fatalf("Invalid IDEMPOTENCY_CLEANUP_BATCH_SIZE %d. Expected a number greater than 0", config.Settings.IdempotencyCleanUpBatchSize)
+	}
+
+	repo := persistence.NewIdempotencyRepository(Db)
+	stopIdempotencyCleanUp = repo.CleanUp(config.Settings.IdempotencyCleanUpInterval, config.Settings.IdempotencyCleanUpBatchSize)
+	idempotencyRepo = repo
+}
+
+func initURLScreener() {
+	if len(config.Settings.BlocklistHostsFile) == 0 && len(config.Settings.BlocklistHashesFile) == 0 {
+		return
+	}
+
+	if config.Settings.BlocklistReloadInterval < 0 {
+		log.Fatalf("Invalid BLOCKLIST_RELOAD_INTERVAL %q. Expected a duration of 0 or more; 0 does not reload the blocklists", config.Settings.BlocklistReloadInterval)
+	}
+
+	screener, err := screening.NewBlocklistScreener(config.Settings.BlocklistHostsFile, config.Settings.BlocklistHashesFile)
+	if err != nil {
+		log.Fatalf("Failed to load blocklists: %s", err)
+	}
+	stopBlocklistWatch = screener.Watch(config.Settings.BlocklistReloadInterval)
+	urlScreener = screener
+}
+
 func initShortenURLUseCase() {
-	ShortenURLUseCase = usecase.NewShortenURLUseCase(urlRepo, config.Settings.GetBaseURL(), usecase.DefaultShortIDGenerator{})
+	options := []usecase.ShortenURLOption{
+		usecase.WithDestinationPolicy(destinationPolicy()),
+		usecase.WithSelfLinks(selfLinks()),
+		usecase.WithDomains(domains()),
+		usecase.WithIdempotency(idempotencyRepo, config.Settings.IdempotencyKeyTTL),
+	}
+	if urlScreener != nil {
+		options = append(options, usecase.WithURLScreener(urlScreener))
+	}
+	if config.Settings.FetchSocialMetadata {
+		client := social.NewClient(config.Settings.SocialMetadataTimeout, destinationPolicy().DeniedNetworks)
+		options = append(options, usecase.WithMetadataFetcher(social.NewMetadataFetcher(client)))
+	}
+
+	ShortenURLUseCase = usecase.NewShortenURLUseCase(
+		urlRepo,
+		config.Settings.GetBaseURL(),
+		usecase.DefaultShortIDGenerator{},
+		options...,
+	)
+}
+
+func initBatchShortenURLUseCase() {
+	BatchShortenURLUseCase = usecase.NewBatchShortenURLUseCase(
+		ShortenURLUseCase,
+		usecase.WithBatchConcurrency(config.Settings.BatchConcurrency),
+		usecase.WithBatchInsertSize(config.Settings.BatchInsertSize),
+		usecase.WithMaxBatchItems(config.Settings.MaxBatchItems),
+	)
+}
+
+func destinationPolicy() usecase.DestinationPolicy {
+	// The non-public networks are denied unless DENIED_NETWORKS is set
+	networks := config.Settings.GetDeniedNetworks()
+	if len(networks) == 0 {
+		networks = usecase.DefaultDeniedNetworks
+	}
+	deniedNetworks, err := usecase.ParseNetworks(networks)
+	if err != nil {
+		log.Fatalf("Failed to parse DENIED_NETWORKS %q: %s", config.Settings.DeniedNetworks, err)
+	}
+
+	policy := usecase.DestinationPolicy{
+		AllowedSchemes: config.Settings.GetAllowedSchemes(),
+		DeniedHosts:    config.Settings.GetDeniedHosts(),
+		DeniedNetworks: deniedNetworks,
+		MaxURLLength:   config.Settings.MaxURLLength,
+	}
+	if config.Settings.ResolveDestinationHosts {
+		policy.LookupIP = net.LookupIP
+	}
+	return policy
+}
+
+func domains() usecase.Domains {
+	return usecase.NewDomains(
+		config.Settings.GetBaseURL(),
+		config.Settings.GetBrandedDomains(),
+		config.Settings.GetAliasDomains(),
+	)
+}
+
+func selfLinks() usecase.SelfLinks {
+	policy := usecase.SelfLinkPolicy(config.Settings.SelfLinkPolicy)
+	if policy != usecase.SelfLinkResolve && policy != usecase.SelfLinkReject {
+		log.Fatalf("Invalid SELF_LINK_POLICY %q. Expected %q or %q", policy, usecase.SelfLinkResolve, usecase.SelfLinkReject)
+	}
+
+	return usecase.SelfLinks{
+		Domains:  config.Settings.GetDomains(),
+		Policy:   policy,
+		MaxDepth: config.Settings.MaxRedirectDepth,
+		Registry: domains(),
+	}
 }
 
 func initRetrieveOriginalUseCase() {
-	RetrieveOriginalURLUseCase = usecase.NewRetrieveOriginalURLUseCase(urlRepo)
+	if !usecase.IsRedirectStatus(config.Settings.RedirectStatus) {
+		log.Fatalf("Invalid REDIRECT_STATUS %d. Expected one of 301, 302, 303, 307 or 308", config.Settings.RedirectStatus)
+	}
+
+	options := []usecase.RetrieveOriginalURLOption{
+		usecase.WithSelfLinkDetection(selfLinks()),
+		usecase.WithDefaultRedirectStatus(config.Settings.RedirectStatus),
+		usecase.WithCountryHeader(config.Settings.CountryHeader),
+		usecase.WithClickTracking(analyticsRepo),
+		usecase.WithDomainLookup(domains()),
+	}
+	if urlScreener != nil && config.Settings.ScreenOnRedirect {
+		options = append(options, usecase.WithRedirectScreening(urlScreener))
+	}
+
+	RetrieveOriginalURLUseCase = usecase.NewRetrieveOriginalURLUseCase(urlRepo, options...)
+}
+
+func initListURLsUseCase() {
+	ListURLsUseCase = usecase.NewListURLsUseCase(urlRepo, domains())
+}
+
+func initUpdateURLUseCase() {
+	UpdateURLUseCase = usecase.NewUpdateURLUseCase(urlRepo, domains(), destinationPolicy(), urlScreener)
+}
+
+func initClickStatsUseCase() {
+	ClickStatsUseCase = usecase.NewClickStatsUseCase(urlRepo, domains(), analyticsRepo)
+}
+
+func initQRCodeUseCase() {
+	QRCodeUseCase = usecase.NewQRCodeUseCase(urlRepo, domains(), qrcode.Renderer{})
 }
 
 func initLogRepository() {
 	LogRepository = logging.NewLogRepository(Db)
 }
 
+func initRedirectCachePolicy() {
+	if config.Settings.PermanentRedirectMaxAge < 0 || config.Settings.TemporaryRedirectMaxAge < 0 {
+		log.Fatalf("Invalid PERMANENT_REDIRECT_MAX_AGE %q or TEMPORARY_REDIRECT_MAX_AGE %q. Expected durations of 0 or more", config.Settings.PermanentRedirectMaxAge, config.Settings.TemporaryRedirectMaxAge)
+	}
+
+	RedirectCachePolicy = web.RedirectCachePolicy{
+		PermanentMaxAge: config.Settings.PermanentRedirectMaxAge,
+		TemporaryMaxAge: config.Settings.TemporaryRedirectMaxAge,
+	}
+}
+
+func initCORSPolicy() {
+	origins := config.Settings.GetCORSAllowedOrigins()
+	for _, origin := range origins {
+		if origin == "*" && config.Settings.CORSAllowCredentials {
+			log.Fatalf("CORS_ALLOW_CREDENTIALS can not be used when CORS_ALLOWED_ORIGINS is '*'. List the origins that may send credentials instead")
+		}
+	}
+	if config.Settings.CORSMaxAge < 0 {
+		log.Fatalf("Invalid CORS_MAX_AGE %q. Expected a duration of 0 or more", config.Settings.CORSMaxAge)
+	}
+
+	CORSPolicy = web.CORSPolicy{
+		AllowedOrigins:   origins,
+		AllowedMethods:   config.Settings.GetCORSAllowedMethods(),
+		AllowedHeaders:   config.Settings.GetCORSAllowedHeaders(),
+		ExposedHeaders:   config.Settings.GetCORSExposedHeaders(),
+		AllowCredentials: config.Settings.CORSAllowCredentials,
+		MaxAge:           config.Settings.CORSMaxAge,
+	}
+}
+
 func initJsonFmt() {
-	JsonFmt = web.NewJsonFmtWithHeaders(map[string]string{
-		"Access-Control-Allow-Origin": config.Settings.AccessControlAllowOriginHeader,
-	})
+	JsonFmt = web.NewJsonFmt()
+}
+
+func initResponseFmt() {
+	ResponseFmt = web.NewNegotiatingFmt()
 }
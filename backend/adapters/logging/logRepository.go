@@ -52,7 +52,7 @@ func (lr *LogRepository) LogRequest(r *http.Request) *logRecord {
 
 func (lr *LogRepository) LogResponse(sw *StatusWriter, record *logRecord) error {
 	record.Status = sw.Status()
-	log.Printf(record.String())
+	log.Printf("%s", record)
 
 	_, err := lr.db.Exec(
 		`INSERT INTO logs (method,uri,ip_address,status,body,create_time) VALUES ($1,$2,$3,$4,$5,$6)`,
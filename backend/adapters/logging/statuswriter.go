@@ -27,3 +27,10 @@ func (w *StatusWriter) Status() int {
 	}
 	return w.status
 }
+
+// Flush sends streamed responses to the client as they are written, if the underlying ResponseWriter can be flushed
+func (w *StatusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
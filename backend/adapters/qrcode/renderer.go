@@ -0,0 +1,98 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"image"
+	"image/color"
+	"image/png"
+	"rsc.io/qr"
+)
+
+// Renderer draws qr codes as PNG or SVG images
+type Renderer struct{}
+
+func (r Renderer) Render(text string, options usecase.QRCodeOptions) ([]byte, error) {
+	code, err := qr.Encode(text, level(options.Level))
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Format == usecase.QRCodeSVG {
+		return renderSVG(code, options), nil
+	}
+	return renderPNG(code, options)
+}
+
+func level(level usecase.QRCodeLevel) qr.Level {
+	switch level {
+	case usecase.QRCodeLevelLow:
+		return qr.L
+	case usecase.QRCodeLevelQuartile:
+		return qr.Q
+	case usecase.QRCodeLevelHigh:
+		return qr.H
+	default:
+		return qr.M
+	}
+}
+
+// renderPNG scales each module to a whole number of pixels so that the code stays sharp.
+// Any pixels left over are added to the margin, keeping the code centered in an image of the requested size.
+func renderPNG(code *qr.Code, options usecase.QRCodeOptions) ([]byte, error) {
+	modules := code.Size + 2*options.Margin
+	scale := options.Size / modules
+	if scale < 1 {
+		return nil, fmt.Errorf("a size of %dpx is too small for a code of %d modules", options.Size, modules)
+	}
+	offset := (options.Size - scale*code.Size) / 2
+
+	img := image.NewPaletted(
+		image.Rect(0, 0, options.Size, options.Size),
+		color.Palette{options.Background, options.Foreground},
+	)
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if !code.Black(x, y) {
+				continue
+			}
+			for py := 0; py < scale; py++ {
+				row := img.Pix[(offset+y*scale+py)*img.Stride:]
+				for px := 0; px < scale; px++ {
+					row[offset+x*scale+px] = 1
+				}
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// renderSVG draws the code as a single path in a viewBox measured in modules, so that it scales to any size.
+func renderSVG(code *qr.Code, options usecase.QRCodeOptions) []byte {
+	modules := code.Size + 2*options.Margin
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(&buffer, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, options.Size, options.Size, modules, modules)
+	fmt.Fprintf(&buffer, `<rect width="100%%" height="100%%" fill="%s"/>`, hexColor(options.Background))
+	fmt.Fprintf(&buffer, `<path fill="%s" d="`, hexColor(options.Foreground))
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if code.Black(x, y) {
+				fmt.Fprintf(&buffer, "M%d %dh1v1h-1z", x+options.Margin, y+options.Margin)
+			}
+		}
+	}
+	buffer.WriteString(`"/></svg>`)
+	return buffer.Bytes()
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,56 @@
+package qrcode
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+var options = usecase.QRCodeOptions{
+	Format:     usecase.QRCodePNG,
+	Size:       256,
+	Level:      usecase.QRCodeLevelMedium,
+	Margin:     4,
+	Foreground: color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff},
+	Background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+}
+
+func TestRenderPNG(t *testing.T) {
+
+	image, err := Renderer{}.Render("https://small.ml/shrt", options)
+	assert.Nil(t, err)
+
+	decoded, err := png.Decode(bytes.NewReader(image))
+	if assert.Nil(t, err) {
+		bounds := decoded.Bounds()
+		assert.Equal(t, 256, bounds.Dx())
+		assert.Equal(t, 256, bounds.Dy())
+
+		// The corner of the image is in the margin; the top-left finder pattern starts right after it
+		// (version 2 codes are 25 modules wide: 256px / 33 modules = 7px per module, centered)
+		r, g, b, _ := decoded.At(0, 0).RGBA()
+		assert.Equal(t, []uint32{0xffff, 0xffff, 0xffff}, []uint32{r, g, b})
+		r, g, b, _ = decoded.At(128-25*7/2+1, 128-25*7/2+1).RGBA()
+		assert.Equal(t, []uint32{0x1a1a, 0x2b2b, 0x3c3c}, []uint32{r, g, b})
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+
+	svgOptions := options
+	svgOptions.Format = usecase.QRCodeSVG
+	svgOptions.Margin = 2
+
+	image, err := Renderer{}.Render("https://small.ml/shrt", svgOptions)
+	assert.Nil(t, err)
+
+	svg := string(image)
+	assert.True(t, strings.HasPrefix(svg, "<?xml"))
+	assert.Contains(t, svg, `width="256" height="256" viewBox="0 0 29 29"`)
+	assert.Contains(t, svg, `fill="#1a2b3c"`)
+	assert.Contains(t, svg, `M2 2h1v1h-1z`, "Expected the top-left finder pattern after the margin")
+}
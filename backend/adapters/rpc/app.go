@@ -0,0 +1,34 @@
+// Package rpc serves the gRPC API that is described by pb/shorturl.proto, on top of the same use cases as the REST API
+package rpc
+
+import (
+	"github.com/w-k-s/short-url/adapters/rpc/pb"
+	"github.com/w-k-s/short-url/log"
+	"google.golang.org/grpc"
+	"net"
+)
+
+type App struct {
+	server  *grpc.Server
+	address string
+}
+
+// Init creates the app. The gRPC API is served on its own address (e.g. `:9090`), separately from the REST API.
+func Init(listenAddress string, service pb.URLShortenerServer) *App {
+	server := grpc.NewServer()
+	pb.RegisterURLShortenerServer(server, service)
+
+	return &App{
+		server,
+		listenAddress,
+	}
+}
+
+func (a *App) ListenAndServe() error {
+	listener, err := net.Listen("tcp", a.address)
+	if err != nil {
+		return err
+	}
+	log.Printf("Serving gRPC on address: %s", a.address)
+	return a.server.Serve(listener)
+}
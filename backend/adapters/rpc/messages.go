@@ -0,0 +1,145 @@
+package rpc
+
+import (
+	"github.com/w-k-s/short-url/adapters/rpc/pb"
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+)
+
+// Conversions between the protobuf messages and the requests and responses of the use cases
+
+func shortenURLRequest(req *pb.ShortenRequest) usecase.ShortenURLRequest {
+	shortenReq := usecase.ShortenURLRequest{
+		LongURL:        req.LongUrl,
+		ShortID:        req.ShortId,
+		Owner:          req.Owner,
+		Dedupe:         usecase.DedupeMode(req.Dedupe),
+		RedirectStatus: int(req.RedirectStatus),
+		QueryMerge:     usecase.QueryMerge(req.QueryMerge),
+		Wildcard:       req.Wildcard,
+		Tags:           req.Tags,
+		Metadata:       req.Metadata,
+		Domain:         req.Domain,
+	}
+	if req.Utm != nil {
+		shortenReq.UTM = &usecase.UTMParameters{
+			Source:   req.Utm.Source,
+			Medium:   req.Utm.Medium,
+			Campaign: req.Utm.Campaign,
+			Term:     req.Utm.Term,
+			Content:  req.Utm.Content,
+		}
+	}
+	for _, rule := range req.Targeting {
+		shortenReq.Targeting = append(shortenReq.Targeting, usecase.TargetingRule{
+			Platform: usecase.Platform(rule.Platform),
+			Language: rule.Language,
+			Country:  rule.Country,
+			LongURL:  rule.LongUrl,
+		})
+	}
+	for _, destination := range req.Destinations {
+		shortenReq.Destinations = append(shortenReq.Destinations, usecase.Destination{
+			Variant: destination.Variant,
+			LongURL: destination.LongUrl,
+			Weight:  int(destination.Weight),
+		})
+	}
+	if req.Social != nil {
+		shortenReq.Social = &usecase.SocialMetadata{
+			Title:       req.Social.Title,
+			Description: req.Social.Description,
+			Image:       req.Social.Image,
+		}
+	}
+	return shortenReq
+}
+
+func shortenResponseMessage(response usecase.ShortenURLResponse) *pb.ShortenResponse {
+	message := &pb.ShortenResponse{
+		LongUrl:        response.LongURL,
+		ShortUrl:       response.ShortURL,
+		RedirectStatus: int32(response.RedirectStatus),
+		Social:         socialMessage(response.Social),
+		Tags:           response.Tags,
+		Metadata:       response.Metadata,
+	}
+	if response.UTM != nil {
+		message.Utm = &pb.UTMParameters{
+			Source:   response.UTM.Source,
+			Medium:   response.UTM.Medium,
+			Campaign: response.UTM.Campaign,
+			Term:     response.UTM.Term,
+			Content:  response.UTM.Content,
+		}
+	}
+	for _, rule := range response.Targeting {
+		message.Targeting = append(message.Targeting, &pb.TargetingRule{
+			Platform: string(rule.Platform),
+			Language: rule.Language,
+			Country:  rule.Country,
+			LongUrl:  rule.LongURL,
+		})
+	}
+	for _, destination := range response.Destinations {
+		message.Destinations = append(message.Destinations, &pb.Destination{
+			Variant: destination.Variant,
+			LongUrl: destination.LongURL,
+			Weight:  int32(destination.Weight),
+		})
+	}
+	return message
+}
+
+func resolveResponseMessage(response usecase.RetrieveOriginalURLResponse) *pb.ResolveResponse {
+	return &pb.ResolveResponse{
+		LongUrl:        response.LongURL,
+		ShortUrl:       response.ShortURL,
+		RedirectStatus: int32(response.RedirectStatus),
+		CreateTime:     response.CreateTime.Unix(),
+		Social:         socialMessage(response.Social),
+		Tags:           response.Tags,
+		Metadata:       response.Metadata,
+	}
+}
+
+func batchResultMessage(result usecase.BatchShortenURLResult) *pb.BatchShortenResult {
+	message := &pb.BatchShortenResult{Index: int32(result.Index)}
+	if result.Err != nil {
+		message.Result = &pb.BatchShortenResult_Error{Error: &pb.Error{
+			Code:    int32(result.Err.Code()),
+			Domain:  result.Err.Domain(),
+			Message: result.Err.Error(),
+			Fields:  result.Err.Fields(),
+		}}
+	} else {
+		message.Result = &pb.BatchShortenResult_Response{Response: shortenResponseMessage(result.Response)}
+	}
+	return message
+}
+
+func statsResponseMessage(response usecase.ClickStatsResponse) *pb.StatsResponse {
+	message := &pb.StatsResponse{
+		ShortId: response.ShortID,
+		Clicks:  response.Clicks,
+	}
+	for _, variant := range response.Variants {
+		message.Variants = append(message.Variants, &pb.VariantClicks{
+			Variant: variant.Variant,
+			LongUrl: variant.LongURL,
+			Weight:  int32(variant.Weight),
+			Clicks:  variant.Clicks,
+		})
+	}
+	return message
+}
+
+func socialMessage(social *usecase.SocialMetadata) *pb.SocialMetadata {
+	if social == nil {
+		return nil
+	}
+	return &pb.SocialMetadata{
+		Title:       social.Title,
+		Description: social.Description,
+		Image:       social.Image,
+	}
+}
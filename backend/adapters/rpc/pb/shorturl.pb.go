@@ -0,0 +1,1481 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: shorturl.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ShortenRequest struct {
+	LongUrl string `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	// short_id is a custom shortId. One is generated if it is empty.
+	ShortId string `protobuf:"bytes,2,opt,name=short_id,json=shortId,proto3" json:"short_id,omitempty"`
+	Owner   string `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	// dedupe is `global`, `per-owner` or `never`.
+	Dedupe         string `protobuf:"bytes,4,opt,name=dedupe,proto3" json:"dedupe,omitempty"`
+	RedirectStatus int32  `protobuf:"varint,5,opt,name=redirect_status,json=redirectStatus,proto3" json:"redirect_status,omitempty"`
+	// query_merge is `keep`, `replace` or `append`. The query string of visitors is not passed on if it is empty.
+	QueryMerge   string            `protobuf:"bytes,6,opt,name=query_merge,json=queryMerge,proto3" json:"query_merge,omitempty"`
+	Wildcard     bool              `protobuf:"varint,7,opt,name=wildcard,proto3" json:"wildcard,omitempty"`
+	Utm          *UTMParameters    `protobuf:"bytes,8,opt,name=utm,proto3" json:"utm,omitempty"`
+	Targeting    []*TargetingRule  `protobuf:"bytes,9,rep,name=targeting,proto3" json:"targeting,omitempty"`
+	Destinations []*Destination    `protobuf:"bytes,10,rep,name=destinations,proto3" json:"destinations,omitempty"`
+	Social       *SocialMetadata   `protobuf:"bytes,11,opt,name=social,proto3" json:"social,omitempty"`
+	Tags         []string          `protobuf:"bytes,12,rep,name=tags,proto3" json:"tags,omitempty"`
+	Metadata     map[string]string `protobuf:"bytes,13,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// domain is the host of the branded domain to shorten the url on. The domain of the base url is used if it is empty.
+	Domain               string   `protobuf:"bytes,14,opt,name=domain,proto3" json:"domain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShortenRequest) Reset()         { *m = ShortenRequest{} }
+func (m *ShortenRequest) String() string { return proto.CompactTextString(m) }
+func (*ShortenRequest) ProtoMessage()    {}
+func (*ShortenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{0}
+}
+
+func (m *ShortenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShortenRequest.Unmarshal(m, b)
+}
+func (m *ShortenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShortenRequest.Marshal(b, m, deterministic)
+}
+func (m *ShortenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShortenRequest.Merge(m, src)
+}
+func (m *ShortenRequest) XXX_Size() int {
+	return xxx_messageInfo_ShortenRequest.Size(m)
+}
+func (m *ShortenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShortenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShortenRequest proto.InternalMessageInfo
+
+func (m *ShortenRequest) GetLongUrl() string {
+	if m != nil {
+		return m.LongUrl
+	}
+	return ""
+}
+
+func (m *ShortenRequest) GetShortId() string {
+	if m != nil {
+		return m.ShortId
+	}
+	return ""
+}
+
+func (m *ShortenRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *ShortenRequest) GetDedupe() string {
+	if m != nil {
+		return m.Dedupe
+	}
+	return ""
+}
+
+func (m *ShortenRequest) GetRedirectStatus() int32 {
+	if m != nil {
+		return m.RedirectStatus
+	}
+	return 0
+}
+
+func (m *ShortenRequest) GetQueryMerge() string {
+	if m != nil {
+		return m.QueryMerge
+	}
+	return ""
+}
+
+func (m *ShortenRequest) GetWildcard() bool {
+	if m != nil {
+		return m.Wildcard
+	}
+	return false
+}
+
+func (m *ShortenRequest) GetUtm() *UTMParameters {
+	if m != nil {
+		return m.Utm
+	}
+	return nil
+}
+
+func (m *ShortenRequest) GetTargeting() []*TargetingRule {
+	if m != nil {
+		return m.Targeting
+	}
+	return nil
+}
+
+func (m *ShortenRequest) GetDestinations() []*Destination {
+	if m != nil {
+		return m.Destinations
+	}
+	return nil
+}
+
+func (m *ShortenRequest) GetSocial() *SocialMetadata {
+	if m != nil {
+		return m.Social
+	}
+	return nil
+}
+
+func (m *ShortenRequest) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *ShortenRequest) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *ShortenRequest) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+type ShortenResponse struct {
+	LongUrl              string            `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	ShortUrl             string            `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	RedirectStatus       int32             `protobuf:"varint,3,opt,name=redirect_status,json=redirectStatus,proto3" json:"redirect_status,omitempty"`
+	Utm                  *UTMParameters    `protobuf:"bytes,4,opt,name=utm,proto3" json:"utm,omitempty"`
+	Targeting            []*TargetingRule  `protobuf:"bytes,5,rep,name=targeting,proto3" json:"targeting,omitempty"`
+	Destinations         []*Destination    `protobuf:"bytes,6,rep,name=destinations,proto3" json:"destinations,omitempty"`
+	Social               *SocialMetadata   `protobuf:"bytes,7,opt,name=social,proto3" json:"social,omitempty"`
+	Tags                 []string          `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	Metadata             map[string]string `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ShortenResponse) Reset()         { *m = ShortenResponse{} }
+func (m *ShortenResponse) String() string { return proto.CompactTextString(m) }
+func (*ShortenResponse) ProtoMessage()    {}
+func (*ShortenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{1}
+}
+
+func (m *ShortenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShortenResponse.Unmarshal(m, b)
+}
+func (m *ShortenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShortenResponse.Marshal(b, m, deterministic)
+}
+func (m *ShortenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShortenResponse.Merge(m, src)
+}
+func (m *ShortenResponse) XXX_Size() int {
+	return xxx_messageInfo_ShortenResponse.Size(m)
+}
+func (m *ShortenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShortenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShortenResponse proto.InternalMessageInfo
+
+func (m *ShortenResponse) GetLongUrl() string {
+	if m != nil {
+		return m.LongUrl
+	}
+	return ""
+}
+
+func (m *ShortenResponse) GetShortUrl() string {
+	if m != nil {
+		return m.ShortUrl
+	}
+	return ""
+}
+
+func (m *ShortenResponse) GetRedirectStatus() int32 {
+	if m != nil {
+		return m.RedirectStatus
+	}
+	return 0
+}
+
+func (m *ShortenResponse) GetUtm() *UTMParameters {
+	if m != nil {
+		return m.Utm
+	}
+	return nil
+}
+
+func (m *ShortenResponse) GetTargeting() []*TargetingRule {
+	if m != nil {
+		return m.Targeting
+	}
+	return nil
+}
+
+func (m *ShortenResponse) GetDestinations() []*Destination {
+	if m != nil {
+		return m.Destinations
+	}
+	return nil
+}
+
+func (m *ShortenResponse) GetSocial() *SocialMetadata {
+	if m != nil {
+		return m.Social
+	}
+	return nil
+}
+
+func (m *ShortenResponse) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *ShortenResponse) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type UTMParameters struct {
+	Source               string   `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Medium               string   `protobuf:"bytes,2,opt,name=medium,proto3" json:"medium,omitempty"`
+	Campaign             string   `protobuf:"bytes,3,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	Term                 string   `protobuf:"bytes,4,opt,name=term,proto3" json:"term,omitempty"`
+	Content              string   `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UTMParameters) Reset()         { *m = UTMParameters{} }
+func (m *UTMParameters) String() string { return proto.CompactTextString(m) }
+func (*UTMParameters) ProtoMessage()    {}
+func (*UTMParameters) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{2}
+}
+
+func (m *UTMParameters) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UTMParameters.Unmarshal(m, b)
+}
+func (m *UTMParameters) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UTMParameters.Marshal(b, m, deterministic)
+}
+func (m *UTMParameters) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UTMParameters.Merge(m, src)
+}
+func (m *UTMParameters) XXX_Size() int {
+	return xxx_messageInfo_UTMParameters.Size(m)
+}
+func (m *UTMParameters) XXX_DiscardUnknown() {
+	xxx_messageInfo_UTMParameters.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UTMParameters proto.InternalMessageInfo
+
+func (m *UTMParameters) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *UTMParameters) GetMedium() string {
+	if m != nil {
+		return m.Medium
+	}
+	return ""
+}
+
+func (m *UTMParameters) GetCampaign() string {
+	if m != nil {
+		return m.Campaign
+	}
+	return ""
+}
+
+func (m *UTMParameters) GetTerm() string {
+	if m != nil {
+		return m.Term
+	}
+	return ""
+}
+
+func (m *UTMParameters) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+type TargetingRule struct {
+	Platform             string   `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`
+	Language             string   `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Country              string   `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	LongUrl              string   `protobuf:"bytes,4,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TargetingRule) Reset()         { *m = TargetingRule{} }
+func (m *TargetingRule) String() string { return proto.CompactTextString(m) }
+func (*TargetingRule) ProtoMessage()    {}
+func (*TargetingRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{3}
+}
+
+func (m *TargetingRule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TargetingRule.Unmarshal(m, b)
+}
+func (m *TargetingRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TargetingRule.Marshal(b, m, deterministic)
+}
+func (m *TargetingRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TargetingRule.Merge(m, src)
+}
+func (m *TargetingRule) XXX_Size() int {
+	return xxx_messageInfo_TargetingRule.Size(m)
+}
+func (m *TargetingRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_TargetingRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TargetingRule proto.InternalMessageInfo
+
+func (m *TargetingRule) GetPlatform() string {
+	if m != nil {
+		return m.Platform
+	}
+	return ""
+}
+
+func (m *TargetingRule) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
+func (m *TargetingRule) GetCountry() string {
+	if m != nil {
+		return m.Country
+	}
+	return ""
+}
+
+func (m *TargetingRule) GetLongUrl() string {
+	if m != nil {
+		return m.LongUrl
+	}
+	return ""
+}
+
+type Destination struct {
+	Variant              string   `protobuf:"bytes,1,opt,name=variant,proto3" json:"variant,omitempty"`
+	LongUrl              string   `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	Weight               int32    `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Destination) Reset()         { *m = Destination{} }
+func (m *Destination) String() string { return proto.CompactTextString(m) }
+func (*Destination) ProtoMessage()    {}
+func (*Destination) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{4}
+}
+
+func (m *Destination) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Destination.Unmarshal(m, b)
+}
+func (m *Destination) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Destination.Marshal(b, m, deterministic)
+}
+func (m *Destination) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Destination.Merge(m, src)
+}
+func (m *Destination) XXX_Size() int {
+	return xxx_messageInfo_Destination.Size(m)
+}
+func (m *Destination) XXX_DiscardUnknown() {
+	xxx_messageInfo_Destination.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Destination proto.InternalMessageInfo
+
+func (m *Destination) GetVariant() string {
+	if m != nil {
+		return m.Variant
+	}
+	return ""
+}
+
+func (m *Destination) GetLongUrl() string {
+	if m != nil {
+		return m.LongUrl
+	}
+	return ""
+}
+
+func (m *Destination) GetWeight() int32 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
+type SocialMetadata struct {
+	Title                string   `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description          string   `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Image                string   `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SocialMetadata) Reset()         { *m = SocialMetadata{} }
+func (m *SocialMetadata) String() string { return proto.CompactTextString(m) }
+func (*SocialMetadata) ProtoMessage()    {}
+func (*SocialMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{5}
+}
+
+func (m *SocialMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SocialMetadata.Unmarshal(m, b)
+}
+func (m *SocialMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SocialMetadata.Marshal(b, m, deterministic)
+}
+func (m *SocialMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SocialMetadata.Merge(m, src)
+}
+func (m *SocialMetadata) XXX_Size() int {
+	return xxx_messageInfo_SocialMetadata.Size(m)
+}
+func (m *SocialMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_SocialMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SocialMetadata proto.InternalMessageInfo
+
+func (m *SocialMetadata) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *SocialMetadata) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *SocialMetadata) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+type ResolveRequest struct {
+	// Types that are valid to be assigned to Link:
+	//	*ResolveRequest_ShortUrl
+	//	*ResolveRequest_ShortId
+	Link                 isResolveRequest_Link `protobuf_oneof:"link"`
+	Domain               string                `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ResolveRequest) Reset()         { *m = ResolveRequest{} }
+func (m *ResolveRequest) String() string { return proto.CompactTextString(m) }
+func (*ResolveRequest) ProtoMessage()    {}
+func (*ResolveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{6}
+}
+
+func (m *ResolveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResolveRequest.Unmarshal(m, b)
+}
+func (m *ResolveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResolveRequest.Marshal(b, m, deterministic)
+}
+func (m *ResolveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResolveRequest.Merge(m, src)
+}
+func (m *ResolveRequest) XXX_Size() int {
+	return xxx_messageInfo_ResolveRequest.Size(m)
+}
+func (m *ResolveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResolveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResolveRequest proto.InternalMessageInfo
+
+type isResolveRequest_Link interface {
+	isResolveRequest_Link()
+}
+
+type ResolveRequest_ShortUrl struct {
+	ShortUrl string `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3,oneof"`
+}
+
+type ResolveRequest_ShortId struct {
+	ShortId string `protobuf:"bytes,2,opt,name=short_id,json=shortId,proto3,oneof"`
+}
+
+func (*ResolveRequest_ShortUrl) isResolveRequest_Link() {}
+
+func (*ResolveRequest_ShortId) isResolveRequest_Link() {}
+
+func (m *ResolveRequest) GetLink() isResolveRequest_Link {
+	if m != nil {
+		return m.Link
+	}
+	return nil
+}
+
+func (m *ResolveRequest) GetShortUrl() string {
+	if x, ok := m.GetLink().(*ResolveRequest_ShortUrl); ok {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (m *ResolveRequest) GetShortId() string {
+	if x, ok := m.GetLink().(*ResolveRequest_ShortId); ok {
+		return x.ShortId
+	}
+	return ""
+}
+
+func (m *ResolveRequest) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ResolveRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ResolveRequest_ShortUrl)(nil),
+		(*ResolveRequest_ShortId)(nil),
+	}
+}
+
+type ResolveResponse struct {
+	LongUrl        string `protobuf:"bytes,1,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	ShortUrl       string `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	RedirectStatus int32  `protobuf:"varint,3,opt,name=redirect_status,json=redirectStatus,proto3" json:"redirect_status,omitempty"`
+	// create_time is the number of seconds since the Unix epoch.
+	CreateTime           int64             `protobuf:"varint,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	Social               *SocialMetadata   `protobuf:"bytes,5,opt,name=social,proto3" json:"social,omitempty"`
+	Tags                 []string          `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Metadata             map[string]string `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ResolveResponse) Reset()         { *m = ResolveResponse{} }
+func (m *ResolveResponse) String() string { return proto.CompactTextString(m) }
+func (*ResolveResponse) ProtoMessage()    {}
+func (*ResolveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{7}
+}
+
+func (m *ResolveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResolveResponse.Unmarshal(m, b)
+}
+func (m *ResolveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResolveResponse.Marshal(b, m, deterministic)
+}
+func (m *ResolveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResolveResponse.Merge(m, src)
+}
+func (m *ResolveResponse) XXX_Size() int {
+	return xxx_messageInfo_ResolveResponse.Size(m)
+}
+func (m *ResolveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResolveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResolveResponse proto.InternalMessageInfo
+
+func (m *ResolveResponse) GetLongUrl() string {
+	if m != nil {
+		return m.LongUrl
+	}
+	return ""
+}
+
+func (m *ResolveResponse) GetShortUrl() string {
+	if m != nil {
+		return m.ShortUrl
+	}
+	return ""
+}
+
+func (m *ResolveResponse) GetRedirectStatus() int32 {
+	if m != nil {
+		return m.RedirectStatus
+	}
+	return 0
+}
+
+func (m *ResolveResponse) GetCreateTime() int64 {
+	if m != nil {
+		return m.CreateTime
+	}
+	return 0
+}
+
+func (m *ResolveResponse) GetSocial() *SocialMetadata {
+	if m != nil {
+		return m.Social
+	}
+	return nil
+}
+
+func (m *ResolveResponse) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *ResolveResponse) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type BatchShortenResult struct {
+	// index is the position of the url in the stream, starting at 0.
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//	*BatchShortenResult_Response
+	//	*BatchShortenResult_Error
+	Result               isBatchShortenResult_Result `protobuf_oneof:"result"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *BatchShortenResult) Reset()         { *m = BatchShortenResult{} }
+func (m *BatchShortenResult) String() string { return proto.CompactTextString(m) }
+func (*BatchShortenResult) ProtoMessage()    {}
+func (*BatchShortenResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{8}
+}
+
+func (m *BatchShortenResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchShortenResult.Unmarshal(m, b)
+}
+func (m *BatchShortenResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchShortenResult.Marshal(b, m, deterministic)
+}
+func (m *BatchShortenResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchShortenResult.Merge(m, src)
+}
+func (m *BatchShortenResult) XXX_Size() int {
+	return xxx_messageInfo_BatchShortenResult.Size(m)
+}
+func (m *BatchShortenResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchShortenResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchShortenResult proto.InternalMessageInfo
+
+func (m *BatchShortenResult) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type isBatchShortenResult_Result interface {
+	isBatchShortenResult_Result()
+}
+
+type BatchShortenResult_Response struct {
+	Response *ShortenResponse `protobuf:"bytes,2,opt,name=response,proto3,oneof"`
+}
+
+type BatchShortenResult_Error struct {
+	Error *Error `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchShortenResult_Response) isBatchShortenResult_Result() {}
+
+func (*BatchShortenResult_Error) isBatchShortenResult_Result() {}
+
+func (m *BatchShortenResult) GetResult() isBatchShortenResult_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *BatchShortenResult) GetResponse() *ShortenResponse {
+	if x, ok := m.GetResult().(*BatchShortenResult_Response); ok {
+		return x.Response
+	}
+	return nil
+}
+
+func (m *BatchShortenResult) GetError() *Error {
+	if x, ok := m.GetResult().(*BatchShortenResult_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*BatchShortenResult) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*BatchShortenResult_Response)(nil),
+		(*BatchShortenResult_Error)(nil),
+	}
+}
+
+// Error is a domain error, with the same fields as an error of the REST API.
+type Error struct {
+	Code                 int32             `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Domain               string            `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	Message              string            `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Fields               map[string]string `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Error) Reset()         { *m = Error{} }
+func (m *Error) String() string { return proto.CompactTextString(m) }
+func (*Error) ProtoMessage()    {}
+func (*Error) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{9}
+}
+
+func (m *Error) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Error.Unmarshal(m, b)
+}
+func (m *Error) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Error.Marshal(b, m, deterministic)
+}
+func (m *Error) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Error.Merge(m, src)
+}
+func (m *Error) XXX_Size() int {
+	return xxx_messageInfo_Error.Size(m)
+}
+func (m *Error) XXX_DiscardUnknown() {
+	xxx_messageInfo_Error.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Error proto.InternalMessageInfo
+
+func (m *Error) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *Error) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *Error) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Error) GetFields() map[string]string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+type DeleteRequest struct {
+	ShortId string `protobuf:"bytes,1,opt,name=short_id,json=shortId,proto3" json:"short_id,omitempty"`
+	// domain is the host of the domain the shortId is on. The domain of the base url is used if it is empty.
+	Domain string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// reason is shown to visitors of the disabled url.
+	Reason               string   `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
+func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteRequest) ProtoMessage()    {}
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{10}
+}
+
+func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteRequest.Unmarshal(m, b)
+}
+func (m *DeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteRequest.Merge(m, src)
+}
+func (m *DeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteRequest.Size(m)
+}
+func (m *DeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteRequest proto.InternalMessageInfo
+
+func (m *DeleteRequest) GetShortId() string {
+	if m != nil {
+		return m.ShortId
+	}
+	return ""
+}
+
+func (m *DeleteRequest) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *DeleteRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type DeleteResponse struct {
+	ShortId              string   `protobuf:"bytes,1,opt,name=short_id,json=shortId,proto3" json:"short_id,omitempty"`
+	LongUrl              string   `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
+func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteResponse) ProtoMessage()    {}
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{11}
+}
+
+func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteResponse.Unmarshal(m, b)
+}
+func (m *DeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteResponse.Merge(m, src)
+}
+func (m *DeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteResponse.Size(m)
+}
+func (m *DeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteResponse proto.InternalMessageInfo
+
+func (m *DeleteResponse) GetShortId() string {
+	if m != nil {
+		return m.ShortId
+	}
+	return ""
+}
+
+func (m *DeleteResponse) GetLongUrl() string {
+	if m != nil {
+		return m.LongUrl
+	}
+	return ""
+}
+
+type StatsRequest struct {
+	ShortId string `protobuf:"bytes,1,opt,name=short_id,json=shortId,proto3" json:"short_id,omitempty"`
+	// domain is the host of the domain the shortId is on. The domain of the base url is used if it is empty.
+	Domain               string   `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatsRequest) Reset()         { *m = StatsRequest{} }
+func (m *StatsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatsRequest) ProtoMessage()    {}
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{12}
+}
+
+func (m *StatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsRequest.Unmarshal(m, b)
+}
+func (m *StatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsRequest.Marshal(b, m, deterministic)
+}
+func (m *StatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsRequest.Merge(m, src)
+}
+func (m *StatsRequest) XXX_Size() int {
+	return xxx_messageInfo_StatsRequest.Size(m)
+}
+func (m *StatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsRequest proto.InternalMessageInfo
+
+func (m *StatsRequest) GetShortId() string {
+	if m != nil {
+		return m.ShortId
+	}
+	return ""
+}
+
+func (m *StatsRequest) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+type StatsResponse struct {
+	ShortId              string           `protobuf:"bytes,1,opt,name=short_id,json=shortId,proto3" json:"short_id,omitempty"`
+	Clicks               int64            `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	Variants             []*VariantClicks `protobuf:"bytes,3,rep,name=variants,proto3" json:"variants,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
+func (m *StatsResponse) String() string { return proto.CompactTextString(m) }
+func (*StatsResponse) ProtoMessage()    {}
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{13}
+}
+
+func (m *StatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsResponse.Unmarshal(m, b)
+}
+func (m *StatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsResponse.Marshal(b, m, deterministic)
+}
+func (m *StatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsResponse.Merge(m, src)
+}
+func (m *StatsResponse) XXX_Size() int {
+	return xxx_messageInfo_StatsResponse.Size(m)
+}
+func (m *StatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsResponse proto.InternalMessageInfo
+
+func (m *StatsResponse) GetShortId() string {
+	if m != nil {
+		return m.ShortId
+	}
+	return ""
+}
+
+func (m *StatsResponse) GetClicks() int64 {
+	if m != nil {
+		return m.Clicks
+	}
+	return 0
+}
+
+func (m *StatsResponse) GetVariants() []*VariantClicks {
+	if m != nil {
+		return m.Variants
+	}
+	return nil
+}
+
+type VariantClicks struct {
+	Variant              string   `protobuf:"bytes,1,opt,name=variant,proto3" json:"variant,omitempty"`
+	LongUrl              string   `protobuf:"bytes,2,opt,name=long_url,json=longUrl,proto3" json:"long_url,omitempty"`
+	Weight               int32    `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	Clicks               int64    `protobuf:"varint,4,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VariantClicks) Reset()         { *m = VariantClicks{} }
+func (m *VariantClicks) String() string { return proto.CompactTextString(m) }
+func (*VariantClicks) ProtoMessage()    {}
+func (*VariantClicks) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b15b647f658c2820, []int{14}
+}
+
+func (m *VariantClicks) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VariantClicks.Unmarshal(m, b)
+}
+func (m *VariantClicks) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VariantClicks.Marshal(b, m, deterministic)
+}
+func (m *VariantClicks) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VariantClicks.Merge(m, src)
+}
+func (m *VariantClicks) XXX_Size() int {
+	return xxx_messageInfo_VariantClicks.Size(m)
+}
+func (m *VariantClicks) XXX_DiscardUnknown() {
+	xxx_messageInfo_VariantClicks.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VariantClicks proto.InternalMessageInfo
+
+func (m *VariantClicks) GetVariant() string {
+	if m != nil {
+		return m.Variant
+	}
+	return ""
+}
+
+func (m *VariantClicks) GetLongUrl() string {
+	if m != nil {
+		return m.LongUrl
+	}
+	return ""
+}
+
+func (m *VariantClicks) GetWeight() int32 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
+func (m *VariantClicks) GetClicks() int64 {
+	if m != nil {
+		return m.Clicks
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ShortenRequest)(nil), "shorturl.v1.ShortenRequest")
+	proto.RegisterMapType((map[string]string)(nil), "shorturl.v1.ShortenRequest.MetadataEntry")
+	proto.RegisterType((*ShortenResponse)(nil), "shorturl.v1.ShortenResponse")
+	proto.RegisterMapType((map[string]string)(nil), "shorturl.v1.ShortenResponse.MetadataEntry")
+	proto.RegisterType((*UTMParameters)(nil), "shorturl.v1.UTMParameters")
+	proto.RegisterType((*TargetingRule)(nil), "shorturl.v1.TargetingRule")
+	proto.RegisterType((*Destination)(nil), "shorturl.v1.Destination")
+	proto.RegisterType((*SocialMetadata)(nil), "shorturl.v1.SocialMetadata")
+	proto.RegisterType((*ResolveRequest)(nil), "shorturl.v1.ResolveRequest")
+	proto.RegisterType((*ResolveResponse)(nil), "shorturl.v1.ResolveResponse")
+	proto.RegisterMapType((map[string]string)(nil), "shorturl.v1.ResolveResponse.MetadataEntry")
+	proto.RegisterType((*BatchShortenResult)(nil), "shorturl.v1.BatchShortenResult")
+	proto.RegisterType((*Error)(nil), "shorturl.v1.Error")
+	proto.RegisterMapType((map[string]string)(nil), "shorturl.v1.Error.FieldsEntry")
+	proto.RegisterType((*DeleteRequest)(nil), "shorturl.v1.DeleteRequest")
+	proto.RegisterType((*DeleteResponse)(nil), "shorturl.v1.DeleteResponse")
+	proto.RegisterType((*StatsRequest)(nil), "shorturl.v1.StatsRequest")
+	proto.RegisterType((*StatsResponse)(nil), "shorturl.v1.StatsResponse")
+	proto.RegisterType((*VariantClicks)(nil), "shorturl.v1.VariantClicks")
+}
+
+func init() { proto.RegisterFile("shorturl.proto", fileDescriptor_b15b647f658c2820) }
+
+var fileDescriptor_b15b647f658c2820 = []byte{
+	// 1087 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0xdd, 0x6e, 0xe3, 0x44,
+	0x14, 0x5e, 0xe7, 0xc7, 0x49, 0x4e, 0x9a, 0x14, 0x8d, 0x50, 0xe5, 0x4d, 0x17, 0x1a, 0xf9, 0x86,
+	0xb0, 0xa2, 0x09, 0x74, 0xa5, 0xd5, 0xb2, 0x8b, 0x90, 0xb6, 0xb4, 0x55, 0x91, 0xa8, 0xb4, 0x72,
+	0x5b, 0x2e, 0x7a, 0x41, 0x35, 0xb5, 0xcf, 0xa6, 0xa3, 0xfa, 0x27, 0x3b, 0x33, 0x6e, 0x29, 0xe2,
+	0x05, 0x78, 0x07, 0x1e, 0x86, 0x57, 0xe1, 0x02, 0x6e, 0x79, 0x05, 0x34, 0xe3, 0xb1, 0x63, 0xb7,
+	0x69, 0x50, 0xbb, 0xc0, 0x5d, 0xbe, 0xf3, 0xf3, 0xf9, 0xcc, 0x9c, 0xef, 0x1c, 0x3b, 0xd0, 0x17,
+	0xe7, 0x09, 0x97, 0x29, 0x0f, 0xc7, 0x33, 0x9e, 0xc8, 0x84, 0x74, 0x0b, 0x7c, 0xf9, 0x85, 0xfb,
+	0x47, 0x03, 0xfa, 0x87, 0x0a, 0x63, 0xec, 0xe1, 0xbb, 0x14, 0x85, 0x24, 0x8f, 0xa1, 0x1d, 0x26,
+	0xf1, 0xf4, 0x34, 0xe5, 0xa1, 0x63, 0x0d, 0xad, 0x51, 0xc7, 0x6b, 0x29, 0x7c, 0xcc, 0x43, 0xe5,
+	0xd2, 0xc9, 0xa7, 0x2c, 0x70, 0x6a, 0x99, 0x4b, 0xe3, 0x6f, 0x03, 0xf2, 0x21, 0x34, 0x93, 0xab,
+	0x18, 0xb9, 0x53, 0xd7, 0xf6, 0x0c, 0x90, 0x35, 0xb0, 0x03, 0x0c, 0xd2, 0x19, 0x3a, 0x0d, 0x6d,
+	0x36, 0x88, 0x7c, 0x02, 0xab, 0x1c, 0x03, 0xc6, 0xd1, 0x97, 0xa7, 0x42, 0x52, 0x99, 0x0a, 0xa7,
+	0x39, 0xb4, 0x46, 0x4d, 0xaf, 0x9f, 0x9b, 0x0f, 0xb5, 0x95, 0x6c, 0x40, 0xf7, 0x5d, 0x8a, 0xfc,
+	0xfa, 0x34, 0x42, 0x3e, 0x45, 0xc7, 0xd6, 0x2c, 0xa0, 0x4d, 0x07, 0xca, 0x42, 0x06, 0xd0, 0xbe,
+	0x62, 0x61, 0xe0, 0x53, 0x1e, 0x38, 0xad, 0xa1, 0x35, 0x6a, 0x7b, 0x05, 0x26, 0x9f, 0x41, 0x3d,
+	0x95, 0x91, 0xd3, 0x1e, 0x5a, 0xa3, 0xee, 0xd6, 0x60, 0x5c, 0x3a, 0xf7, 0xf8, 0xf8, 0xe8, 0xe0,
+	0x0d, 0xe5, 0x34, 0x42, 0x89, 0x5c, 0x78, 0x2a, 0x8c, 0xbc, 0x80, 0x8e, 0xa4, 0x7c, 0x8a, 0x92,
+	0xc5, 0x53, 0xa7, 0x33, 0xac, 0xdf, 0xca, 0x39, 0xca, 0xbd, 0x5e, 0x1a, 0xa2, 0x37, 0x0f, 0x26,
+	0x5f, 0xc1, 0x4a, 0x80, 0x42, 0xb2, 0x98, 0x4a, 0x96, 0xc4, 0xc2, 0x01, 0x9d, 0xec, 0x54, 0x92,
+	0x77, 0xe6, 0x01, 0x5e, 0x25, 0x9a, 0x3c, 0x03, 0x5b, 0x24, 0x3e, 0xa3, 0xa1, 0xd3, 0xd5, 0x85,
+	0xae, 0x57, 0xf2, 0x0e, 0xb5, 0xeb, 0x00, 0x25, 0x0d, 0xa8, 0xa4, 0x9e, 0x09, 0x25, 0x04, 0x1a,
+	0x92, 0x4e, 0x85, 0xb3, 0x32, 0xac, 0x8f, 0x3a, 0x9e, 0xfe, 0x4d, 0x76, 0xa1, 0x1d, 0x99, 0x38,
+	0xa7, 0xa7, 0x4b, 0xf8, 0xb4, 0x4a, 0x55, 0xe9, 0xf3, 0x38, 0xe7, 0xdc, 0x8d, 0x25, 0xbf, 0xf6,
+	0x8a, 0x54, 0xdd, 0xb3, 0x24, 0xa2, 0x2c, 0x76, 0xfa, 0xa6, 0x67, 0x1a, 0x0d, 0x5e, 0x41, 0xaf,
+	0x92, 0x42, 0x3e, 0x80, 0xfa, 0x05, 0x5e, 0x1b, 0x8d, 0xa8, 0x9f, 0x4a, 0x04, 0x97, 0x34, 0x4c,
+	0xd1, 0x88, 0x23, 0x03, 0x2f, 0x6b, 0x2f, 0x2c, 0xf7, 0xcf, 0x3a, 0xac, 0x16, 0xcf, 0x17, 0xb3,
+	0x24, 0x16, 0xb8, 0x4c, 0x68, 0xeb, 0xd0, 0xc9, 0x84, 0xa6, 0x7c, 0x19, 0x59, 0xa6, 0x3c, 0xe5,
+	0x5c, 0x20, 0x9e, 0xfa, 0x42, 0xf1, 0x98, 0xfe, 0x37, 0x1e, 0xd0, 0xff, 0xe6, 0xfb, 0xf4, 0xdf,
+	0x7e, 0x60, 0xff, 0x5b, 0xf7, 0xef, 0x7f, 0xbb, 0xd4, 0xff, 0xbd, 0x52, 0xff, 0x33, 0xfd, 0x3e,
+	0x5d, 0xdc, 0xff, 0xec, 0xfe, 0xef, 0x12, 0xc0, 0xfb, 0x35, 0xfa, 0x17, 0x0b, 0x7a, 0x95, 0xcb,
+	0x55, 0x7a, 0x12, 0x49, 0xca, 0x7d, 0x34, 0x04, 0x06, 0x29, 0x7b, 0x84, 0x01, 0x4b, 0x23, 0x43,
+	0x62, 0x90, 0x9a, 0x68, 0x9f, 0x46, 0x33, 0xca, 0xa6, 0xb1, 0x59, 0x26, 0x05, 0xd6, 0xc7, 0x46,
+	0x1e, 0x99, 0x6d, 0xa2, 0x7f, 0x13, 0x07, 0x5a, 0x7e, 0x12, 0x4b, 0x8c, 0xa5, 0xde, 0x21, 0x1d,
+	0x2f, 0x87, 0xee, 0xcf, 0xd0, 0xab, 0xf4, 0x4c, 0x51, 0xcf, 0x42, 0x2a, 0xdf, 0x26, 0x3c, 0x32,
+	0xc5, 0x14, 0x58, 0xf9, 0x42, 0x1a, 0x4f, 0x53, 0x3a, 0xcd, 0x4f, 0x55, 0xe0, 0xec, 0x11, 0xa9,
+	0xba, 0x0b, 0x53, 0x51, 0x0e, 0x2b, 0x1a, 0x6e, 0x54, 0x34, 0xec, 0x9e, 0x40, 0xb7, 0xd4, 0x74,
+	0xc5, 0x71, 0x49, 0x39, 0xa3, 0xb1, 0xcc, 0xc5, 0x6e, 0x60, 0x85, 0xa3, 0x56, 0x9d, 0x83, 0x35,
+	0xb0, 0xaf, 0x90, 0x4d, 0xcf, 0xa5, 0x51, 0xb8, 0x41, 0xee, 0x0f, 0xd0, 0xaf, 0x0a, 0x43, 0x75,
+	0x44, 0x32, 0x19, 0xe6, 0x97, 0x9c, 0x01, 0x32, 0x84, 0x6e, 0x80, 0xc2, 0xe7, 0x6c, 0xa6, 0x6a,
+	0x30, 0xec, 0x65, 0x93, 0xca, 0x63, 0x91, 0x3a, 0xb3, 0xd9, 0xdb, 0x1a, 0xb8, 0x21, 0xf4, 0x3d,
+	0x14, 0x49, 0x78, 0x89, 0xf9, 0x5b, 0xe1, 0xa3, 0xf2, 0x44, 0xea, 0x67, 0xec, 0x3f, 0x2a, 0xcd,
+	0xe4, 0xfa, 0xcd, 0x37, 0xc3, 0xfe, 0xa3, 0xf9, 0xbb, 0x61, 0xbe, 0x51, 0xea, 0xe5, 0x8d, 0xb2,
+	0x6d, 0x43, 0x23, 0x64, 0xf1, 0x85, 0xfb, 0x7b, 0x0d, 0x56, 0x8b, 0xc7, 0xfd, 0x5f, 0xcb, 0x61,
+	0x03, 0xba, 0x3e, 0x47, 0x2a, 0xf1, 0x54, 0xb2, 0x28, 0x7b, 0x3f, 0xd5, 0x3d, 0xc8, 0x4c, 0x47,
+	0x2c, 0xc2, 0xd2, 0x5c, 0x36, 0xef, 0x3f, 0x97, 0xf6, 0x1d, 0x73, 0xd9, 0x5a, 0x30, 0x97, 0x37,
+	0x8e, 0xfe, 0xdf, 0xcc, 0xe5, 0xaf, 0x16, 0x90, 0x6d, 0x2a, 0xfd, 0xf3, 0xf9, 0x16, 0x48, 0x43,
+	0xa9, 0xdb, 0x1f, 0x07, 0xf8, 0xa3, 0x26, 0x69, 0x7a, 0x19, 0x20, 0x2f, 0xa1, 0xcd, 0x4d, 0x35,
+	0x9a, 0xa9, 0xbb, 0xf5, 0x64, 0xd9, 0x26, 0x51, 0x4a, 0xc8, 0xe3, 0xc9, 0x53, 0x68, 0x22, 0xe7,
+	0x49, 0xf6, 0x21, 0xd0, 0xdd, 0x22, 0x95, 0xc4, 0x5d, 0xe5, 0xd9, 0x7f, 0xe4, 0x65, 0x21, 0xdb,
+	0x6d, 0xb0, 0xb9, 0xae, 0xc3, 0xfd, 0xcd, 0x82, 0xa6, 0x76, 0xaa, 0x1b, 0xf4, 0x93, 0x00, 0x4d,
+	0x41, 0xfa, 0x77, 0x49, 0x40, 0xb5, 0xb2, 0x80, 0xd4, 0x4c, 0x45, 0x28, 0xc4, 0x5c, 0xbe, 0x39,
+	0x24, 0xcf, 0xc1, 0x7e, 0xcb, 0x30, 0x0c, 0x84, 0xd3, 0xd0, 0x37, 0xfe, 0xf1, 0xed, 0x32, 0xc6,
+	0x7b, 0x3a, 0x20, 0xbb, 0x65, 0x13, 0x3d, 0xf8, 0x12, 0xba, 0x25, 0xf3, 0xbd, 0x6e, 0xf8, 0x04,
+	0x7a, 0x3b, 0x18, 0xa2, 0xc4, 0xd2, 0x87, 0x54, 0x31, 0x13, 0x56, 0xf5, 0x6b, 0xe9, 0xae, 0x03,
+	0xad, 0xa9, 0x0b, 0xa1, 0x22, 0x29, 0x26, 0x25, 0x43, 0xee, 0x1e, 0xf4, 0x73, 0xee, 0xf9, 0x7c,
+	0xdc, 0x45, 0x7e, 0xf7, 0x3e, 0x71, 0x5f, 0xc3, 0x8a, 0x92, 0xbf, 0x78, 0x78, 0x89, 0xee, 0x4f,
+	0xd0, 0x33, 0x14, 0xff, 0x5c, 0xc9, 0x1a, 0xd8, 0x7e, 0xc8, 0xfc, 0x0b, 0xa1, 0x39, 0xea, 0x9e,
+	0x41, 0xe4, 0x39, 0xb4, 0xcd, 0xf2, 0x53, 0xd3, 0x79, 0xfb, 0x4d, 0xfb, 0x7d, 0xe6, 0xfc, 0x46,
+	0x47, 0x7b, 0x45, 0xac, 0x2b, 0xa1, 0x57, 0x71, 0xfd, 0xab, 0x4b, 0xb5, 0x54, 0x6d, 0xa3, 0x5c,
+	0xed, 0xd6, 0x5f, 0x35, 0x58, 0x39, 0xf6, 0xbe, 0x33, 0xa2, 0x47, 0x4e, 0x76, 0xa0, 0x65, 0x00,
+	0x59, 0x5f, 0xf2, 0x85, 0x35, 0x58, 0x3a, 0x34, 0x8a, 0xc5, 0x4c, 0xfe, 0x0d, 0x96, 0xea, 0xe6,
+	0x1d, 0x3c, 0x59, 0xec, 0x34, 0x2c, 0x6f, 0x60, 0xa5, 0x3c, 0xd6, 0xcb, 0x0b, 0xda, 0xa8, 0x38,
+	0x6f, 0xaf, 0x83, 0x91, 0xf5, 0xb9, 0x45, 0x5e, 0x83, 0x9d, 0x69, 0x8d, 0x0c, 0x6e, 0x7c, 0xc1,
+	0x94, 0xc4, 0x3d, 0x58, 0x5f, 0xe8, 0x33, 0x45, 0x7d, 0x0d, 0x4d, 0xad, 0x11, 0xf2, 0xb8, 0x5a,
+	0x4d, 0x49, 0x7a, 0x83, 0xc1, 0x22, 0x57, 0x96, 0xbf, 0x3d, 0x39, 0xd9, 0x9c, 0x32, 0x79, 0x9e,
+	0x9e, 0x8d, 0xfd, 0x24, 0x9a, 0x5c, 0x6d, 0x5e, 0x6c, 0x8a, 0x89, 0x8e, 0xde, 0x4c, 0x79, 0x38,
+	0xa1, 0x01, 0x9d, 0x49, 0xe4, 0x62, 0xc2, 0x67, 0xfe, 0x64, 0x76, 0xf6, 0x6a, 0x76, 0x76, 0x66,
+	0xeb, 0xbf, 0x36, 0xcf, 0xfe, 0x1e, 0x00, 0xdf, 0x83, 0x9d, 0xb4, 0xec, 0x0c, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// URLShortenerClient is the client API for URLShortener service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type URLShortenerClient interface {
+	// Shorten shortens a long url.
+	Shorten(ctx context.Context, in *ShortenRequest, opts ...grpc.CallOption) (*ShortenResponse, error)
+	// Resolve looks up the long url of a short url, or of a shortId on a domain.
+	Resolve(ctx context.Context, in *ResolveRequest, opts ...grpc.CallOption) (*ResolveResponse, error)
+	// BatchShorten shortens each url of the stream and streams the result of every url, in the order the urls were sent.
+	BatchShorten(ctx context.Context, opts ...grpc.CallOption) (URLShortener_BatchShortenClient, error)
+	// Delete disables a short url, so that visitors are no longer redirected.
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Stats counts the clicks on a short url.
+	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+}
+
+type uRLShortenerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewURLShortenerClient(cc *grpc.ClientConn) URLShortenerClient {
+	return &uRLShortenerClient{cc}
+}
+
+func (c *uRLShortenerClient) Shorten(ctx context.Context, in *ShortenRequest, opts ...grpc.CallOption) (*ShortenResponse, error) {
+	out := new(ShortenResponse)
+	err := c.cc.Invoke(ctx, "/shorturl.v1.URLShortener/Shorten", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) Resolve(ctx context.Context, in *ResolveRequest, opts ...grpc.CallOption) (*ResolveResponse, error) {
+	out := new(ResolveResponse)
+	err := c.cc.Invoke(ctx, "/shorturl.v1.URLShortener/Resolve", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) BatchShorten(ctx context.Context, opts ...grpc.CallOption) (URLShortener_BatchShortenClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_URLShortener_serviceDesc.Streams[0], "/shorturl.v1.URLShortener/BatchShorten", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &uRLShortenerBatchShortenClient{stream}
+	return x, nil
+}
+
+type URLShortener_BatchShortenClient interface {
+	Send(*ShortenRequest) error
+	Recv() (*BatchShortenResult, error)
+	grpc.ClientStream
+}
+
+type uRLShortenerBatchShortenClient struct {
+	grpc.ClientStream
+}
+
+func (x *uRLShortenerBatchShortenClient) Send(m *ShortenRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *uRLShortenerBatchShortenClient) Recv() (*BatchShortenResult, error) {
+	m := new(BatchShortenResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *uRLShortenerClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/shorturl.v1.URLShortener/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, "/shorturl.v1.URLShortener/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// URLShortenerServer is the server API for URLShortener service.
+type URLShortenerServer interface {
+	// Shorten shortens a long url.
+	Shorten(context.Context, *ShortenRequest) (*ShortenResponse, error)
+	// Resolve looks up the long url of a short url, or of a shortId on a domain.
+	Resolve(context.Context, *ResolveRequest) (*ResolveResponse, error)
+	// BatchShorten shortens each url of the stream and streams the result of every url, in the order the urls were sent.
+	BatchShorten(URLShortener_BatchShortenServer) error
+	// Delete disables a short url, so that visitors are no longer redirected.
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Stats counts the clicks on a short url.
+	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
+}
+
+// UnimplementedURLShortenerServer can be embedded to have forward compatible implementations.
+type UnimplementedURLShortenerServer struct {
+}
+
+func (*UnimplementedURLShortenerServer) Shorten(ctx context.Context, req *ShortenRequest) (*ShortenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Shorten not implemented")
+}
+func (*UnimplementedURLShortenerServer) Resolve(ctx context.Context, req *ResolveRequest) (*ResolveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resolve not implemented")
+}
+func (*UnimplementedURLShortenerServer) BatchShorten(srv URLShortener_BatchShortenServer) error {
+	return status.Errorf(codes.Unimplemented, "method BatchShorten not implemented")
+}
+func (*UnimplementedURLShortenerServer) Delete(ctx context.Context, req *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (*UnimplementedURLShortenerServer) Stats(ctx context.Context, req *StatsRequest) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+
+func RegisterURLShortenerServer(s *grpc.Server, srv URLShortenerServer) {
+	s.RegisterService(&_URLShortener_serviceDesc, srv)
+}
+
+func _URLShortener_Shorten_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).Shorten(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shorturl.v1.URLShortener/Shorten",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).Shorten(ctx, req.(*ShortenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_Resolve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).Resolve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shorturl.v1.URLShortener/Resolve",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).Resolve(ctx, req.(*ResolveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_BatchShorten_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(URLShortenerServer).BatchShorten(&uRLShortenerBatchShortenServer{stream})
+}
+
+type URLShortener_BatchShortenServer interface {
+	Send(*BatchShortenResult) error
+	Recv() (*ShortenRequest, error)
+	grpc.ServerStream
+}
+
+type uRLShortenerBatchShortenServer struct {
+	grpc.ServerStream
+}
+
+func (x *uRLShortenerBatchShortenServer) Send(m *BatchShortenResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *uRLShortenerBatchShortenServer) Recv() (*ShortenRequest, error) {
+	m := new(ShortenRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _URLShortener_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shorturl.v1.URLShortener/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shorturl.v1.URLShortener/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).Stats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _URLShortener_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "shorturl.v1.URLShortener",
+	HandlerType: (*URLShortenerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Shorten",
+			Handler:    _URLShortener_Shorten_Handler,
+		},
+		{
+			MethodName: "Resolve",
+			Handler:    _URLShortener_Resolve_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _URLShortener_Delete_Handler,
+		},
+		{
+			MethodName: "Stats",
+			Handler:    _URLShortener_Stats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchShorten",
+			Handler:       _URLShortener_BatchShorten_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "shorturl.proto",
+}
@@ -0,0 +1,169 @@
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"github.com/w-k-s/short-url/adapters/rpc/pb"
+	"github.com/w-k-s/short-url/domain"
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"strings"
+)
+
+// Server implements the URLShortener service with the same use cases as the REST API
+type Server struct {
+	shortenURLUseCase          *usecase.ShortenURLUseCase
+	batchShortenURLUseCase     *usecase.BatchShortenURLUseCase
+	retrieveOriginalURLUseCase *usecase.RetrieveOriginalURLUseCase
+	updateURLUseCase           *usecase.UpdateURLUseCase
+	clickStatsUseCase          *usecase.ClickStatsUseCase
+	adminToken                 string
+}
+
+// NewServer creates the service. Delete and Stats require the admin token, as they do in the REST API.
+func NewServer(
+	shortenURLUseCase *usecase.ShortenURLUseCase,
+	batchShortenURLUseCase *usecase.BatchShortenURLUseCase,
+	retrieveOriginalURLUseCase *usecase.RetrieveOriginalURLUseCase,
+	updateURLUseCase *usecase.UpdateURLUseCase,
+	clickStatsUseCase *usecase.ClickStatsUseCase,
+	adminToken string,
+) *Server {
+	return &Server{
+		shortenURLUseCase,
+		batchShortenURLUseCase,
+		retrieveOriginalURLUseCase,
+		updateURLUseCase,
+		clickStatsUseCase,
+		adminToken,
+	}
+}
+
+func (s *Server) Shorten(ctx context.Context, req *pb.ShortenRequest) (*pb.ShortenResponse, error) {
+	message := shortenURLRequest(req)
+	message.IdempotencyKey = idempotencyKey(ctx)
+
+	shortenReq, err := usecase.ValidateShortenURLRequest(message)
+	if err != nil {
+		return nil, sendError(ctx, err)
+	}
+
+	shortenResponse, err := s.shortenURLUseCase.Execute(shortenReq)
+	if err != nil {
+		return nil, sendError(ctx, err)
+	}
+	return shortenResponseMessage(shortenResponse), nil
+}
+
+func (s *Server) Resolve(ctx context.Context, req *pb.ResolveRequest) (*pb.ResolveResponse, error) {
+	var retrieveReq usecase.RetrieveOriginalURLRequest
+	var err domain.Err
+	if len(req.GetShortUrl()) > 0 {
+		retrieveReq, err = usecase.RetrieveShortURLRequest(req.GetShortUrl())
+	} else {
+		retrieveReq, err = usecase.RetrieveShortIDRequest(req.GetShortId(), req.Domain)
+	}
+	if err != nil {
+		return nil, sendError(ctx, err)
+	}
+
+	retrieveResponse, err := s.retrieveOriginalURLUseCase.Execute(retrieveReq)
+	if err != nil {
+		return nil, sendError(ctx, err)
+	}
+	return resolveResponseMessage(retrieveResponse), nil
+}
+
+// BatchShorten shortens the urls as they are received. Results are sent after each chunk of urls is saved.
+func (s *Server) BatchShorten(stream pb.URLShortener_BatchShortenServer) error {
+	batchReq := usecase.StreamedBatchShortenURLRequest(func() (usecase.ShortenURLRequest, error) {
+		req, err := stream.Recv()
+		if err != nil {
+			return usecase.ShortenURLRequest{}, err
+		}
+		return shortenURLRequest(req), nil
+	})
+
+	return s.batchShortenURLUseCase.Execute(batchReq, func(result usecase.BatchShortenURLResult) error {
+		return stream.Send(batchResultMessage(result))
+	})
+}
+
+// Delete disables the short url; records are kept so that the shortId is not reused
+func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	if err := authorize(ctx, s.adminToken); err != nil {
+		return nil, sendError(ctx, err)
+	}
+	if len(req.ShortId) == 0 {
+		return nil, sendError(ctx, usecase.NewError(usecase.UpdateURLValidation, "`shortId` is required", nil))
+	}
+
+	disabled := true
+	updateReq := usecase.UpdateURLRequest{
+		ShortID:  req.ShortId,
+		Domain:   req.Domain,
+		Disabled: &disabled,
+	}
+	if len(req.Reason) > 0 {
+		updateReq.DisabledReason = &req.Reason
+	}
+
+	updateResponse, err := s.updateURLUseCase.Execute(updateReq)
+	if err != nil {
+		return nil, sendError(ctx, err)
+	}
+	return &pb.DeleteResponse{
+		ShortId: updateResponse.ShortID,
+		LongUrl: updateResponse.LongURL,
+	}, nil
+}
+
+func (s *Server) Stats(ctx context.Context, req *pb.StatsRequest) (*pb.StatsResponse, error) {
+	if err := authorize(ctx, s.adminToken); err != nil {
+		return nil, sendError(ctx, err)
+	}
+	if len(req.ShortId) == 0 {
+		return nil, sendError(ctx, usecase.NewError(usecase.ClickStatsValidation, "`shortId` is required", nil))
+	}
+
+	statsResponse, err := s.clickStatsUseCase.Execute(usecase.ClickStatsRequest{ShortID: req.ShortId, Domain: req.Domain})
+	if err != nil {
+		return nil, sendError(ctx, err)
+	}
+	return statsResponseMessage(statsResponse), nil
+}
+
+// sendError sets the trailer of the error and returns its status
+func sendError(ctx context.Context, e domain.Err) error {
+	grpc.SetTrailer(ctx, errorTrailer(e))
+	return statusError(e)
+}
+
+// idempotencyKey returns the `idempotency-key` metadata, the counterpart of the Idempotency-Key header
+func idempotencyKey(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(usecase.IdempotencyKeyHeader); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// authorize checks the admin token in the `authorization` metadata e.g. `Bearer <ADMIN_TOKEN>`
+func authorize(ctx context.Context, adminToken string) domain.Err {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = strings.TrimPrefix(values[0], "Bearer ")
+		}
+	}
+	if len(adminToken) == 0 || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		return usecase.NewError(
+			usecase.Unauthorized,
+			"A valid admin token is required",
+			nil,
+		)
+	}
+	return nil
+}
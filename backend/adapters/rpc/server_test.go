@@ -0,0 +1,290 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/w-k-s/short-url/adapters/rpc/pb"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"github.com/w-k-s/short-url/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const savedShortID = "shrt"
+const savedLongURL = "http://www.example.com"
+const adminToken = "secret"
+
+//-- MockShortIDGenerator
+
+type MockShortIDGenerator struct {
+	ShortID string
+}
+
+func (m MockShortIDGenerator) Generate(d usecase.ShortIDLength) string {
+	return m.ShortID
+}
+
+//-- MockURLRepository
+
+// MockURLRepository saves every record, and finds LongURLRecordResult by its shortId
+type MockURLRepository struct {
+	SaveURLRecordResult *u.URLRecord
+	LongURLRecordResult *u.URLRecord
+}
+
+func (m MockURLRepository) SaveRecord(record *u.URLRecord) (*u.URLRecord, error) {
+	return m.SaveURLRecordResult, nil
+}
+
+func (m MockURLRepository) SaveRecords(records []*u.URLRecord) ([]*u.URLRecord, error) {
+	return records, nil
+}
+
+func (m MockURLRepository) ListRecords(query u.RecordQuery) ([]*u.URLRecord, error) {
+	return nil, nil
+}
+
+func (m MockURLRepository) LongURL(domain string, shortID string) (*u.URLRecord, error) {
+	if m.LongURLRecordResult == nil || m.LongURLRecordResult.ShortID != shortID {
+		return nil, errors.New("not found")
+	}
+	return m.LongURLRecordResult, nil
+}
+
+func (m MockURLRepository) ShortURL(domain string, longURL string) (*u.URLRecord, error) {
+	return nil, nil
+}
+
+func (m MockURLRepository) ShortURLForOwner(domain string, longURL string, owner string) (*u.URLRecord, error) {
+	return nil, nil
+}
+
+func (m MockURLRepository) UpdateRecord(record *u.URLRecord) error {
+	return nil
+}
+
+//-- MockAnalyticsRepository
+
+type MockAnalyticsRepository struct {
+	ClickCountsValue u.ClickCounts
+}
+
+func (m *MockAnalyticsRepository) SaveClick(click *u.Click) error {
+	return nil
+}
+
+func (m *MockAnalyticsRepository) ClickCounts(domain string, shortID string) (u.ClickCounts, error) {
+	return m.ClickCountsValue, nil
+}
+
+type ServerSuite struct {
+	suite.Suite
+	urlRepo   *MockURLRepository
+	analytics *MockAnalyticsRepository
+	generator *MockShortIDGenerator
+	record    *u.URLRecord
+	server    *grpc.Server
+	conn      *grpc.ClientConn
+	client    pb.URLShortenerClient
+}
+
+func TestServerSuite(t *testing.T) {
+	suite.Run(t, new(ServerSuite))
+}
+
+func (suite *ServerSuite) SetupTest() {
+	baseURL, _ := url.Parse("https://small.ml")
+	domains := usecase.NewDomains(baseURL, nil, nil)
+
+	suite.generator = &MockShortIDGenerator{ShortID: "unique"}
+	suite.record = &u.URLRecord{
+		LongURL:    savedLongURL,
+		ShortID:    savedShortID,
+		CreateTime: time.Unix(1600000000, 0),
+	}
+	suite.urlRepo = &MockURLRepository{LongURLRecordResult: suite.record}
+	suite.analytics = &MockAnalyticsRepository{}
+
+	shortenURLUseCase := usecase.NewShortenURLUseCase(suite.urlRepo, baseURL, suite.generator, usecase.WithDomains(domains))
+	service := NewServer(
+		shortenURLUseCase,
+		usecase.NewBatchShortenURLUseCase(shortenURLUseCase, usecase.WithBatchInsertSize(2)),
+		usecase.NewRetrieveOriginalURLUseCase(suite.urlRepo, usecase.WithDomainLookup(domains)),
+		usecase.NewUpdateURLUseCase(suite.urlRepo, domains, usecase.DefaultDestinationPolicy(), nil),
+		usecase.NewClickStatsUseCase(suite.urlRepo, domains, suite.analytics),
+		adminToken,
+	)
+
+	listener := bufconn.Listen(1024 * 1024)
+	suite.server = Init("bufconn", service).server
+	go suite.server.Serve(listener)
+
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+	)
+	if err != nil {
+		suite.T().Fatalf("Setup Test: %s", err)
+	}
+	suite.conn = conn
+	suite.client = pb.NewURLShortenerClient(conn)
+
+	log.Init()
+}
+
+func (suite *ServerSuite) TearDownTest() {
+	suite.conn.Close()
+	suite.server.Stop()
+}
+
+func adminContext() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+adminToken)
+}
+
+func (suite *ServerSuite) TestGivenLongURL_WhenShortening_ThenReturnsShortURL() {
+	//Given
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{LongURL: "http://www.eg.com", ShortID: "unique", CreateTime: time.Now(), Tags: []string{"summer"}}
+
+	//When
+	response, err := suite.client.Shorten(context.Background(), &pb.ShortenRequest{
+		LongUrl: "http://www.eg.com",
+		Tags:    []string{"summer"},
+	})
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "https://small.ml/unique", response.ShortUrl)
+	assert.Equal(suite.T(), []string{"summer"}, response.Tags)
+}
+
+func (suite *ServerSuite) TestGivenRelativeURL_WhenShortening_ThenInvalidArgumentWithDomainCode() {
+	//Given
+	var trailer metadata.MD
+
+	//When
+	_, err := suite.client.Shorten(context.Background(), &pb.ShortenRequest{LongUrl: "hello there"}, grpc.Trailer(&trailer))
+
+	//Then
+	assert.Equal(suite.T(), codes.InvalidArgument, status.Code(err))
+	assert.Equal(suite.T(), []string{"10300"}, trailer.Get("error-code"))
+	assert.Equal(suite.T(), []string{"shortenUrl.validation"}, trailer.Get("error-domain"))
+}
+
+func (suite *ServerSuite) TestGivenShortURL_WhenResolving_ThenReturnsLongURL() {
+	//When
+	response, err := suite.client.Resolve(context.Background(), &pb.ResolveRequest{
+		Link: &pb.ResolveRequest_ShortUrl{ShortUrl: "https://small.ml/" + savedShortID},
+	})
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), savedLongURL, response.LongUrl)
+	assert.Equal(suite.T(), int64(1600000000), response.CreateTime)
+}
+
+func (suite *ServerSuite) TestGivenUnknownShortID_WhenResolving_ThenNotFound() {
+	//When
+	_, err := suite.client.Resolve(context.Background(), &pb.ResolveRequest{
+		Link: &pb.ResolveRequest_ShortId{ShortId: "absent"},
+	})
+
+	//Then
+	assert.Equal(suite.T(), codes.NotFound, status.Code(err))
+}
+
+func (suite *ServerSuite) TestGivenDisabledURL_WhenResolving_ThenPermissionDenied() {
+	//Given
+	suite.record.Disabled = true
+
+	//When
+	_, err := suite.client.Resolve(context.Background(), &pb.ResolveRequest{
+		Link: &pb.ResolveRequest_ShortId{ShortId: savedShortID},
+	})
+
+	//Then
+	assert.Equal(suite.T(), codes.PermissionDenied, status.Code(err))
+}
+
+func (suite *ServerSuite) TestGivenStreamOfURLs_WhenBatchShortening_ThenStreamsResultsInOrder() {
+	//Given
+	stream, err := suite.client.BatchShorten(context.Background())
+	assert.Nil(suite.T(), err)
+
+	//When
+	for _, longURL := range []string{"http://www.eg.com/1", "not a url", "http://www.eg.com/3"} {
+		assert.Nil(suite.T(), stream.Send(&pb.ShortenRequest{LongUrl: longURL}))
+	}
+	assert.Nil(suite.T(), stream.CloseSend())
+
+	var results []*pb.BatchShortenResult
+	for {
+		result, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(suite.T(), err)
+		results = append(results, result)
+	}
+
+	//Then
+	assert.Len(suite.T(), results, 3)
+	for index, result := range results {
+		assert.Equal(suite.T(), int32(index), result.Index)
+	}
+	assert.NotNil(suite.T(), results[0].GetResponse())
+	assert.Equal(suite.T(), int32(usecase.ShortenURLValidation), results[1].GetError().GetCode())
+	assert.NotNil(suite.T(), results[2].GetResponse())
+}
+
+func (suite *ServerSuite) TestGivenAdminToken_WhenDeleting_ThenDisablesURL() {
+	//When
+	response, err := suite.client.Delete(adminContext(), &pb.DeleteRequest{ShortId: savedShortID, Reason: "Expired"})
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), savedLongURL, response.LongUrl)
+	assert.True(suite.T(), suite.record.Disabled)
+	assert.Equal(suite.T(), "Expired", suite.record.DisabledReason)
+}
+
+func (suite *ServerSuite) TestGivenNoAdminToken_WhenDeleting_ThenUnauthenticated() {
+	//When
+	_, err := suite.client.Delete(context.Background(), &pb.DeleteRequest{ShortId: savedShortID})
+
+	//Then
+	assert.Equal(suite.T(), codes.Unauthenticated, status.Code(err))
+	assert.False(suite.T(), suite.record.Disabled)
+}
+
+func (suite *ServerSuite) TestGivenClicks_WhenGettingStats_ThenReturnsClicks() {
+	//Given
+	suite.analytics.ClickCountsValue = u.ClickCounts{"": 7}
+
+	//When
+	response, err := suite.client.Stats(adminContext(), &pb.StatsRequest{ShortId: savedShortID})
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(7), response.Clicks)
+}
+
+func (suite *ServerSuite) TestGivenNoShortID_WhenGettingStats_ThenInvalidArgument() {
+	//When
+	_, err := suite.client.Stats(adminContext(), &pb.StatsRequest{})
+
+	//Then
+	assert.Equal(suite.T(), codes.InvalidArgument, status.Code(err))
+}
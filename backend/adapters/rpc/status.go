@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/adapters/web"
+	"github.com/w-k-s/short-url/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+const (
+	// errorCodeTrailer is the trailer with the domain.Code of an error
+	errorCodeTrailer = "error-code"
+	// errorDomainTrailer is the trailer with the domain of an error e.g. `shortenUrl.validation`
+	errorDomainTrailer = "error-domain"
+)
+
+// statusError converts the error into a gRPC status. The domain code and domain are sent in the trailer.
+func statusError(e domain.Err) error {
+	return status.Error(grpcStatusCode(e.Code()), e.Error())
+}
+
+func errorTrailer(e domain.Err) metadata.MD {
+	return metadata.Pairs(
+		errorCodeTrailer, fmt.Sprint(e.Code()),
+		errorDomainTrailer, e.Domain(),
+	)
+}
+
+// grpcCodes are the gRPC status codes of the HTTP statuses of errors in the REST API, so that both APIs classify errors the same way
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.Aborted,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusUnprocessableEntity:   codes.FailedPrecondition,
+	http.StatusLoopDetected:          codes.FailedPrecondition,
+}
+
+// grpcStatusCode returns the gRPC status code that corresponds to the HTTP status of the error in the REST API
+func grpcStatusCode(e domain.Code) codes.Code {
+	if code, ok := grpcCodes[web.HTTPStatusCode(e)]; ok {
+		return code
+	}
+	return codes.Internal
+}
@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/w-k-s/short-url/adapters/web"
+	"github.com/w-k-s/short-url/domain"
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"google.golang.org/grpc/codes"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// errorsFile declares the error codes of the use cases
+const errorsFile = "../../domain/urlshortener/usecase/errors.go"
+
+// httpStatuses are the HTTP statuses that the gRPC status codes stand for (https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto)
+var httpStatuses = map[codes.Code][]int{
+	codes.InvalidArgument:    {http.StatusBadRequest},
+	codes.FailedPrecondition: {http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusLoopDetected},
+	codes.Unauthenticated:    {http.StatusUnauthorized},
+	codes.PermissionDenied:   {http.StatusForbidden},
+	codes.NotFound:           {http.StatusNotFound},
+	codes.AlreadyExists:      {http.StatusConflict},
+	codes.Aborted:            {http.StatusConflict},
+	codes.ResourceExhausted:  {http.StatusTooManyRequests, http.StatusRequestEntityTooLarge},
+	codes.Internal:           {http.StatusInternalServerError},
+}
+
+// declaredErrorCodes finds the values of the error code constants e.g. `ShortenURLValidation = 10300`
+func declaredErrorCodes(t *testing.T) []domain.Code {
+	file, err := parser.ParseFile(token.NewFileSet(), errorsFile, nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse error codes: %s", err)
+	}
+
+	var codes []domain.Code
+	for _, decl := range file.Decls {
+		constants, ok := decl.(*ast.GenDecl)
+		if !ok || constants.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range constants.Specs {
+			for _, value := range spec.(*ast.ValueSpec).Values {
+				literal, ok := value.(*ast.BasicLit)
+				if !ok || literal.Kind != token.INT {
+					t.Fatalf("Expected error codes to be declared with integer literals")
+				}
+				code, _ := strconv.Atoi(literal.Value)
+				codes = append(codes, domain.Code(code))
+			}
+		}
+	}
+	return codes
+}
+
+func TestEveryErrorCodeHasTheSameStatusInBothAPIs(t *testing.T) {
+	codes := declaredErrorCodes(t)
+	assert.NotEmpty(t, codes)
+
+	for _, code := range codes {
+		grpcCode := grpcStatusCode(code)
+		assert.Contains(t, httpStatuses[grpcCode], web.HTTPStatusCode(code), "Error %d is %s in gRPC but %d in HTTP", code, grpcCode, web.HTTPStatusCode(code))
+	}
+}
+
+func TestGRPCStatusCodes(t *testing.T) {
+	testCases := map[domain.Code]codes.Code{
+		usecase.ShortenURLValidation:               codes.InvalidArgument,
+		usecase.ClickStatsValidation:               codes.InvalidArgument,
+		usecase.ShortenURLShortIDInUse:             codes.InvalidArgument,
+		usecase.ShortenURLIdempotencyKeyInProgress: codes.Aborted,
+		usecase.ShortenURLIdempotencyKeyReused:     codes.FailedPrecondition,
+		usecase.RetrieveFullURLNotFound:            codes.NotFound,
+		usecase.RetrieveFullURLDisabled:            codes.PermissionDenied,
+		usecase.Unauthorized:                       codes.Unauthenticated,
+		usecase.BatchShortenURLTooLarge:            codes.ResourceExhausted,
+		usecase.RetrieveFullURLRedirectLoop:        codes.FailedPrecondition,
+		usecase.ShortenURLFailedToSave:             codes.Internal,
+	}
+	for code, expectation := range testCases {
+		assert.Equal(t, expectation, grpcStatusCode(code), "Wrong status code for %d", code)
+	}
+}
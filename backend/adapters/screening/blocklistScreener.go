@@ -0,0 +1,187 @@
+package screening
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"github.com/w-k-s/short-url/log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BlocklistScreener screens urls against blocklists stored on disk.
+//
+// The hosts file lists one hostname per line; subdomains of a listed host are also blocked.
+// The hashes file lists one hex-encoded SHA-256 hash of a full url per line.
+// Blank lines and lines starting with '#' are ignored.
+//
+// Files are reloaded when their modification time changes (see Watch).
+type BlocklistScreener struct {
+	hostsPath  string
+	hashesPath string
+
+	mu       sync.RWMutex
+	hosts    map[string]bool
+	hashes   map[string]bool
+	modTimes map[string]time.Time
+}
+
+// NewBlocklistScreener loads the blocklists. Either path may be empty.
+func NewBlocklistScreener(hostsPath string, hashesPath string) (*BlocklistScreener, error) {
+	screener := &BlocklistScreener{
+		hostsPath:  hostsPath,
+		hashesPath: hashesPath,
+		hosts:      map[string]bool{},
+		hashes:     map[string]bool{},
+		modTimes:   map[string]time.Time{},
+	}
+
+	if err := screener.Reload(); err != nil {
+		return nil, err
+	}
+	return screener, nil
+}
+
+// HashURL returns the hash of a url as it is expected in the hashes file
+func HashURL(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:])
+}
+
+func (b *BlocklistScreener) Screen(longURL *url.URL) (usecase.Verdict, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	host := strings.TrimSuffix(strings.ToLower(longURL.Hostname()), ".")
+	for candidate := host; len(candidate) > 0; candidate = parentDomain(candidate) {
+		if b.hosts[candidate] {
+			return usecase.Verdict{
+				Malicious: true,
+				Reason:    fmt.Sprintf("The host '%s' is blocklisted", candidate),
+			}, nil
+		}
+	}
+
+	if b.hashes[HashURL(longURL.String())] {
+		return usecase.Verdict{
+			Malicious: true,
+			Reason:    "The url is blocklisted",
+		}, nil
+	}
+
+	return usecase.Verdict{}, nil
+}
+
+// Reload reads the blocklists that have been modified since they were last read
+func (b *BlocklistScreener) Reload() error {
+	hosts, err := b.readIfModified(b.hostsPath, func(line string) string {
+		return strings.TrimSuffix(strings.ToLower(line), ".")
+	})
+	if err != nil {
+		return err
+	}
+
+	hashes, err := b.readIfModified(b.hashesPath, strings.ToLower)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if hosts != nil {
+		b.hosts = hosts
+		log.Printf("Loaded %d blocklisted hosts from '%s'", len(hosts), b.hostsPath)
+	}
+	if hashes != nil {
+		b.hashes = hashes
+		log.Printf("Loaded %d blocklisted url hashes from '%s'", len(hashes), b.hashesPath)
+	}
+	return nil
+}
+
+// Watch reloads the blocklists every interval until the returned function is called.
+// The blocklists are not reloaded if the interval is 0 or less.
+func (b *BlocklistScreener) Watch(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := b.Reload(); err != nil {
+					log.Printf("Failed to reload blocklists: %s", err)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// readIfModified returns nil if the path is empty or the file has not changed since it was last read
+func (b *BlocklistScreener) readIfModified(path string, normalize func(string) string) (map[string]bool, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.RLock()
+	lastModTime, loaded := b.modTimes[path]
+	b.mu.RUnlock()
+	if loaded && info.ModTime().Equal(lastModTime) {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries[normalize(line)] = true
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.modTimes[path] = info.ModTime()
+	b.mu.Unlock()
+
+	return entries, nil
+}
+
+func parentDomain(host string) string {
+	index := strings.Index(host, ".")
+	if index < 0 {
+		return ""
+	}
+	return host[index+1:]
+}
@@ -0,0 +1,106 @@
+package screening
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/w-k-s/short-url/log"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type BlocklistScreenerTestSuite struct {
+	suite.Suite
+	dir        string
+	hostsPath  string
+	hashesPath string
+}
+
+func TestBlocklistScreenerTestSuite(t *testing.T) {
+	suite.Run(t, new(BlocklistScreenerTestSuite))
+}
+
+func (suite *BlocklistScreenerTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "blocklists")
+	if err != nil {
+		panic(err)
+	}
+
+	suite.dir = dir
+	suite.hostsPath = filepath.Join(dir, "hosts.txt")
+	suite.hashesPath = filepath.Join(dir, "hashes.txt")
+
+	suite.writeFile(suite.hostsPath, "# phishing hosts\nevil.example.com\n\nMALWARE.example.org.\n", time.Now().Add(-time.Hour))
+	suite.writeFile(suite.hashesPath, HashURL("https://www.example.net/login")+"\n", time.Now().Add(-time.Hour))
+
+	log.Init()
+}
+
+func (suite *BlocklistScreenerTestSuite) TearDownTest() {
+	os.RemoveAll(suite.dir)
+}
+
+func (suite *BlocklistScreenerTestSuite) writeFile(path string, contents string, modTime time.Time) {
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		panic(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		panic(err)
+	}
+}
+
+func (suite *BlocklistScreenerTestSuite) screen(screener *BlocklistScreener, rawURL string) bool {
+	longURL, _ := url.Parse(rawURL)
+	verdict, err := screener.Screen(longURL)
+	assert.Nil(suite.T(), err)
+	return verdict.Malicious
+}
+
+func (suite *BlocklistScreenerTestSuite) TestGivenBlocklistedHost_WhenScreening_ThenMalicious() {
+	screener, err := NewBlocklistScreener(suite.hostsPath, suite.hashesPath)
+	assert.Nil(suite.T(), err)
+
+	assert.True(suite.T(), suite.screen(screener, "http://evil.example.com/login"))
+	assert.True(suite.T(), suite.screen(screener, "https://www.evil.example.com"))
+	assert.True(suite.T(), suite.screen(screener, "https://malware.example.org"))
+	assert.False(suite.T(), suite.screen(screener, "https://example.com"))
+	assert.False(suite.T(), suite.screen(screener, "https://notevil.example.com"))
+}
+
+func (suite *BlocklistScreenerTestSuite) TestGivenBlocklistedURLHash_WhenScreening_ThenMalicious() {
+	screener, err := NewBlocklistScreener(suite.hostsPath, suite.hashesPath)
+	assert.Nil(suite.T(), err)
+
+	assert.True(suite.T(), suite.screen(screener, "https://www.example.net/login"))
+	assert.False(suite.T(), suite.screen(screener, "https://www.example.net/"))
+}
+
+func (suite *BlocklistScreenerTestSuite) TestGivenBlocklistModified_WhenReloading_ThenNewEntriesUsed() {
+	screener, err := NewBlocklistScreener(suite.hostsPath, "")
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), suite.screen(screener, "https://phish.example.io"))
+
+	suite.writeFile(suite.hostsPath, "phish.example.io\n", time.Now())
+	assert.Nil(suite.T(), screener.Reload())
+
+	assert.True(suite.T(), suite.screen(screener, "https://phish.example.io"))
+	assert.False(suite.T(), suite.screen(screener, "http://evil.example.com/login"))
+}
+
+func (suite *BlocklistScreenerTestSuite) TestGivenNoInterval_WhenWatching_ThenBlocklistsNotReloaded() {
+	screener, err := NewBlocklistScreener(suite.hostsPath, suite.hashesPath)
+	assert.Nil(suite.T(), err)
+
+	stop := screener.Watch(0)
+	defer stop()
+
+	assert.True(suite.T(), suite.screen(screener, "http://evil.example.com/login"))
+}
+
+func (suite *BlocklistScreenerTestSuite) TestGivenMissingBlocklist_WhenCreatingScreener_ThenReturnError() {
+	_, err := NewBlocklistScreener(filepath.Join(suite.dir, "missing.txt"), "")
+	assert.NotNil(suite.T(), err)
+}
@@ -0,0 +1,161 @@
+package social
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"html"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// maxPageSize is the number of bytes of a page that are read. Metadata is expected in the page's <head>.
+const maxPageSize = 512 * 1024
+
+const userAgent = "short-url-unfurler/1.0 (+https://github.com/w-k-s/short-url)"
+
+var (
+	metaPattern      = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attributePattern = regexp.MustCompile(`(?s)([a-zA-Z_:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	titlePattern     = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+// MetadataFetcher reads the OpenGraph and Twitter card tags of a web page.
+// The page's <title> and description are used if it has no OpenGraph or Twitter card tags.
+type MetadataFetcher struct {
+	client *http.Client
+}
+
+// NewMetadataFetcher fetches pages with the client (see NewClient)
+func NewMetadataFetcher(client *http.Client) *MetadataFetcher {
+	return &MetadataFetcher{
+		client: client,
+	}
+}
+
+func (f *MetadataFetcher) Fetch(longURL *url.URL) (usecase.SocialMetadata, error) {
+	req, err := http.NewRequest("GET", longURL.String(), nil)
+	if err != nil {
+		return usecase.SocialMetadata{}, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return usecase.SocialMetadata{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return usecase.SocialMetadata{}, fmt.Errorf("'%s' responded with status %d", longURL, resp.StatusCode)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return usecase.SocialMetadata{}, fmt.Errorf("'%s' is not an html page (Content-Type: %q)", longURL, resp.Header.Get("Content-Type"))
+	}
+
+	page, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return usecase.SocialMetadata{}, err
+	}
+
+	// Relative images are relative to the page the client was redirected to
+	return parseMetadata(string(page), resp.Request.URL), nil
+}
+
+// parseMetadata reads the metadata from the page's meta tags.
+// OpenGraph tags take precedence over Twitter card tags, which take precedence over the page's title and description.
+func parseMetadata(page string, pageURL *url.URL) usecase.SocialMetadata {
+	tags := map[string]string{}
+	for _, meta := range metaPattern.FindAllString(page, -1) {
+		attributes := map[string]string{}
+		for _, match := range attributePattern.FindAllStringSubmatch(meta, -1) {
+			attributes[strings.ToLower(match[1])] = match[2] + match[3] + match[4]
+		}
+
+		// OpenGraph uses `property`, Twitter cards and plain html use `name`
+		key := attributes["property"]
+		if len(key) == 0 {
+			key = attributes["name"]
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if _, seen := tags[key]; len(key) > 0 && !seen {
+			tags[key] = cleanText(attributes["content"])
+		}
+	}
+
+	var title string
+	if match := titlePattern.FindStringSubmatch(page); match != nil {
+		title = cleanText(match[1])
+	}
+
+	metadata := usecase.SocialMetadata{
+		Title:       firstNonEmpty(tags["og:title"], tags["twitter:title"], title),
+		Description: firstNonEmpty(tags["og:description"], tags["twitter:description"], tags["description"]),
+	}
+
+	image := firstNonEmpty(tags["og:image:secure_url"], tags["og:image"], tags["og:image:url"], tags["twitter:image"], tags["twitter:image:src"])
+	if imageURL, err := pageURL.Parse(image); len(image) > 0 && err == nil {
+		metadata.Image = imageURL.String()
+	}
+	return metadata
+}
+
+// cleanText unescapes html entities and collapses whitespace
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if len(value) > 0 {
+			return value
+		}
+	}
+	return ""
+}
+
+// NewClient returns a client that refuses to connect to the denied networks, even if a host resolves to
+// a denied address only after the long url was checked or a page redirects to a denied address.
+func NewClient(timeout time.Duration, deniedNetworks []*net.IPNet) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			for _, denied := range deniedNetworks {
+				if ip == nil || denied.Contains(ip) {
+					return fmt.Errorf("connections to '%s' are not allowed", host)
+				}
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		// Connections are not made through a proxy so that the address that is dialled is the one that is checked
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return fmt.Errorf("stopped after %d redirects", len(via))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,140 @@
+package social
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const openGraphPage = `<!DOCTYPE html>
+<html>
+<head>
+	<title>Fallback title</title>
+	<meta name="description" content="Fallback description">
+	<meta property="og:title" content="Fish &amp; Chips">
+	<meta content="The   best fish
+	in town" property='og:description'>
+	<meta property="og:image" content="/images/card.png">
+	<meta name="twitter:title" content="Twitter title">
+</head>
+<body></body>
+</html>`
+
+const plainPage = `<html><head><TITLE>
+	Plain page
+</TITLE><meta name=description content=Plain></head></html>`
+
+type MetadataFetcherTestSuite struct {
+	suite.Suite
+	server  *httptest.Server
+	fetcher *MetadataFetcher
+}
+
+func TestMetadataFetcherTestSuite(t *testing.T) {
+	suite.Run(t, new(MetadataFetcherTestSuite))
+}
+
+func (suite *MetadataFetcherTestSuite) SetupTest() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/og", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, openGraphPage)
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, plainPage)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, "/articles/og", http.StatusFound)
+	})
+	mux.HandleFunc("/articles/og", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<meta property="og:image" content="card.png">`)
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(w, "\x89PNG")
+	})
+
+	suite.server = httptest.NewServer(mux)
+	suite.fetcher = NewMetadataFetcher(suite.server.Client())
+}
+
+func (suite *MetadataFetcherTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *MetadataFetcherTestSuite) fetch(path string) (usecase.SocialMetadata, error) {
+	pageURL, _ := url.Parse(suite.server.URL + path)
+	return suite.fetcher.Fetch(pageURL)
+}
+
+func (suite *MetadataFetcherTestSuite) TestGivenOpenGraphTags_WhenFetching_ThenOpenGraphTagsAreUsed() {
+	//When
+	metadata, err := suite.fetch("/og")
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), usecase.SocialMetadata{
+		Title:       "Fish & Chips",
+		Description: "The best fish in town",
+		Image:       suite.server.URL + "/images/card.png",
+	}, metadata)
+}
+
+func (suite *MetadataFetcherTestSuite) TestGivenNoOpenGraphTags_WhenFetching_ThenTitleAndDescriptionAreUsed() {
+	//When
+	metadata, err := suite.fetch("/plain")
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), usecase.SocialMetadata{
+		Title:       "Plain page",
+		Description: "Plain",
+	}, metadata)
+}
+
+func (suite *MetadataFetcherTestSuite) TestGivenRedirect_WhenFetching_ThenImageIsRelativeToFinalPage() {
+	//When
+	metadata, err := suite.fetch("/moved")
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), suite.server.URL+"/articles/card.png", metadata.Image)
+}
+
+func (suite *MetadataFetcherTestSuite) TestGivenNonHTMLPage_WhenFetching_ThenError() {
+	//When
+	_, err := suite.fetch("/image.png")
+
+	//Then
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *MetadataFetcherTestSuite) TestGivenMissingPage_WhenFetching_ThenError() {
+	//When
+	_, err := suite.fetch("/missing")
+
+	//Then
+	assert.NotNil(suite.T(), err)
+}
+
+func (suite *MetadataFetcherTestSuite) TestGivenDeniedNetwork_WhenFetching_ThenConnectionIsRefused() {
+	//Given
+	networks, _ := usecase.ParseNetworks([]string{"127.0.0.0/8", "::1/128"})
+	fetcher := NewMetadataFetcher(NewClient(time.Second, networks))
+	pageURL, _ := url.Parse(suite.server.URL + "/og")
+
+	//When
+	_, err := fetcher.Fetch(pageURL)
+
+	//Then
+	assert.NotNil(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "not allowed")
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/w-k-s/short-url/log"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -14,18 +15,36 @@ type Routable interface {
 type App struct {
 	server *http.Server
 	router *mux.Router
+	// routes is the router that handlers are registered on, under the path prefix
+	routes *mux.Router
+	// requestTimeout is how long handlers have to respond, except the handlers of streaming routes
+	requestTimeout time.Duration
+	streaming      map[*mux.Route]bool
+	// redirects are the routes that redirect short urls, which CORS preflight requests are not answered for
+	redirects map[*mux.Route]bool
 }
 
-func Init(listenAddress string) *App {
+// Init creates the app. Routes are mounted under the path prefix (e.g. `/go`) if one is given.
+func Init(listenAddress string, pathPrefix string, requestTimeout time.Duration) *App {
 
 	router := mux.NewRouter()
 
+	routes := router
+	if pathPrefix = strings.TrimSuffix(pathPrefix, "/"); len(pathPrefix) > 0 {
+		routes = router.PathPrefix(pathPrefix).Subrouter()
+	}
+
 	server := createServer(router, listenAddress)
 
 	app := &App{
 		server,
 		router,
+		routes,
+		requestTimeout,
+		map[*mux.Route]bool{},
+		map[*mux.Route]bool{},
 	}
+	router.Use(app.timeout)
 
 	return app
 }
@@ -35,16 +54,79 @@ func (a *App) ListenAndServe() error {
 	return a.server.ListenAndServe()
 }
 
+// Register mounts the routes under the path prefix
 func (a *App) Register(routable Routable) {
+	routable.Route(a.routes)
+}
+
+// RegisterStreaming mounts routes that stream their responses under the path prefix.
+// The request timeout does not apply to them e.g. uploading and shortening a large batch takes longer than other requests.
+func (a *App) RegisterStreaming(routable Routable) {
+	for route := range a.register(routable) {
+		a.streaming[route] = true
+	}
+}
+
+// RegisterRedirects mounts the routes that redirect short urls under the path prefix.
+// They match almost every path (e.g. `/{shortUrl}`), so CORS preflight requests are not answered for them;
+// otherwise a preflight request for any path would succeed.
+func (a *App) RegisterRedirects(routable Routable) {
+	for route := range a.register(routable) {
+		a.redirects[route] = true
+	}
+}
+
+// register mounts the routes under the path prefix, and returns the routes that were added
+func (a *App) register(routable Routable) map[*mux.Route]bool {
+	registered := routesOf(a.routes)
+	routable.Route(a.routes)
+	added := map[*mux.Route]bool{}
+	for route := range routesOf(a.routes) {
+		if !registered[route] {
+			added[route] = true
+		}
+	}
+	return added
+}
+
+// RegisterAtRoot mounts the routes at the root, ignoring the path prefix
+// e.g. for health checks by the load balancer
+func (a *App) RegisterAtRoot(routable Routable) {
 	routable.Route(a.router)
 }
 
+// EnableCORS adds the CORS headers of the policy to every response, and answers preflight requests for every route except redirects
+func (a *App) EnableCORS(policy CORSPolicy) {
+	a.server.Handler = NewCORSHandler(policy, a.router, a.redirects)
+}
+
+// timeout responds with 503 Service Unavailable if the handler of the route takes longer than the request timeout.
+// The server has no read or write timeout, since it would cut off the requests and responses of streaming routes.
+func (a *App) timeout(next http.Handler) http.Handler {
+	limited := http.TimeoutHandler(next, a.requestTimeout, "The request timed out")
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if a.requestTimeout <= 0 || a.streaming[mux.CurrentRoute(req)] {
+			next.ServeHTTP(w, req)
+			return
+		}
+		limited.ServeHTTP(w, req)
+	})
+}
+
+func routesOf(router *mux.Router) map[*mux.Route]bool {
+	routes := map[*mux.Route]bool{}
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		routes[route] = true
+		return nil
+	})
+	return routes
+}
+
 func createServer(h http.Handler, address string) *http.Server {
 	return &http.Server{
-		Handler:      h,
-		Addr:         address,
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 5 * time.Second,
-		IdleTimeout:  120 * time.Second,
+		Handler:           h,
+		Addr:              address,
+		ReadHeaderTimeout: 5 * time.Second,
+		IdleTimeout:       120 * time.Second,
 	}
 }
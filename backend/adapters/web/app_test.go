@@ -0,0 +1,124 @@
+package web
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/gorilla/mux"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type routeFunc func(*mux.Router)
+
+func (f routeFunc) Route(r *mux.Router) {
+	f(r)
+}
+
+func TestRoutesMountedUnderPathPrefix(t *testing.T) {
+
+	app := Init(":0", "/go/", 5*time.Second)
+	app.Register(routeFunc(func(r *mux.Router) {
+		r.HandleFunc("/{shortUrl}", func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(mux.Vars(req)["shortUrl"]))
+		})
+	}))
+	app.RegisterAtRoot(routeFunc(func(r *mux.Router) {
+		r.HandleFunc("/health", func(w http.ResponseWriter, req *http.Request) {})
+	}))
+
+	for path, expectation := range map[string]int{
+		"/go/abc": http.StatusOK,
+		"/abc":    http.StatusNotFound,
+		"/health": http.StatusOK,
+	} {
+		w := httptest.NewRecorder()
+		app.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if w.Code != expectation {
+			t.Errorf("GET %s: Expected status %d. Got: %d", path, expectation, w.Code)
+		}
+	}
+}
+
+func TestStreamingRoutesNotCutOffByRequestTimeout(t *testing.T) {
+
+	// Counts the lines of the batch as they are uploaded
+	countLines := func(w http.ResponseWriter, req *http.Request) {
+		lines := 0
+		scanner := bufio.NewScanner(req.Body)
+		for scanner.Scan() {
+			lines++
+		}
+		fmt.Fprintf(w, "%d", lines)
+	}
+
+	app := Init(":0", "", 100*time.Millisecond)
+	app.Register(routeFunc(func(r *mux.Router) {
+		r.HandleFunc("/lines", countLines).Methods("POST")
+	}))
+	app.RegisterStreaming(routeFunc(func(r *mux.Router) {
+		r.HandleFunc("/lines:batch", countLines).Methods("POST")
+	}))
+
+	server := httptest.NewUnstartedServer(app.server.Handler)
+	server.Config = app.server
+	server.Start()
+	defer server.Close()
+
+	for path, expectation := range map[string]int{
+		"/lines":       http.StatusServiceUnavailable,
+		"/lines:batch": http.StatusOK,
+	} {
+		// The batch takes 3 times the request timeout to upload
+		body, writer := io.Pipe()
+		go func() {
+			for i := 0; i < 3; i++ {
+				time.Sleep(100 * time.Millisecond)
+				fmt.Fprintf(writer, "{\"longUrl\":\"https://www.example.com/%d\"}\n", i)
+			}
+			writer.Close()
+		}()
+
+		resp, err := http.Post(server.URL+path, "application/x-ndjson", body)
+		if err != nil {
+			t.Fatalf("POST %s: %s", path, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != expectation {
+			t.Errorf("POST %s: Expected status %d. Got: %d", path, expectation, resp.StatusCode)
+		}
+	}
+}
+
+func TestPreflightRequestsNotAnsweredForRedirects(t *testing.T) {
+
+	app := Init(":0", "", 5*time.Second)
+	app.Register(routeFunc(func(r *mux.Router) {
+		r.HandleFunc("/urlshortener/v1/url", func(w http.ResponseWriter, req *http.Request) {}).Methods("GET", "POST")
+	}))
+	app.RegisterRedirects(routeFunc(func(r *mux.Router) {
+		r.HandleFunc("/{shortUrl}", func(w http.ResponseWriter, req *http.Request) {}).Methods("GET")
+		r.HandleFunc("/{shortUrl}/{suffix:.*}", func(w http.ResponseWriter, req *http.Request) {}).Methods("GET")
+	}))
+	app.EnableCORS(CORSPolicy{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET", "POST"}})
+
+	for path, expectation := range map[string]int{
+		"/urlshortener/v1/url": http.StatusNoContent,
+		"/abc":                 http.StatusMethodNotAllowed,
+		"/abc/any/path":        http.StatusMethodNotAllowed,
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodOptions, path, nil)
+		req.Header.Set("Origin", "https://www.example.com")
+		req.Header.Set("Access-Control-Request-Method", "GET")
+		app.server.Handler.ServeHTTP(w, req)
+
+		if w.Code != expectation {
+			t.Errorf("OPTIONS %s: Expected status %d. Got: %d", path, expectation, w.Code)
+		}
+	}
+}
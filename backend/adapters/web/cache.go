@@ -0,0 +1,106 @@
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// noCache makes clients revalidate a response before they use it again
+const noCache = "private, max-age=0, no-cache"
+
+// RedirectCachePolicy decides how long clients and proxies may cache redirects.
+// Every link can be updated or disabled by an admin; a cached redirect keeps sending visitors to the old long url until it expires.
+type RedirectCachePolicy struct {
+	// PermanentMaxAge is how long clients and proxies may cache permanent redirects (301 and 308). They are not cached if it is 0.
+	PermanentMaxAge time.Duration
+	// TemporaryMaxAge is how long the visitor's browser may cache other redirects. They are not cached if it is 0,
+	// so that changes to the link and visits are not missed.
+	TemporaryMaxAge time.Duration
+}
+
+// DefaultRedirectCachePolicy caches permanent redirects for a day, and does not cache temporary redirects
+func DefaultRedirectCachePolicy() RedirectCachePolicy {
+	return RedirectCachePolicy{
+		PermanentMaxAge: 24 * time.Hour,
+	}
+}
+
+// CacheControl returns the Cache-Control header of the redirect to the long url of the response
+func (p RedirectCachePolicy) CacheControl(response usecase.RetrieveOriginalURLResponse) string {
+	// The next visitor may be sent elsewhere, so the redirect must not be stored by shared caches
+	if response.PerVisitor {
+		return noCache
+	}
+	if usecase.IsPermanentRedirectStatus(response.RedirectStatus) && p.PermanentMaxAge > 0 {
+		return fmt.Sprintf("public, max-age=%d", int64(p.PermanentMaxAge/time.Second))
+	}
+	if !usecase.IsPermanentRedirectStatus(response.RedirectStatus) && p.TemporaryMaxAge > 0 {
+		return fmt.Sprintf("private, max-age=%d", int64(p.TemporaryMaxAge/time.Second))
+	}
+	return noCache
+}
+
+// WeakETag identifies a response by the values that it was built from (e.g. the shortId and update time of a link).
+// It is weak because the response is equivalent, but not identical, in each of the formats that it is negotiated in.
+func WeakETag(values ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(values, "\x00")))
+	return `W/"` + hex.EncodeToString(hash[:16]) + `"`
+}
+
+// NotModified sets the ETag and Last-Modified headers of the response.
+// It responds with 304 Not Modified and returns true if the conditional headers of the request show that the client has the response already
+// (https://tools.ietf.org/html/rfc7232). If-Modified-Since is ignored if the request has If-None-Match.
+func NotModified(w http.ResponseWriter, req *http.Request, etag string, lastModified time.Time) bool {
+	if len(etag) > 0 {
+		w.Header().Set("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	if !isNotModified(req, etag, lastModified) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+func isNotModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := req.Header["If-None-Match"]; len(ifNoneMatch) > 0 {
+		return etagMatches(strings.Join(ifNoneMatch, ","), etag)
+	}
+
+	ifModifiedSince := req.Header.Get("If-Modified-Since")
+	if len(ifModifiedSince) == 0 || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	// Last-Modified only has whole seconds
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// etagMatches compares the tags in the If-None-Match header with the etag, ignoring whether they are weak
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if len(etag) == 0 {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
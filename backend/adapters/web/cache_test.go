@@ -0,0 +1,83 @@
+package web
+
+import (
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotModified(t *testing.T) {
+
+	lastModified := time.Date(2019, 11, 2, 10, 30, 15, 500, time.UTC)
+	etag := WeakETag("https://small.ml/shrt", "1572690615000000500")
+
+	testCases := []struct {
+		name        string
+		method      string
+		headers     map[string]string
+		notModified bool
+	}{
+		{"unconditional", "GET", nil, false},
+		{"matching etag", "GET", map[string]string{"If-None-Match": etag}, true},
+		{"matching strong etag", "GET", map[string]string{"If-None-Match": etag[2:]}, true},
+		{"matching etag in list", "GET", map[string]string{"If-None-Match": `"abc", ` + etag}, true},
+		{"any etag", "GET", map[string]string{"If-None-Match": "*"}, true},
+		{"other etag", "GET", map[string]string{"If-None-Match": `W/"abc"`}, false},
+		{"modified since", "GET", map[string]string{"If-Modified-Since": "Sat, 02 Nov 2019 10:30:14 GMT"}, false},
+		{"not modified since", "GET", map[string]string{"If-Modified-Since": "Sat, 02 Nov 2019 10:30:15 GMT"}, true},
+		{"invalid date", "GET", map[string]string{"If-Modified-Since": "yesterday"}, false},
+		{"etag takes precedence", "GET", map[string]string{"If-None-Match": `W/"abc"`, "If-Modified-Since": "Sat, 02 Nov 2019 10:30:15 GMT"}, false},
+		{"head", "HEAD", map[string]string{"If-None-Match": etag}, true},
+		{"post", "POST", map[string]string{"If-None-Match": etag}, false},
+	}
+
+	for _, testCase := range testCases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(testCase.method, "http://small.ml/urlshortener/v1/url?shortUrl=https://small.ml/shrt", nil)
+		for name, value := range testCase.headers {
+			req.Header.Set(name, value)
+		}
+
+		notModified := NotModified(w, req, etag, lastModified)
+
+		if notModified != testCase.notModified {
+			t.Errorf("%s: NotModified returned %t, want: %t", testCase.name, notModified, testCase.notModified)
+		}
+		if notModified && w.Code != http.StatusNotModified {
+			t.Errorf("%s: NotModified sent status %d, want: %d", testCase.name, w.Code, http.StatusNotModified)
+		}
+		if header := w.Header().Get("ETag"); header != etag {
+			t.Errorf("%s: NotModified sent ETag %s, want: %s", testCase.name, header, etag)
+		}
+		if header := w.Header().Get("Last-Modified"); header != "Sat, 02 Nov 2019 10:30:15 GMT" {
+			t.Errorf("%s: NotModified sent Last-Modified %s", testCase.name, header)
+		}
+	}
+}
+
+func TestRedirectCacheControl(t *testing.T) {
+
+	policy := RedirectCachePolicy{PermanentMaxAge: time.Hour, TemporaryMaxAge: time.Minute}
+
+	testCases := []struct {
+		name         string
+		policy       RedirectCachePolicy
+		response     usecase.RetrieveOriginalURLResponse
+		cacheControl string
+	}{
+		{"permanent", policy, usecase.RetrieveOriginalURLResponse{RedirectStatus: http.StatusMovedPermanently}, "public, max-age=3600"},
+		{"temporary", policy, usecase.RetrieveOriginalURLResponse{RedirectStatus: http.StatusSeeOther}, "private, max-age=60"},
+		{"per visitor", policy, usecase.RetrieveOriginalURLResponse{RedirectStatus: http.StatusPermanentRedirect, PerVisitor: true}, "private, max-age=0, no-cache"},
+		{"default permanent", DefaultRedirectCachePolicy(), usecase.RetrieveOriginalURLResponse{RedirectStatus: http.StatusPermanentRedirect}, "public, max-age=86400"},
+		{"default temporary", DefaultRedirectCachePolicy(), usecase.RetrieveOriginalURLResponse{RedirectStatus: http.StatusFound}, "private, max-age=0, no-cache"},
+		{"not cached", RedirectCachePolicy{}, usecase.RetrieveOriginalURLResponse{RedirectStatus: http.StatusMovedPermanently}, "private, max-age=0, no-cache"},
+	}
+
+	for _, testCase := range testCases {
+		if cacheControl := testCase.policy.CacheControl(testCase.response); cacheControl != testCase.cacheControl {
+			t.Errorf("%s: Cache-Control is %q, want: %q", testCase.name, cacheControl, testCase.cacheControl)
+		}
+	}
+}
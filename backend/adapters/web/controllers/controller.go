@@ -1,13 +1,20 @@
 package controllers
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
 	"database/sql"
+	"encoding/hex"
 	"github.com/gorilla/mux"
 	"github.com/w-k-s/short-url/adapters/logging"
 	"github.com/w-k-s/short-url/adapters/web"
+	"github.com/w-k-s/short-url/domain"
 	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
 	"github.com/w-k-s/short-url/log"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 // Shorten URL
@@ -23,17 +30,97 @@ func GetShortenURLHandler(useCase *usecase.ShortenURLUseCase, responseFmt web.Re
 	return func(w http.ResponseWriter, req *http.Request) {
 		shortenRequest, err := usecase.NewShortenURLRequest(req)
 		if err != nil {
-			responseFmt.Error(w, err)
+			responseFmt.Error(w, req, err)
 			return
 		}
 
 		shortenResponse, err := useCase.Execute(shortenRequest)
 		if err != nil {
-			responseFmt.Error(w, err)
+			responseFmt.Error(w, req, err)
 			return
 		}
 
-		responseFmt.Print(w, http.StatusOK, shortenResponse)
+		if shortenResponse.Replayed {
+			w.Header().Set("Idempotent-Replayed", "true")
+		}
+		responseFmt.Print(w, req, http.StatusOK, shortenResponse)
+	}
+}
+
+// Batch Shorten URLs
+
+type BatchShortenURLHandler http.HandlerFunc
+
+func (h BatchShortenURLHandler) Route(r *mux.Router) {
+	r.HandleFunc("/urlshortener/v1/urls:batch", h).
+		Methods("POST")
+}
+
+type batchResultBody struct {
+	Index  int                         `json:"index"`
+	Result *usecase.ShortenURLResponse `json:"result,omitempty"`
+	Error  map[string]interface{}      `json:"error,omitempty"`
+}
+
+// GetBatchShortenURLHandler streams the results as JSON or NDJSON, and writes errors with the batch itself in the response format
+func GetBatchShortenURLHandler(useCase *usecase.BatchShortenURLUseCase, streamingFmt web.StreamingFmt, responseFmt web.ResponseFmt) BatchShortenURLHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		batchRequest, err := usecase.NewBatchShortenURLRequest(req)
+		if err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		// Results are streamed in input order as each chunk is saved, so errors are reported per item
+		stream := streamingFmt.Stream(w, http.StatusOK, batchRequest.IsNDJSON())
+
+		writeErr := useCase.Execute(batchRequest, func(result usecase.BatchShortenURLResult) error {
+			body := batchResultBody{Index: result.Index}
+			if result.Err != nil {
+				body.Error = web.ErrorBody(result.Err)
+			} else {
+				body.Result = &result.Response
+			}
+			return stream.Write(body)
+		})
+		if writeErr == nil {
+			writeErr = stream.Close()
+		}
+		if writeErr != nil {
+			log.Printf("Failed to write batch results: %s", writeErr)
+		}
+	}
+}
+
+// List URLs
+
+type ListURLsHandler http.HandlerFunc
+
+func (h ListURLsHandler) Route(r *mux.Router) {
+	r.HandleFunc("/urlshortener/v1/urls", h).
+		Methods("GET")
+}
+
+func GetListURLsHandler(useCase *usecase.ListURLsUseCase, adminToken string, responseFmt web.ResponseFmt) ListURLsHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if err := authorize(req, adminToken); err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		listRequest, err := usecase.NewListURLsRequest(req)
+		if err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		listResponse, err := useCase.Execute(listRequest)
+		if err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		responseFmt.Print(w, req, http.StatusOK, listResponse)
 	}
 }
 
@@ -50,17 +137,161 @@ func GetRetrieveOriginalURLHandler(useCase *usecase.RetrieveOriginalURLUseCase,
 	return func(w http.ResponseWriter, req *http.Request) {
 		retrieveRequest, err := usecase.NewRetrieveOriginalURLRequest(req)
 		if err != nil {
-			responseFmt.Error(w, err)
+			responseFmt.Error(w, req, err)
 			return
 		}
 
 		retrieveResponse, err := useCase.Execute(retrieveRequest)
 		if err != nil {
-			responseFmt.Error(w, err)
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		if notModified(w, req, retrieveResponse) {
+			return
+		}
+		responseFmt.Print(w, req, http.StatusOK, retrieveResponse)
+	}
+}
+
+// notModified sets the validators of a looked up link, and responds with 304 Not Modified if the client has the link already.
+// Clients revalidate the link every time they use it, since an admin can change it at any time.
+func notModified(w http.ResponseWriter, req *http.Request, response usecase.RetrieveOriginalURLResponse) bool {
+	if response.PerVisitor {
+		w.Header().Set("Cache-Control", "private, no-cache")
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	w.Header().Add("Vary", "Accept")
+
+	etag := web.WeakETag(response.ShortURL, response.LongURL, response.Variant, strconv.FormatInt(response.UpdateTime.UnixNano(), 10))
+	return web.NotModified(w, req, etag, response.UpdateTime)
+}
+
+// Get Original URL by ShortId
+
+type RetrieveShortIDHandler http.HandlerFunc
+
+func (h RetrieveShortIDHandler) Route(r *mux.Router) {
+	r.HandleFunc("/urlshortener/v1/url/{shortId}", h).
+		Methods("GET")
+}
+
+func GetRetrieveShortIDHandler(useCase *usecase.RetrieveOriginalURLUseCase, responseFmt web.ResponseFmt) RetrieveShortIDHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		retrieveRequest, err := usecase.NewRetrieveShortIDRequest(mux.Vars(req)["shortId"], req)
+		if err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		retrieveResponse, err := useCase.Execute(retrieveRequest)
+		if err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		if notModified(w, req, retrieveResponse) {
+			return
+		}
+		responseFmt.Print(w, req, http.StatusOK, retrieveResponse)
+	}
+}
+
+// Update URL
+
+type UpdateURLHandler http.HandlerFunc
+
+func (h UpdateURLHandler) Route(r *mux.Router) {
+	r.HandleFunc("/urlshortener/v1/url/{shortId}", h).
+		Methods("PATCH")
+}
+
+func GetUpdateURLHandler(useCase *usecase.UpdateURLUseCase, adminToken string, responseFmt web.ResponseFmt) UpdateURLHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if err := authorize(req, adminToken); err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		updateRequest, err := usecase.NewUpdateURLRequest(mux.Vars(req)["shortId"], req)
+		if err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		updateResponse, err := useCase.Execute(updateRequest)
+		if err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		responseFmt.Print(w, req, http.StatusOK, updateResponse)
+	}
+}
+
+// Click Statistics
+
+type ClickStatsHandler http.HandlerFunc
+
+func (h ClickStatsHandler) Route(r *mux.Router) {
+	r.HandleFunc("/urlshortener/v1/url/{shortId}/stats", h).
+		Methods("GET")
+}
+
+func GetClickStatsHandler(useCase *usecase.ClickStatsUseCase, adminToken string, responseFmt web.ResponseFmt) ClickStatsHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if err := authorize(req, adminToken); err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		statsRequest, err := usecase.NewClickStatsRequest(mux.Vars(req)["shortId"], req)
+		if err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		statsResponse, err := useCase.Execute(statsRequest)
+		if err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		responseFmt.Print(w, req, http.StatusOK, statsResponse)
+	}
+}
+
+// QR Code
+
+type QRCodeHandler http.HandlerFunc
+
+func (h QRCodeHandler) Route(r *mux.Router) {
+	r.HandleFunc("/urlshortener/v1/url/{shortId}/qr", h).
+		Methods("GET")
+}
+
+func GetQRCodeHandler(useCase *usecase.QRCodeUseCase, responseFmt web.ResponseFmt) QRCodeHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		qrRequest, err := usecase.NewQRCodeRequest(mux.Vars(req)["shortId"], req)
+		if err != nil {
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		qrResponse, err := useCase.Execute(qrRequest)
+		if err != nil {
+			responseFmt.Error(w, req, err)
 			return
 		}
 
-		responseFmt.Print(w, http.StatusOK, retrieveResponse)
+		// The qr code of a link never changes, so it can be cached by clients and proxies.
+		// ServeContent answers If-None-Match and If-Modified-Since with 304 Not Modified.
+		hash := sha256.Sum256(qrResponse.Image)
+		w.Header().Set("Content-Type", qrResponse.ContentType)
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		w.Header().Set("ETag", `"`+hex.EncodeToString(hash[:16])+`"`)
+		http.ServeContent(w, req, "", qrResponse.CreateTime, bytes.NewReader(qrResponse.Image))
 	}
 }
 
@@ -71,21 +302,66 @@ type RedirectToOriginalURLHandler http.HandlerFunc
 func (h RedirectToOriginalURLHandler) Route(r *mux.Router) {
 	r.HandleFunc("/{shortUrl}", h).
 		Methods("GET")
+	// Wildcard links append the rest of the path to the long url
+	r.HandleFunc("/{shortUrl}/{suffix:.*}", h).
+		Methods("GET")
 }
 
-func GetRedirectToOriginalURLHandler(useCase *usecase.RetrieveOriginalURLUseCase, responseFmt web.ResponseFmt) RedirectToOriginalURLHandler {
+func GetRedirectToOriginalURLHandler(useCase *usecase.RetrieveOriginalURLUseCase, cachePolicy web.RedirectCachePolicy, responseFmt web.ResponseFmt) RedirectToOriginalURLHandler {
 	return func(w http.ResponseWriter, req *http.Request) {
-		redirectRequest := usecase.RedirectShortURLRequest(req.URL)
+		redirectRequest := usecase.NewRedirectRequest(req)
+		if usecase.IsPreviewRequest(req) {
+			redirectRequest = usecase.NewPreviewRequest(req)
+		}
 
 		redirectResponse, err := useCase.Execute(redirectRequest)
+		if err != nil && err.Code() == usecase.RetrieveFullURLDisabled {
+			web.WarningPage(
+				w,
+				http.StatusForbidden,
+				"This link has been disabled",
+				"The link you followed has been disabled, possibly because it was reported as phishing or malware.",
+				err.Fields()["reason"],
+			)
+			return
+		}
 		if err != nil {
-			responseFmt.Error(w, err)
+			responseFmt.Error(w, req, err)
+			return
+		}
+
+		if redirectRequest.IsPreview() {
+			web.PreviewPage(w, redirectResponse.ShortURL, redirectResponse.LongURL, redirectResponse.CreateTime, redirectResponse.Clicks)
+			return
+		}
+
+		if social := redirectResponse.Social; social != nil && usecase.IsCrawler(req.UserAgent()) {
+			web.SocialPage(w, cachePolicy.CacheControl(redirectResponse), redirectResponse.ShortURL, redirectResponse.LongURL, social.Title, social.Description, social.Image)
 			return
 		}
 
 		log.Printf("redirecting to %s\n", redirectResponse.LongURL)
-		http.Redirect(w, req, redirectResponse.LongURL, http.StatusSeeOther)
+		if len(redirectResponse.Variant) > 0 {
+			rememberVisitor(w, req, redirectResponse.VisitorID)
+		}
+		w.Header().Set("Cache-Control", cachePolicy.CacheControl(redirectResponse))
+		http.Redirect(w, req, redirectResponse.LongURL, redirectResponse.RedirectStatus)
+	}
+}
+
+// rememberVisitor sets the visitor cookie so that the visitor is sent to the same destination on their next visit,
+// even if their IP address changes.
+func rememberVisitor(w http.ResponseWriter, req *http.Request, visitorID string) {
+	if _, err := req.Cookie(usecase.VisitorCookie); err == nil {
+		return
 	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     usecase.VisitorCookie,
+		Value:    visitorID,
+		Path:     "/",
+		MaxAge:   365 * 24 * 60 * 60,
+		HttpOnly: true,
+	})
 }
 
 // Health Check
@@ -108,6 +384,22 @@ func GetHealthCheckHandler(db *sql.DB) HealthCheckHandler {
 	}
 }
 
+// Authorization
+
+// authorize checks that the request carries the admin token as a bearer token.
+// If no admin token is configured, all requests are unauthorized.
+func authorize(req *http.Request, adminToken string) domain.Err {
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if len(adminToken) == 0 || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		return usecase.NewError(
+			usecase.Unauthorized,
+			"A valid admin token is required",
+			nil,
+		)
+	}
+	return nil
+}
+
 // Middleware
 
 type LogRequestMiddleware mux.MiddlewareFunc
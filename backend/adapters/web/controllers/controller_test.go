@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"github.com/w-k-s/short-url/adapters/qrcode"
 	"github.com/w-k-s/short-url/adapters/web"
 	"github.com/w-k-s/short-url/domain"
 	u "github.com/w-k-s/short-url/domain/urlshortener"
@@ -15,6 +17,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 )
@@ -46,6 +49,16 @@ type MockURLRepository struct {
 
 	ShortURLRecordResult *u.URLRecord
 	ShortURLRecordError  error
+
+	ShortURLForOwnerRecordResult *u.URLRecord
+	ShortURLForOwnerRecordError  error
+
+	UpdateRecordError error
+
+	ListRecordsResult []*u.URLRecord
+	ListRecordsError  error
+	// ListRecordsQuery, if set, receives the query that records were listed with
+	ListRecordsQuery *u.RecordQuery
 }
 
 func (m MockURLRepository) SaveRecord(record *u.URLRecord) (*u.URLRecord, error) {
@@ -55,20 +68,106 @@ func (m MockURLRepository) SaveRecord(record *u.URLRecord) (*u.URLRecord, error)
 	return m.SaveURLRecordResult, nil
 }
 
-func (m MockURLRepository) LongURL(shortID string) (*u.URLRecord, error) {
+func (m MockURLRepository) SaveRecords(records []*u.URLRecord) ([]*u.URLRecord, error) {
+	if m.ReturnError {
+		return nil, m.SaveURLRecordError
+	}
+	return records, nil
+}
+
+func (m MockURLRepository) ListRecords(query u.RecordQuery) ([]*u.URLRecord, error) {
+	if m.ListRecordsQuery != nil {
+		*m.ListRecordsQuery = query
+	}
+	if m.ReturnError {
+		return nil, m.ListRecordsError
+	}
+	return m.ListRecordsResult, nil
+}
+
+func (m MockURLRepository) LongURL(domain string, shortID string) (*u.URLRecord, error) {
 	if m.ReturnError {
 		return nil, m.LongURLRecordError
 	}
 	return m.LongURLRecordResult, nil
 }
 
-func (m MockURLRepository) ShortURL(longURL string) (*u.URLRecord, error) {
+func (m MockURLRepository) ShortURL(domain string, longURL string) (*u.URLRecord, error) {
 	if m.ReturnError {
 		return nil, m.ShortURLRecordError
 	}
 	return m.ShortURLRecordResult, nil
 }
 
+func (m MockURLRepository) ShortURLForOwner(domain string, longURL string, owner string) (*u.URLRecord, error) {
+	if m.ReturnError {
+		return nil, m.ShortURLForOwnerRecordError
+	}
+	return m.ShortURLForOwnerRecordResult, nil
+}
+
+func (m MockURLRepository) UpdateRecord(record *u.URLRecord) error {
+	if m.ReturnError {
+		return m.UpdateRecordError
+	}
+	return nil
+}
+
+//-- MockAnalyticsRepository
+
+type MockAnalyticsRepository struct {
+	ReturnError bool
+
+	SavedClicks      []u.Click
+	SaveClickError   error
+	ClickCountsValue u.ClickCounts
+	ClickCountsError error
+}
+
+func (m *MockAnalyticsRepository) SaveClick(click *u.Click) error {
+	if m.ReturnError {
+		return m.SaveClickError
+	}
+	m.SavedClicks = append(m.SavedClicks, *click)
+	return nil
+}
+
+func (m *MockAnalyticsRepository) ClickCounts(domain string, shortID string) (u.ClickCounts, error) {
+	if m.ReturnError {
+		return nil, m.ClickCountsError
+	}
+	return m.ClickCountsValue, nil
+}
+
+//-- MockIdempotencyRepository
+
+type MockIdempotencyRepository struct {
+	Records map[string]*u.IdempotencyRecord
+}
+
+func (m *MockIdempotencyRepository) ReserveKey(record *u.IdempotencyRecord) (bool, error) {
+	if _, ok := m.Records[record.Owner+"/"+record.Key]; ok {
+		return false, nil
+	}
+	saved := *record
+	m.Records[record.Owner+"/"+record.Key] = &saved
+	return true, nil
+}
+
+func (m *MockIdempotencyRepository) FindKey(owner string, key string) (*u.IdempotencyRecord, error) {
+	return m.Records[owner+"/"+key], nil
+}
+
+func (m *MockIdempotencyRepository) SaveResponse(owner string, key string, response []byte) error {
+	m.Records[owner+"/"+key].Response = response
+	return nil
+}
+
+func (m *MockIdempotencyRepository) ReleaseKey(owner string, key string) error {
+	delete(m.Records, owner+"/"+key)
+	return nil
+}
+
 type ControllerSuite struct {
 	suite.Suite
 	urlRepo                    *MockURLRepository
@@ -85,7 +184,7 @@ func (suite *ControllerSuite) SetupTest() {
 
 	suite.urlRepo = &MockURLRepository{}
 	suite.shortenURLUseCase = usecase.NewShortenURLUseCase(suite.urlRepo, baseURL, suite.generator)
-	suite.retrieveOriginalURLUseCase = usecase.NewRetrieveOriginalURLUseCase(suite.urlRepo)
+	suite.retrieveOriginalURLUseCase = usecase.NewRetrieveOriginalURLUseCase(suite.urlRepo, usecase.WithDomainLookup(usecase.NewDomains(baseURL, nil, []string{"www.small.ml"})))
 
 	suite.record = &u.URLRecord{
 		LongURL:    savedLongURL,
@@ -152,6 +251,51 @@ func (suite *ControllerSuite) TestGivenRelativeLongURL_WhenShorteningURL_ThenRet
 
 }
 
+func (suite *ControllerSuite) TestGivenInvalidDedupeMode_WhenShorteningURL_ThenReturnError() {
+	//Given
+	jsonBytes := bytes.NewBuffer([]byte("{\"longUrl\":\"http://www.eg.com\",\"dedupe\":\"sometimes\"}"))
+
+	//When
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v", jsonBytes)
+	w := httptest.NewRecorder()
+	GetShortenURLHandler(suite.shortenURLUseCase, web.NewJsonFmt())(w, req)
+
+	//Then
+	err := getErrOrNil(w)
+	assert.NotNil(suite.T(), err, "ShortURL: Expected error; got nil")
+	assert.Equal(suite.T(), domain.Code(usecase.ShortenURLValidation), err.Code(), "Wrong error code. Expected: %d, got: %d", usecase.ShortenURLValidation, err.Code())
+}
+
+func (suite *ControllerSuite) TestGivenPerOwnerDedupeWithoutOwner_WhenShorteningURL_ThenReturnError() {
+	//Given
+	jsonBytes := bytes.NewBuffer([]byte("{\"longUrl\":\"http://www.eg.com\",\"dedupe\":\"per-owner\"}"))
+
+	//When
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v", jsonBytes)
+	w := httptest.NewRecorder()
+	GetShortenURLHandler(suite.shortenURLUseCase, web.NewJsonFmt())(w, req)
+
+	//Then
+	err := getErrOrNil(w)
+	assert.NotNil(suite.T(), err, "ShortURL: Expected error; got nil")
+	assert.Equal(suite.T(), domain.Code(usecase.ShortenURLValidation), err.Code(), "Wrong error code. Expected: %d, got: %d", usecase.ShortenURLValidation, err.Code())
+}
+
+func (suite *ControllerSuite) TestGivenIncompleteUTMParameters_WhenShorteningURL_ThenReturnError() {
+	//Given
+	jsonBytes := bytes.NewBuffer([]byte("{\"longUrl\":\"http://www.eg.com\",\"utm\":{\"source\":\"newsletter\"}}"))
+
+	//When
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v", jsonBytes)
+	w := httptest.NewRecorder()
+	GetShortenURLHandler(suite.shortenURLUseCase, web.NewJsonFmt())(w, req)
+
+	//Then
+	err := getErrOrNil(w)
+	assert.NotNil(suite.T(), err, "ShortURL: Expected error; got nil")
+	assert.Equal(suite.T(), domain.Code(usecase.ShortenURLValidation), err.Code(), "Wrong error code. Expected: %d, got: %d", usecase.ShortenURLValidation, err.Code())
+}
+
 func (suite *ControllerSuite) TestGivenLongURL_WhenShorteningURL_() {
 
 	//Given
@@ -177,6 +321,125 @@ func (suite *ControllerSuite) TestGivenLongURL_WhenShorteningURL_() {
 
 }
 
+func (suite *ControllerSuite) TestGivenFormEncodedLongURL_WhenShorteningURL_ThenReturnsShortURL() {
+
+	//Given
+	form := strings.NewReader("longUrl=http%3A%2F%2Fwww.eg.com&tags=summer&tags=sale&utm.source=newsletter&utm.medium=email&utm.campaign=launch")
+	suite.generator.ShortID = "unique"
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{
+		LongURL:    "http://www.eg.com",
+		ShortID:    "unique",
+		CreateTime: time.Now(),
+	}
+
+	//When
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v", form)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	GetShortenURLHandler(suite.shortenURLUseCase, web.NewJsonFmt())(w, req)
+
+	//Then
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	json := getJSONDictionaryOrNil(w)
+	assert.Contains(suite.T(), json["shortUrl"], suite.generator.ShortID)
+}
+
+func (suite *ControllerSuite) TestGivenXMLLongURL_WhenShorteningURLAcceptingXML_ThenReturnsXML() {
+
+	//Given
+	body := strings.NewReader("<request><longUrl>http://www.eg.com</longUrl><tags><item>summer</item></tags></request>")
+	suite.generator.ShortID = "unique"
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{
+		LongURL:    "http://www.eg.com",
+		ShortID:    "unique",
+		CreateTime: time.Now(),
+	}
+
+	//When
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v", body)
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Accept", "application/xml")
+	w := httptest.NewRecorder()
+	GetShortenURLHandler(suite.shortenURLUseCase, web.NewNegotiatingFmt())(w, req)
+
+	//Then
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "application/xml;charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(suite.T(), w.Body.String(), "<shortUrl>https://small.ml/unique</shortUrl>")
+}
+
+func (suite *ControllerSuite) TestGivenMalformedYAML_WhenShorteningURL_ThenReturnsDecodingError() {
+	//Given
+	body := strings.NewReader("longUrl: [http://www.eg.com")
+
+	//When
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v", body)
+	req.Header.Set("Content-Type", "application/yaml")
+	w := httptest.NewRecorder()
+	GetShortenURLHandler(suite.shortenURLUseCase, web.NewJsonFmt())(w, req)
+
+	//Then
+	err := getErrOrNil(w)
+	assert.NotNil(suite.T(), err, "ShortURL: Expected error; got nil")
+	assert.Equal(suite.T(), domain.Code(usecase.ShortenURLDecoding), err.Code())
+}
+
+func (suite *ControllerSuite) shortenWithIdempotencyKey(useCase *usecase.ShortenURLUseCase, body string, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v1/url", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", key)
+	w := httptest.NewRecorder()
+	GetShortenURLHandler(useCase, web.NewJsonFmt())(w, req)
+	return w
+}
+
+func (suite *ControllerSuite) TestGivenIdempotencyKey_WhenShorteningURLAgain_ThenResponseReplayed() {
+
+	//Given
+	baseURL, _ := url.Parse("https://small.ml")
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{
+		LongURL:    "http://www.eg.com",
+		ShortID:    "mine",
+		CreateTime: time.Now(),
+	}
+	useCase := usecase.NewShortenURLUseCase(suite.urlRepo, baseURL, suite.generator,
+		usecase.WithIdempotency(&MockIdempotencyRepository{Records: map[string]*u.IdempotencyRecord{}}, time.Hour))
+	first := suite.shortenWithIdempotencyKey(useCase, `{"longUrl":"http://www.eg.com","ShortId":"mine"}`, "retry-1")
+
+	//When
+	suite.urlRepo.ReturnError = true
+	suite.urlRepo.SaveURLRecordError = errors.New("duplicate key value violates unique constraint")
+	w := suite.shortenWithIdempotencyKey(useCase, `{"longUrl":"http://www.eg.com","ShortId":"mine"}`, "retry-1")
+
+	//Then
+	assert.Equal(suite.T(), http.StatusOK, first.Code)
+	assert.Empty(suite.T(), first.Header().Get("Idempotent-Replayed"))
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "true", w.Header().Get("Idempotent-Replayed"))
+	assert.Equal(suite.T(), first.Body.String(), w.Body.String())
+}
+
+func (suite *ControllerSuite) TestGivenIdempotencyKey_WhenShorteningDifferentURL_ThenUnprocessableEntity() {
+
+	//Given
+	baseURL, _ := url.Parse("https://small.ml")
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{
+		LongURL:    "http://www.eg.com",
+		ShortID:    "mine",
+		CreateTime: time.Now(),
+	}
+	useCase := usecase.NewShortenURLUseCase(suite.urlRepo, baseURL, suite.generator,
+		usecase.WithIdempotency(&MockIdempotencyRepository{Records: map[string]*u.IdempotencyRecord{}}, time.Hour))
+	suite.shortenWithIdempotencyKey(useCase, `{"longUrl":"http://www.eg.com","ShortId":"mine"}`, "retry-1")
+
+	//When
+	w := suite.shortenWithIdempotencyKey(useCase, `{"longUrl":"http://www.eg.com/other","ShortId":"mine"}`, "retry-1")
+
+	//Then
+	err := getErrOrNil(w)
+	assert.Equal(suite.T(), http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(suite.T(), usecase.ShortenURLIdempotencyKeyReused, int(err.Code()))
+}
+
 func (suite *ControllerSuite) TestGivenShortURLExists_WhenRedirecting_ThenSeeOtherResponse() {
 
 	//Given
@@ -185,13 +448,320 @@ func (suite *ControllerSuite) TestGivenShortURLExists_WhenRedirecting_ThenSeeOth
 	//When
 	req := httptest.NewRequest("GET", savedShortURL, nil)
 	w := httptest.NewRecorder()
-	GetRedirectToOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.NewJsonFmt())(w, req)
+	GetRedirectToOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt())(w, req)
 
 	//Then
 	resp := w.Result()
 	assert.Equal(suite.T(), resp.StatusCode, http.StatusSeeOther)
 }
 
+func (suite *ControllerSuite) TestGivenPermanentRedirectStatus_WhenRedirecting_ThenPermanentRedirectCached() {
+
+	//Given
+	suite.record.RedirectStatus = http.StatusMovedPermanently
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	req := httptest.NewRequest("GET", savedShortURL, nil)
+	w := httptest.NewRecorder()
+	GetRedirectToOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt())(w, req)
+
+	//Then
+	resp := w.Result()
+	assert.Equal(suite.T(), http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(suite.T(), savedLongURL, resp.Header.Get("Location"))
+	assert.Contains(suite.T(), resp.Header.Get("Cache-Control"), "public")
+}
+
+func (suite *ControllerSuite) TestGivenDefaultRedirectStatus_WhenRedirecting_ThenDefaultStatusNotCached() {
+
+	//Given
+	suite.urlRepo.LongURLRecordResult = suite.record
+	useCase := usecase.NewRetrieveOriginalURLUseCase(suite.urlRepo, usecase.WithDefaultRedirectStatus(http.StatusTemporaryRedirect))
+
+	//When
+	req := httptest.NewRequest("GET", savedShortURL, nil)
+	w := httptest.NewRecorder()
+	GetRedirectToOriginalURLHandler(useCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt())(w, req)
+
+	//Then
+	resp := w.Result()
+	assert.Equal(suite.T(), http.StatusTemporaryRedirect, resp.StatusCode)
+	assert.Contains(suite.T(), resp.Header.Get("Cache-Control"), "no-cache")
+}
+
+func (suite *ControllerSuite) TestGivenInvalidRedirectStatus_WhenShorteningURL_ThenReturnError() {
+	//Given
+	jsonBytes := bytes.NewBuffer([]byte("{\"longUrl\":\"http://www.eg.com\",\"redirectStatus\":200}"))
+
+	//When
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v", jsonBytes)
+	w := httptest.NewRecorder()
+	GetShortenURLHandler(suite.shortenURLUseCase, web.NewJsonFmt())(w, req)
+
+	//Then
+	err := getErrOrNil(w)
+	assert.NotNil(suite.T(), err, "ShortURL: Expected error; got nil")
+	assert.Equal(suite.T(), domain.Code(usecase.ShortenURLValidation), err.Code(), "Wrong error code. Expected: %d, got: %d", usecase.ShortenURLValidation, err.Code())
+}
+
+func (suite *ControllerSuite) TestGivenWildcardLink_WhenRedirectingWithPathAndQuery_ThenPathAndQueryForwarded() {
+
+	//Given
+	suite.record.Wildcard = true
+	suite.record.QueryMerge = string(usecase.QueryMergeKeep)
+	suite.urlRepo.LongURLRecordResult = suite.record
+	router := mux.NewRouter()
+	GetRedirectToOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt()).Route(router)
+
+	//When
+	req := httptest.NewRequest("GET", savedShortURL+"/extra/path?ref=mail", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	//Then
+	resp := w.Result()
+	assert.Equal(suite.T(), http.StatusSeeOther, resp.StatusCode)
+	assert.Equal(suite.T(), savedLongURL+"/extra/path?ref=mail", resp.Header.Get("Location"))
+}
+
+func (suite *ControllerSuite) TestGivenTargetingRules_WhenRedirectingFromIPhone_ThenAppStoreLocation() {
+
+	//Given
+	suite.record.TargetingRules = []u.TargetingRule{
+		{Platform: string(usecase.PlatformIOS), LongURL: "https://apps.apple.com/app/example"},
+	}
+	suite.urlRepo.LongURLRecordResult = suite.record
+	router := mux.NewRouter()
+	GetRedirectToOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt()).Route(router)
+
+	//When
+	req := httptest.NewRequest("GET", savedShortURL, nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (iPhone; CPU iPhone OS 13_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	//Then
+	resp := w.Result()
+	assert.Equal(suite.T(), http.StatusSeeOther, resp.StatusCode)
+	assert.Equal(suite.T(), "https://apps.apple.com/app/example", resp.Header.Get("Location"))
+}
+
+func (suite *ControllerSuite) TestGivenPermanentLinkWithTargetingRules_WhenRedirecting_ThenNotCachedByProxies() {
+
+	//Given
+	suite.record.RedirectStatus = http.StatusMovedPermanently
+	suite.record.TargetingRules = []u.TargetingRule{
+		{Platform: string(usecase.PlatformIOS), LongURL: "https://apps.apple.com/app/example"},
+	}
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	req := httptest.NewRequest("GET", savedShortURL, nil)
+	w := httptest.NewRecorder()
+	GetRedirectToOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt())(w, req)
+
+	//Then
+	resp := w.Result()
+	assert.Equal(suite.T(), http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(suite.T(), "private, max-age=0, no-cache", resp.Header.Get("Cache-Control"))
+}
+
+func (suite *ControllerSuite) TestGivenShortURLDisabled_WhenRedirecting_ThenWarningPage() {
+
+	//Given
+	suite.record.Disabled = true
+	suite.record.DisabledReason = "Reported as phishing"
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	req := httptest.NewRequest("GET", savedShortURL, nil)
+	w := httptest.NewRecorder()
+	GetRedirectToOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt())(w, req)
+
+	//Then
+	resp := w.Result()
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+	assert.Equal(suite.T(), "text/html;charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(suite.T(), w.Body.String(), "Reported as phishing")
+	assert.NotContains(suite.T(), w.Body.String(), savedLongURL)
+}
+
+func (suite *ControllerSuite) TestGivenNoAdminToken_WhenUpdatingURL_ThenUnauthorized() {
+
+	//Given
+	jsonBytes := bytes.NewBuffer([]byte("{\"disabled\":true}"))
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	req := httptest.NewRequest("PATCH", "http://small.ml/urlshortener/v1/url/"+savedShortID, jsonBytes)
+	req = mux.SetURLVars(req, map[string]string{"shortId": savedShortID})
+	w := httptest.NewRecorder()
+	GetUpdateURLHandler(usecase.NewUpdateURLUseCase(suite.urlRepo, usecase.NewDomains(nil, nil, nil), usecase.DefaultDestinationPolicy(), nil), "secret", web.NewJsonFmt())(w, req)
+
+	//Then
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Result().StatusCode)
+	assert.False(suite.T(), suite.record.Disabled)
+}
+
+func (suite *ControllerSuite) TestGivenAdminToken_WhenDisablingURL_ThenURLDisabled() {
+
+	//Given
+	jsonBytes := bytes.NewBuffer([]byte("{\"disabled\":true,\"disabledReason\":\"phishing\"}"))
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	req := httptest.NewRequest("PATCH", "http://small.ml/urlshortener/v1/url/"+savedShortID, jsonBytes)
+	req = mux.SetURLVars(req, map[string]string{"shortId": savedShortID})
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	GetUpdateURLHandler(usecase.NewUpdateURLUseCase(suite.urlRepo, usecase.NewDomains(nil, nil, nil), usecase.DefaultDestinationPolicy(), nil), "secret", web.NewJsonFmt())(w, req)
+
+	//Then
+	assert.Equal(suite.T(), http.StatusOK, w.Result().StatusCode)
+	json := getJSONDictionaryOrNil(w)
+	assert.Equal(suite.T(), true, json["disabled"])
+	assert.True(suite.T(), suite.record.Disabled)
+}
+
+func (suite *ControllerSuite) TestGivenSplitDestinations_WhenRedirecting_ThenVisitorRemembered() {
+
+	//Given
+	suite.record.Destinations = []u.Destination{
+		{Variant: "A", LongURL: "https://example.com/a", Weight: 1},
+		{Variant: "B", LongURL: "https://example.com/b", Weight: 1},
+	}
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	req := httptest.NewRequest("GET", savedShortURL, nil)
+	req.Header.Set("User-Agent", "curl/7.64.1")
+	w := httptest.NewRecorder()
+	GetRedirectToOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt())(w, req)
+
+	//Then
+	resp := w.Result()
+	assert.Equal(suite.T(), http.StatusSeeOther, resp.StatusCode)
+	assert.Contains(suite.T(), []string{"https://example.com/a", "https://example.com/b"}, resp.Header.Get("Location"))
+	assert.Equal(suite.T(), "private, max-age=0, no-cache", resp.Header.Get("Cache-Control"))
+	if assert.Len(suite.T(), resp.Cookies(), 1) {
+		assert.Equal(suite.T(), usecase.VisitorCookie, resp.Cookies()[0].Name)
+		assert.NotEmpty(suite.T(), resp.Cookies()[0].Value)
+	}
+}
+
+func (suite *ControllerSuite) TestGivenAdminToken_WhenRetrievingClickStats_ThenClicksReturned() {
+
+	//Given
+	suite.urlRepo.LongURLRecordResult = suite.record
+	analytics := &MockAnalyticsRepository{ClickCountsValue: u.ClickCounts{"": 3}}
+
+	//When
+	req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/url/"+savedShortID+"/stats", nil)
+	req = mux.SetURLVars(req, map[string]string{"shortId": savedShortID})
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	GetClickStatsHandler(usecase.NewClickStatsUseCase(suite.urlRepo, usecase.NewDomains(nil, nil, nil), analytics), "secret", web.NewJsonFmt())(w, req)
+
+	//Then
+	assert.Equal(suite.T(), http.StatusOK, w.Result().StatusCode)
+	json := getJSONDictionaryOrNil(w)
+	assert.Equal(suite.T(), float64(3), json["clicks"])
+}
+
+func (suite *ControllerSuite) TestGivenShortURLExists_WhenRequestingQRCode_ThenCacheablePNG() {
+
+	//Given
+	baseURL, _ := url.Parse("https://small.ml")
+	suite.urlRepo.LongURLRecordResult = suite.record
+	handler := GetQRCodeHandler(usecase.NewQRCodeUseCase(suite.urlRepo, usecase.NewDomains(baseURL, nil, nil), qrcode.Renderer{}), web.NewJsonFmt())
+
+	//When
+	req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/url/"+savedShortID+"/qr", nil)
+	req = mux.SetURLVars(req, map[string]string{"shortId": savedShortID})
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	//Then
+	resp := w.Result()
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "image/png", resp.Header.Get("Content-Type"))
+	assert.Equal(suite.T(), "public, max-age=86400", resp.Header.Get("Cache-Control"))
+	assert.NotEmpty(suite.T(), resp.Header.Get("Last-Modified"))
+
+	//When
+	req.Header.Set("If-None-Match", resp.Header.Get("ETag"))
+	w = httptest.NewRecorder()
+	handler(w, req)
+
+	//Then
+	assert.Equal(suite.T(), http.StatusNotModified, w.Result().StatusCode)
+}
+
+func (suite *ControllerSuite) TestGivenPreviewSuffix_WhenRedirecting_ThenPreviewPage() {
+
+	//Given
+	suite.urlRepo.LongURLRecordResult = suite.record
+	analytics := &MockAnalyticsRepository{ClickCountsValue: u.ClickCounts{"": 42}}
+	useCase := usecase.NewRetrieveOriginalURLUseCase(suite.urlRepo, usecase.WithClickTracking(analytics))
+
+	//When
+	req := httptest.NewRequest("GET", savedShortURL+"+", nil)
+	w := httptest.NewRecorder()
+	GetRedirectToOriginalURLHandler(useCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt())(w, req)
+
+	//Then
+	resp := w.Result()
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "text/html;charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Empty(suite.T(), resp.Header.Get("Location"))
+	assert.Contains(suite.T(), w.Body.String(), `href="`+savedLongURL+`"`)
+	assert.Contains(suite.T(), w.Body.String(), "<dd>42</dd>")
+	assert.Contains(suite.T(), w.Body.String(), suite.record.CreateTime.UTC().Format("2 January 2006"))
+	assert.Empty(suite.T(), analytics.SavedClicks)
+}
+
+func (suite *ControllerSuite) TestGivenCrawler_WhenRedirecting_ThenSocialPage() {
+
+	//Given
+	suite.record.Social = u.SocialMetadata{Title: "Fish & Chips", Description: "The best fish in town", ImageURL: "https://www.example.com/card.png"}
+	suite.urlRepo.LongURLRecordResult = suite.record
+	useCase := usecase.NewRetrieveOriginalURLUseCase(suite.urlRepo)
+
+	//When
+	req := httptest.NewRequest("GET", savedShortURL, nil)
+	req.Header.Set("User-Agent", "facebookexternalhit/1.1")
+	w := httptest.NewRecorder()
+	GetRedirectToOriginalURLHandler(useCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt())(w, req)
+
+	//Then
+	resp := w.Result()
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "User-Agent", resp.Header.Get("Vary"))
+	assert.Equal(suite.T(), "private, max-age=0, no-cache", resp.Header.Get("Cache-Control"))
+	assert.Contains(suite.T(), w.Body.String(), `<meta property="og:title" content="Fish &amp; Chips">`)
+	assert.Contains(suite.T(), w.Body.String(), `<meta property="og:description" content="The best fish in town">`)
+	assert.Contains(suite.T(), w.Body.String(), `<meta property="og:image" content="https://www.example.com/card.png">`)
+	assert.Contains(suite.T(), w.Body.String(), `<meta name="twitter:card" content="summary_large_image">`)
+}
+
+func (suite *ControllerSuite) TestGivenBrowser_WhenRedirectingLinkWithSocialMetadata_ThenRedirected() {
+
+	//Given
+	suite.record.Social = u.SocialMetadata{Title: "Fish & Chips"}
+	suite.urlRepo.LongURLRecordResult = suite.record
+	useCase := usecase.NewRetrieveOriginalURLUseCase(suite.urlRepo)
+
+	//When
+	req := httptest.NewRequest("GET", savedShortURL, nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64)")
+	w := httptest.NewRecorder()
+	GetRedirectToOriginalURLHandler(useCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt())(w, req)
+
+	//Then
+	assert.Equal(suite.T(), savedLongURL, w.Result().Header.Get("Location"))
+}
+
 func (suite *ControllerSuite) TestGivenShortURLDoesNotExist_WhenRedirecting_ThenNotFoundResponse() {
 	//Given
 	suite.urlRepo.ReturnError = true
@@ -200,7 +770,7 @@ func (suite *ControllerSuite) TestGivenShortURLDoesNotExist_WhenRedirecting_Then
 	//When
 	req := httptest.NewRequest("GET", "http://www.small.ml/nil", nil)
 	w := httptest.NewRecorder()
-	GetRedirectToOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.NewJsonFmt())(w, req)
+	GetRedirectToOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.DefaultRedirectCachePolicy(), web.NewJsonFmt())(w, req)
 
 	//Then
 	resp := w.Result()
@@ -267,6 +837,93 @@ func (suite *ControllerSuite) TestGivenShortURLDoesNotExist_WhenGetLongURLReques
 	assert.Equal(suite.T(), domain.Code(usecase.RetrieveFullURLNotFound), err.Code(), "Wrong error code. Expected: %d, got: %d", usecase.RetrieveFullURLNotFound, err.Code())
 }
 
+func (suite *ControllerSuite) TestGivenUnknownDomain_WhenGetLongURLRequest_ThenRetrieveFullURLDomainNotAllowedError() {
+	//Given
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	req := httptest.NewRequest("GET", "http://www.small.ml?shortUrl=http://www.evil.com/"+savedShortID, nil)
+	w := httptest.NewRecorder()
+	GetRetrieveOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.NewJsonFmt())(w, req)
+
+	//Then
+	err := getErrOrNil(w)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	assert.NotNil(suite.T(), err, "ShortURL: Expected error; got nil")
+	assert.Equal(suite.T(), domain.Code(usecase.RetrieveFullURLDomainNotAllowed), err.Code(), "Wrong error code. Expected: %d, got: %d", usecase.RetrieveFullURLDomainNotAllowed, err.Code())
+}
+
+func (suite *ControllerSuite) TestGivenShortID_WhenGetLongURLByShortIDRequest_ThenReturnOriginalURL() {
+	//Given
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/url/"+savedShortID, nil)
+	req = mux.SetURLVars(req, map[string]string{"shortId": savedShortID})
+	w := httptest.NewRecorder()
+	GetRetrieveShortIDHandler(suite.retrieveOriginalURLUseCase, web.NewJsonFmt())(w, req)
+
+	//Then
+	JSONDictionary := getJSONDictionaryOrNil(w)
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), savedLongURL, JSONDictionary["longUrl"])
+	assert.Equal(suite.T(), "https://small.ml/"+savedShortID, JSONDictionary["shortUrl"])
+}
+
+func (suite *ControllerSuite) TestGivenETagOfLink_WhenGetLongURLRequestIfNoneMatch_ThenNotModified() {
+	//Given
+	suite.record.UpdateTime = time.Date(2019, 11, 2, 10, 30, 15, 0, time.UTC)
+	suite.urlRepo.LongURLRecordResult = suite.record
+	handler := GetRetrieveOriginalURLHandler(suite.retrieveOriginalURLUseCase, web.NewJsonFmt())
+
+	first := httptest.NewRecorder()
+	handler(first, httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/url?shortUrl="+savedShortURL, nil))
+	etag := first.Header().Get("ETag")
+
+	//When
+	req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/url?shortUrl="+savedShortURL, nil)
+	req.Header.Set("If-None-Match", etag)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	//Then
+	assert.Equal(suite.T(), http.StatusOK, first.Code)
+	assert.NotEmpty(suite.T(), etag)
+	assert.Equal(suite.T(), "Sat, 02 Nov 2019 10:30:15 GMT", first.Header().Get("Last-Modified"))
+	assert.Equal(suite.T(), http.StatusNotModified, w.Code)
+	assert.Empty(suite.T(), w.Body.String())
+}
+
+func (suite *ControllerSuite) TestGivenLinkUpdatedSinceLastLookup_WhenGetLongURLRequestIfModifiedSince_ThenLinkReturned() {
+	//Given
+	suite.record.UpdateTime = time.Date(2019, 11, 2, 10, 30, 15, 0, time.UTC)
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/url/"+savedShortID, nil)
+	req = mux.SetURLVars(req, map[string]string{"shortId": savedShortID})
+	req.Header.Set("If-Modified-Since", "Sat, 02 Nov 2019 10:00:00 GMT")
+	w := httptest.NewRecorder()
+	GetRetrieveShortIDHandler(suite.retrieveOriginalURLUseCase, web.NewJsonFmt())(w, req)
+
+	//Then
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), savedLongURL, getJSONDictionaryOrNil(w)["longUrl"])
+}
+
+func (suite *ControllerSuite) TestGivenUnknownDomain_WhenGetLongURLByShortIDRequest_ThenRetrieveFullURLDomainNotAllowedError() {
+	//When
+	req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/url/"+savedShortID+"?domain=evil.com", nil)
+	req = mux.SetURLVars(req, map[string]string{"shortId": savedShortID})
+	w := httptest.NewRecorder()
+	GetRetrieveShortIDHandler(suite.retrieveOriginalURLUseCase, web.NewJsonFmt())(w, req)
+
+	//Then
+	err := getErrOrNil(w)
+	assert.NotNil(suite.T(), err, "ShortURL: Expected error; got nil")
+	assert.Equal(suite.T(), domain.Code(usecase.RetrieveFullURLDomainNotAllowed), err.Code(), "Wrong error code. Expected: %d, got: %d", usecase.RetrieveFullURLDomainNotAllowed, err.Code())
+}
+
 func getJSONDictionaryOrNil(w *httptest.ResponseRecorder) map[string]interface{} {
 	var JSONDictionary map[string]interface{}
 
@@ -293,3 +950,99 @@ func getErrOrNil(w *httptest.ResponseRecorder) domain.Err {
 
 	return domain.NewError(domain.Code(code), domainString, message, fields)
 }
+
+func (suite *ControllerSuite) TestGivenJSONArray_WhenBatchShortening_ThenResultsReturnedInOrder() {
+	//Given
+	suite.generator.ShortID = "batch"
+	body := bytes.NewBufferString(`[{"longUrl": "https://www.example.com/1"}, {"longUrl": "/relative"}]`)
+	useCase := usecase.NewBatchShortenURLUseCase(suite.shortenURLUseCase)
+
+	//When
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v1/urls:batch", body)
+	w := httptest.NewRecorder()
+	GetBatchShortenURLHandler(useCase, web.NewJsonFmt(), web.NewJsonFmt())(w, req)
+
+	//Then
+	var results []struct {
+		Index  int
+		Result usecase.ShortenURLResponse
+		Error  map[string]interface{}
+	}
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Nil(suite.T(), json.Unmarshal(w.Body.Bytes(), &results), "Expected a JSON array, got %s", w.Body.String())
+	if assert.Len(suite.T(), results, 2) {
+		assert.Equal(suite.T(), "https://small.ml/batch", results[0].Result.ShortURL)
+		assert.Equal(suite.T(), 1, results[1].Index)
+		assert.Equal(suite.T(), float64(usecase.ShortenURLValidation), results[1].Error["code"])
+	}
+}
+
+func (suite *ControllerSuite) TestGivenNDJSON_WhenBatchShortening_ThenOneResultPerLine() {
+	//Given
+	suite.generator.ShortID = "batch"
+	body := bytes.NewBufferString("{\"longUrl\": \"https://www.example.com/1\"}\n{\"longUrl\": \"https://www.example.com/2\"}\n")
+	useCase := usecase.NewBatchShortenURLUseCase(suite.shortenURLUseCase)
+
+	//When
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v1/urls:batch", body)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	GetBatchShortenURLHandler(useCase, web.NewJsonFmt(), web.NewJsonFmt())(w, req)
+
+	//Then
+	assert.Equal(suite.T(), "application/x-ndjson;charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(suite.T(), "{\"index\":0,\"result\":{\"longUrl\":\"https://www.example.com/1\",\"shortUrl\":\"https://small.ml/batch\"}}\n"+
+		"{\"index\":1,\"result\":{\"longUrl\":\"https://www.example.com/2\",\"shortUrl\":\"https://small.ml/batch\"}}\n", w.Body.String())
+}
+
+func (suite *ControllerSuite) TestGivenBodyIsNotAnArray_WhenBatchShortening_ThenReturnError() {
+	//Given
+	body := bytes.NewBufferString(`{"longUrl": "https://www.example.com/1"}`)
+	useCase := usecase.NewBatchShortenURLUseCase(suite.shortenURLUseCase)
+
+	//When
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v1/urls:batch", body)
+	w := httptest.NewRecorder()
+	GetBatchShortenURLHandler(useCase, web.NewJsonFmt(), web.NewJsonFmt())(w, req)
+
+	//Then
+	err := getErrOrNil(w)
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	assert.Equal(suite.T(), domain.Code(usecase.BatchShortenURLDecoding), err.Code())
+}
+
+func (suite *ControllerSuite) TestGivenAdminToken_WhenListingURLs_ThenPageReturned() {
+
+	//Given
+	baseURL, _ := url.Parse("https://small.ml")
+	query := &u.RecordQuery{}
+	suite.urlRepo.ListRecordsQuery = query
+	suite.urlRepo.ListRecordsResult = []*u.URLRecord{suite.record}
+
+	//When
+	req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/urls?owner=alice&host=example.com&sort=longUrl", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	GetListURLsHandler(usecase.NewListURLsUseCase(suite.urlRepo, usecase.NewDomains(baseURL, nil, nil)), "secret", web.NewJsonFmt())(w, req)
+
+	//Then
+	assert.Equal(suite.T(), http.StatusOK, w.Result().StatusCode)
+	assert.Equal(suite.T(), "alice", query.Owner)
+	assert.Equal(suite.T(), u.SortByLongURL, query.SortBy)
+	json := getJSONDictionaryOrNil(w)
+	if urls, ok := json["urls"].([]interface{}); assert.True(suite.T(), ok) && assert.Len(suite.T(), urls, 1) {
+		assert.Equal(suite.T(), "https://small.ml/"+savedShortID, urls[0].(map[string]interface{})["shortUrl"])
+	}
+	assert.Nil(suite.T(), json["nextCursor"])
+}
+
+func (suite *ControllerSuite) TestGivenNoAdminToken_WhenListingURLs_ThenUnauthorized() {
+
+	//When
+	req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/urls", nil)
+	w := httptest.NewRecorder()
+	GetListURLsHandler(usecase.NewListURLsUseCase(suite.urlRepo, usecase.Domains{}), "secret", web.NewJsonFmt())(w, req)
+
+	//Then
+	assert.Equal(suite.T(), http.StatusUnauthorized, w.Result().StatusCode)
+}
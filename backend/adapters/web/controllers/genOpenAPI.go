@@ -0,0 +1,42 @@
+//go:build ignore
+// +build ignore
+
+// genOpenAPI writes openapi.json into openapiSpec.go as a string constant, so that the spec is compiled into the binary
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	spec, err := ioutil.ReadFile("openapi.json")
+	if err != nil {
+		log.Fatalf("Failed to read openapi.json: %s", err)
+	}
+
+	lines := strings.SplitAfter(string(spec), "\n")
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	var source bytes.Buffer
+	source.WriteString("// Code generated by genOpenAPI.go from openapi.json; DO NOT EDIT.\n\n")
+	source.WriteString("package controllers\n\n")
+	source.WriteString("// openAPISpec describes every route. TestEveryRouteHasOpenAPIEntry fails if a route is missing from it.\n")
+	source.WriteString("const openAPISpec = \"\" +\n")
+	for i, line := range lines {
+		source.WriteString("\t" + strconv.Quote(line))
+		if i < len(lines)-1 {
+			source.WriteString(" +")
+		}
+		source.WriteString("\n")
+	}
+
+	if err := ioutil.WriteFile("openapiSpec.go", source.Bytes(), 0644); err != nil {
+		log.Fatalf("Failed to write openapiSpec.go: %s", err)
+	}
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"github.com/w-k-s/short-url/adapters/web"
+	"net/http"
+)
+
+// openapiSpec.go is generated from openapi.json; run `go generate` after changing openapi.json
+//go:generate go run genOpenAPI.go
+
+// OpenAPI Specification
+
+type OpenAPIHandler http.HandlerFunc
+
+func (h OpenAPIHandler) Route(r *mux.Router) {
+	r.HandleFunc("/urlshortener/v1/openapi.json", h).
+		Methods("GET")
+}
+
+func GetOpenAPIHandler(responseFmt web.ResponseFmt) OpenAPIHandler {
+	return func(w http.ResponseWriter, req *http.Request) {
+		responseFmt.Print(w, req, http.StatusOK, json.RawMessage(openAPISpec))
+	}
+}
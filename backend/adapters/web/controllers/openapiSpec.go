@@ -0,0 +1,510 @@
+// Code generated by genOpenAPI.go from openapi.json; DO NOT EDIT.
+
+package controllers
+
+// openAPISpec describes every route. TestEveryRouteHasOpenAPIEntry fails if a route is missing from it.
+const openAPISpec = "" +
+	"{\n" +
+	"  \"openapi\": \"3.0.3\",\n" +
+	"  \"info\": {\n" +
+	"    \"title\": \"Short URL\",\n" +
+	"    \"description\": \"Shortens urls and redirects visitors of short urls to the original urls. Admin endpoints require the ADMIN_TOKEN as a bearer token. Routes are relative to PATH_PREFIX if one is configured. Responses are JSON unless the Accept header prefers XML, YAML or MessagePack.\",\n" +
+	"    \"version\": \"1.0.0\"\n" +
+	"  },\n" +
+	"  \"paths\": {\n" +
+	"    \"/health\": {\n" +
+	"      \"get\": {\n" +
+	"        \"summary\": \"Checks that the service can reach its database\",\n" +
+	"        \"operationId\": \"healthCheck\",\n" +
+	"        \"responses\": {\n" +
+	"          \"200\": {\"description\": \"The service is healthy\"},\n" +
+	"          \"500\": {\"description\": \"The database can not be reached\", \"content\": {\"text/plain\": {\"schema\": {\"type\": \"string\"}}}}\n" +
+	"        }\n" +
+	"      }\n" +
+	"    },\n" +
+	"    \"/urlshortener/v1/openapi.json\": {\n" +
+	"      \"get\": {\n" +
+	"        \"summary\": \"Returns this OpenAPI document\",\n" +
+	"        \"operationId\": \"getOpenAPI\",\n" +
+	"        \"responses\": {\n" +
+	"          \"200\": {\"description\": \"The OpenAPI document\", \"content\": {\"application/json\": {\"schema\": {\"type\": \"object\"}}}}\n" +
+	"        }\n" +
+	"      }\n" +
+	"    },\n" +
+	"    \"/urlshortener/v1/url\": {\n" +
+	"      \"post\": {\n" +
+	"        \"summary\": \"Shortens a url\",\n" +
+	"        \"operationId\": \"shortenUrl\",\n" +
+	"        \"parameters\": [\n" +
+	"          {\"name\": \"Idempotency-Key\", \"in\": \"header\", \"description\": \"A unique key (e.g. a UUID) that makes retries of the request safe. The response to the first request with the key is saved for IDEMPOTENCY_KEY_TTL and returned to requests of the same `owner` that are sent with the key again. Failed requests are not saved.\", \"schema\": {\"type\": \"string\", \"maxLength\": 255}}\n" +
+	"        ],\n" +
+	"        \"requestBody\": {\n" +
+	"          \"required\": true,\n" +
+	"          \"description\": \"Form fields of nested objects are separated by dots (e.g. `utm.source`) and lists are repeated fields (e.g. `tags=a&tags=b`). Lists in XML are `<item>` elements, and the keys of `metadata` are `<entry key=\\\"...\\\">` elements.\",\n" +
+	"          \"content\": {\n" +
+	"            \"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/ShortenURLRequest\"}},\n" +
+	"            \"application/x-www-form-urlencoded\": {\"schema\": {\"$ref\": \"#/components/schemas/ShortenURLRequest\"}},\n" +
+	"            \"multipart/form-data\": {\"schema\": {\"$ref\": \"#/components/schemas/ShortenURLRequest\"}},\n" +
+	"            \"application/xml\": {\"schema\": {\"$ref\": \"#/components/schemas/ShortenURLRequest\"}},\n" +
+	"            \"application/yaml\": {\"schema\": {\"$ref\": \"#/components/schemas/ShortenURLRequest\"}},\n" +
+	"            \"application/msgpack\": {\"schema\": {\"$ref\": \"#/components/schemas/ShortenURLRequest\"}}\n" +
+	"          }\n" +
+	"        },\n" +
+	"        \"responses\": {\n" +
+	"          \"200\": {\n" +
+	"            \"description\": \"The short url, in the format that the Accept header prefers\",\n" +
+	"            \"content\": {\n" +
+	"              \"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/ShortenURLResponse\"}},\n" +
+	"              \"application/xml\": {\"schema\": {\"$ref\": \"#/components/schemas/ShortenURLResponse\"}},\n" +
+	"              \"application/yaml\": {\"schema\": {\"$ref\": \"#/components/schemas/ShortenURLResponse\"}},\n" +
+	"              \"application/msgpack\": {\"schema\": {\"$ref\": \"#/components/schemas/ShortenURLResponse\"}}\n" +
+	"            },\n" +
+	"            \"headers\": {\"Idempotent-Replayed\": {\"description\": \"`true` if the response was saved for an earlier request with the same Idempotency-Key\", \"schema\": {\"type\": \"string\", \"enum\": [\"true\"]}}}\n" +
+	"          },\n" +
+	"          \"400\": {\"$ref\": \"#/components/responses/BadRequest\"},\n" +
+	"          \"409\": {\"description\": \"The first request with the same Idempotency-Key has not completed yet\", \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/Error\"}}, \"application/problem+json\": {\"schema\": {\"$ref\": \"#/components/schemas/Problem\"}}}},\n" +
+	"          \"422\": {\"description\": \"The Idempotency-Key was first sent with a different request\", \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/Error\"}}, \"application/problem+json\": {\"schema\": {\"$ref\": \"#/components/schemas/Problem\"}}}},\n" +
+	"          \"500\": {\"$ref\": \"#/components/responses/InternalServerError\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"get\": {\n" +
+	"        \"summary\": \"Looks up the long url of a short url\",\n" +
+	"        \"operationId\": \"retrieveOriginalUrl\",\n" +
+	"        \"parameters\": [\n" +
+	"          {\"name\": \"shortUrl\", \"in\": \"query\", \"required\": true, \"description\": \"The absolute short url e.g. `https://small.ml/abc`. Its host must be one of the domains that urls are shortened on.\", \"schema\": {\"type\": \"string\", \"format\": \"uri\"}},\n" +
+	"          {\"$ref\": \"#/components/parameters/IfNoneMatch\"},\n" +
+	"          {\"$ref\": \"#/components/parameters/IfModifiedSince\"}\n" +
+	"        ],\n" +
+	"        \"responses\": {\n" +
+	"          \"200\": {\"description\": \"The long url\", \"headers\": {\"ETag\": {\"$ref\": \"#/components/headers/ETag\"}, \"Last-Modified\": {\"$ref\": \"#/components/headers/LastModified\"}}, \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/RetrieveOriginalURLResponse\"}}}},\n" +
+	"          \"304\": {\"description\": \"The link has not changed since the client looked it up\", \"headers\": {\"ETag\": {\"$ref\": \"#/components/headers/ETag\"}, \"Last-Modified\": {\"$ref\": \"#/components/headers/LastModified\"}}},\n" +
+	"          \"400\": {\"$ref\": \"#/components/responses/BadRequest\"},\n" +
+	"          \"403\": {\"$ref\": \"#/components/responses/Forbidden\"},\n" +
+	"          \"404\": {\"$ref\": \"#/components/responses/NotFound\"},\n" +
+	"          \"500\": {\"$ref\": \"#/components/responses/InternalServerError\"}\n" +
+	"        }\n" +
+	"      }\n" +
+	"    },\n" +
+	"    \"/urlshortener/v1/urls:batch\": {\n" +
+	"      \"post\": {\n" +
+	"        \"summary\": \"Shortens a batch of urls\",\n" +
+	"        \"description\": \"Results are streamed in the order of the input, each with the index of its url. Urls that could not be shortened have an error instead of a result.\",\n" +
+	"        \"operationId\": \"batchShortenUrls\",\n" +
+	"        \"requestBody\": {\n" +
+	"          \"required\": true,\n" +
+	"          \"content\": {\n" +
+	"            \"application/json\": {\"schema\": {\"type\": \"array\", \"items\": {\"$ref\": \"#/components/schemas/ShortenURLRequest\"}}},\n" +
+	"            \"application/x-ndjson\": {\"schema\": {\"$ref\": \"#/components/schemas/ShortenURLRequest\"}}\n" +
+	"          }\n" +
+	"        },\n" +
+	"        \"responses\": {\n" +
+	"          \"200\": {\n" +
+	"            \"description\": \"The result of each url\",\n" +
+	"            \"content\": {\n" +
+	"              \"application/json\": {\"schema\": {\"type\": \"array\", \"items\": {\"$ref\": \"#/components/schemas/BatchShortenURLResult\"}}},\n" +
+	"              \"application/x-ndjson\": {\"schema\": {\"$ref\": \"#/components/schemas/BatchShortenURLResult\"}}\n" +
+	"            }\n" +
+	"          },\n" +
+	"          \"400\": {\"$ref\": \"#/components/responses/BadRequest\"},\n" +
+	"          \"500\": {\"$ref\": \"#/components/responses/InternalServerError\"}\n" +
+	"        }\n" +
+	"      }\n" +
+	"    },\n" +
+	"    \"/urlshortener/v1/urls\": {\n" +
+	"      \"get\": {\n" +
+	"        \"summary\": \"Lists short urls\",\n" +
+	"        \"operationId\": \"listUrls\",\n" +
+	"        \"security\": [{\"adminToken\": []}],\n" +
+	"        \"parameters\": [\n" +
+	"          {\"$ref\": \"#/components/parameters/Domain\"},\n" +
+	"          {\"name\": \"owner\", \"in\": \"query\", \"schema\": {\"type\": \"string\"}},\n" +
+	"          {\"name\": \"host\", \"in\": \"query\", \"description\": \"The host of the long urls\", \"schema\": {\"type\": \"string\"}},\n" +
+	"          {\"name\": \"q\", \"in\": \"query\", \"description\": \"Text that the long url or shortId contains\", \"schema\": {\"type\": \"string\"}},\n" +
+	"          {\"name\": \"tag\", \"in\": \"query\", \"description\": \"A tag that every listed url has. Can be repeated.\", \"schema\": {\"type\": \"array\", \"items\": {\"type\": \"string\"}}, \"explode\": true},\n" +
+	"          {\"name\": \"createdAfter\", \"in\": \"query\", \"schema\": {\"type\": \"string\", \"format\": \"date-time\"}},\n" +
+	"          {\"name\": \"createdBefore\", \"in\": \"query\", \"schema\": {\"type\": \"string\", \"format\": \"date-time\"}},\n" +
+	"          {\"name\": \"sort\", \"in\": \"query\", \"schema\": {\"type\": \"string\", \"enum\": [\"createTime\", \"-createTime\", \"longUrl\", \"-longUrl\", \"shortId\", \"-shortId\"], \"default\": \"-createTime\"}},\n" +
+	"          {\"name\": \"limit\", \"in\": \"query\", \"schema\": {\"type\": \"integer\", \"minimum\": 1, \"maximum\": 500, \"default\": 50}},\n" +
+	"          {\"name\": \"cursor\", \"in\": \"query\", \"description\": \"The `nextCursor` of the previous page\", \"schema\": {\"type\": \"string\"}}\n" +
+	"        ],\n" +
+	"        \"responses\": {\n" +
+	"          \"200\": {\"description\": \"A page of short urls\", \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/ListURLsResponse\"}}}},\n" +
+	"          \"400\": {\"$ref\": \"#/components/responses/BadRequest\"},\n" +
+	"          \"401\": {\"$ref\": \"#/components/responses/Unauthorized\"},\n" +
+	"          \"500\": {\"$ref\": \"#/components/responses/InternalServerError\"}\n" +
+	"        }\n" +
+	"      }\n" +
+	"    },\n" +
+	"    \"/urlshortener/v1/url/{shortId}\": {\n" +
+	"      \"parameters\": [\n" +
+	"        {\"$ref\": \"#/components/parameters/ShortID\"}\n" +
+	"      ],\n" +
+	"      \"get\": {\n" +
+	"        \"summary\": \"Looks up the long url of a shortId\",\n" +
+	"        \"operationId\": \"retrieveShortId\",\n" +
+	"        \"parameters\": [\n" +
+	"          {\"$ref\": \"#/components/parameters/Domain\"},\n" +
+	"          {\"$ref\": \"#/components/parameters/IfNoneMatch\"},\n" +
+	"          {\"$ref\": \"#/components/parameters/IfModifiedSince\"}\n" +
+	"        ],\n" +
+	"        \"responses\": {\n" +
+	"          \"200\": {\"description\": \"The long url\", \"headers\": {\"ETag\": {\"$ref\": \"#/components/headers/ETag\"}, \"Last-Modified\": {\"$ref\": \"#/components/headers/LastModified\"}}, \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/RetrieveOriginalURLResponse\"}}}},\n" +
+	"          \"304\": {\"description\": \"The link has not changed since the client looked it up\", \"headers\": {\"ETag\": {\"$ref\": \"#/components/headers/ETag\"}, \"Last-Modified\": {\"$ref\": \"#/components/headers/LastModified\"}}},\n" +
+	"          \"400\": {\"$ref\": \"#/components/responses/BadRequest\"},\n" +
+	"          \"403\": {\"$ref\": \"#/components/responses/Forbidden\"},\n" +
+	"          \"404\": {\"$ref\": \"#/components/responses/NotFound\"},\n" +
+	"          \"500\": {\"$ref\": \"#/components/responses/InternalServerError\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"patch\": {\n" +
+	"        \"summary\": \"Updates a short url\",\n" +
+	"        \"description\": \"Only the fields that are present are updated.\",\n" +
+	"        \"operationId\": \"updateUrl\",\n" +
+	"        \"security\": [{\"adminToken\": []}],\n" +
+	"        \"parameters\": [\n" +
+	"          {\"$ref\": \"#/components/parameters/Domain\"}\n" +
+	"        ],\n" +
+	"        \"requestBody\": {\n" +
+	"          \"required\": true,\n" +
+	"          \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/UpdateURLRequest\"}}}\n" +
+	"        },\n" +
+	"        \"responses\": {\n" +
+	"          \"200\": {\"description\": \"The updated short url\", \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/UpdateURLResponse\"}}}},\n" +
+	"          \"400\": {\"$ref\": \"#/components/responses/BadRequest\"},\n" +
+	"          \"401\": {\"$ref\": \"#/components/responses/Unauthorized\"},\n" +
+	"          \"404\": {\"$ref\": \"#/components/responses/NotFound\"},\n" +
+	"          \"500\": {\"$ref\": \"#/components/responses/InternalServerError\"}\n" +
+	"        }\n" +
+	"      }\n" +
+	"    },\n" +
+	"    \"/urlshortener/v1/url/{shortId}/stats\": {\n" +
+	"      \"get\": {\n" +
+	"        \"summary\": \"Counts the clicks on a short url\",\n" +
+	"        \"operationId\": \"clickStats\",\n" +
+	"        \"security\": [{\"adminToken\": []}],\n" +
+	"        \"parameters\": [\n" +
+	"          {\"$ref\": \"#/components/parameters/ShortID\"},\n" +
+	"          {\"$ref\": \"#/components/parameters/Domain\"}\n" +
+	"        ],\n" +
+	"        \"responses\": {\n" +
+	"          \"200\": {\"description\": \"The clicks on the short url\", \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/ClickStatsResponse\"}}}},\n" +
+	"          \"401\": {\"$ref\": \"#/components/responses/Unauthorized\"},\n" +
+	"          \"404\": {\"$ref\": \"#/components/responses/NotFound\"},\n" +
+	"          \"500\": {\"$ref\": \"#/components/responses/InternalServerError\"}\n" +
+	"        }\n" +
+	"      }\n" +
+	"    },\n" +
+	"    \"/urlshortener/v1/url/{shortId}/qr\": {\n" +
+	"      \"get\": {\n" +
+	"        \"summary\": \"Renders the qr code of a short url\",\n" +
+	"        \"operationId\": \"qrCode\",\n" +
+	"        \"parameters\": [\n" +
+	"          {\"$ref\": \"#/components/parameters/ShortID\"},\n" +
+	"          {\"$ref\": \"#/components/parameters/Domain\"},\n" +
+	"          {\"name\": \"format\", \"in\": \"query\", \"schema\": {\"type\": \"string\", \"enum\": [\"png\", \"svg\"], \"default\": \"png\"}},\n" +
+	"          {\"name\": \"size\", \"in\": \"query\", \"description\": \"The width and height of the image in pixels\", \"schema\": {\"type\": \"integer\", \"minimum\": 64, \"maximum\": 2048, \"default\": 256}},\n" +
+	"          {\"name\": \"level\", \"in\": \"query\", \"description\": \"The error correction level\", \"schema\": {\"type\": \"string\", \"enum\": [\"L\", \"M\", \"Q\", \"H\"], \"default\": \"M\"}},\n" +
+	"          {\"name\": \"margin\", \"in\": \"query\", \"description\": \"The width of the quiet zone in modules\", \"schema\": {\"type\": \"integer\", \"minimum\": 0, \"maximum\": 16, \"default\": 4}},\n" +
+	"          {\"name\": \"fg\", \"in\": \"query\", \"description\": \"The hex color of the modules e.g. `1a1a1a`\", \"schema\": {\"type\": \"string\"}},\n" +
+	"          {\"name\": \"bg\", \"in\": \"query\", \"description\": \"The hex color of the background e.g. `ffffff`\", \"schema\": {\"type\": \"string\"}},\n" +
+	"          {\"name\": \"track\", \"in\": \"query\", \"description\": \"Adds `src=qr` to the encoded short url so that scans can be told apart from clicks\", \"schema\": {\"type\": \"boolean\", \"default\": false}}\n" +
+	"        ],\n" +
+	"        \"responses\": {\n" +
+	"          \"200\": {\n" +
+	"            \"description\": \"The qr code\",\n" +
+	"            \"content\": {\n" +
+	"              \"image/png\": {\"schema\": {\"type\": \"string\", \"format\": \"binary\"}},\n" +
+	"              \"image/svg+xml\": {\"schema\": {\"type\": \"string\"}}\n" +
+	"            }\n" +
+	"          },\n" +
+	"          \"304\": {\"description\": \"The qr code has not changed\"},\n" +
+	"          \"400\": {\"$ref\": \"#/components/responses/BadRequest\"},\n" +
+	"          \"403\": {\"$ref\": \"#/components/responses/Forbidden\"},\n" +
+	"          \"404\": {\"$ref\": \"#/components/responses/NotFound\"},\n" +
+	"          \"500\": {\"$ref\": \"#/components/responses/InternalServerError\"}\n" +
+	"        }\n" +
+	"      }\n" +
+	"    },\n" +
+	"    \"/{shortUrl}\": {\n" +
+	"      \"get\": {\n" +
+	"        \"summary\": \"Redirects the visitor to the long url\",\n" +
+	"        \"description\": \"The short url is looked up on the domain of the Host header. Append `+` or `?preview=1` to preview the long url instead of being redirected.\",\n" +
+	"        \"operationId\": \"redirect\",\n" +
+	"        \"parameters\": [\n" +
+	"          {\"name\": \"shortUrl\", \"in\": \"path\", \"required\": true, \"description\": \"The shortId\", \"schema\": {\"type\": \"string\"}}\n" +
+	"        ],\n" +
+	"        \"responses\": {\n" +
+	"          \"301\": {\"$ref\": \"#/components/responses/Redirect\"},\n" +
+	"          \"302\": {\"$ref\": \"#/components/responses/Redirect\"},\n" +
+	"          \"303\": {\"$ref\": \"#/components/responses/Redirect\"},\n" +
+	"          \"307\": {\"$ref\": \"#/components/responses/Redirect\"},\n" +
+	"          \"308\": {\"$ref\": \"#/components/responses/Redirect\"},\n" +
+	"          \"200\": {\"description\": \"The preview page, or the social preview for crawlers\", \"content\": {\"text/html\": {\"schema\": {\"type\": \"string\"}}}},\n" +
+	"          \"403\": {\"description\": \"The link has been disabled\", \"content\": {\"text/html\": {\"schema\": {\"type\": \"string\"}}}},\n" +
+	"          \"404\": {\"$ref\": \"#/components/responses/NotFound\"}\n" +
+	"        }\n" +
+	"      }\n" +
+	"    },\n" +
+	"    \"/{shortUrl}/{suffix}\": {\n" +
+	"      \"get\": {\n" +
+	"        \"summary\": \"Redirects the visitor to the long url of a wildcard link, with the suffix appended to its path\",\n" +
+	"        \"operationId\": \"redirectWildcard\",\n" +
+	"        \"parameters\": [\n" +
+	"          {\"name\": \"shortUrl\", \"in\": \"path\", \"required\": true, \"description\": \"The shortId\", \"schema\": {\"type\": \"string\"}},\n" +
+	"          {\"name\": \"suffix\", \"in\": \"path\", \"required\": true, \"description\": \"The path that is appended to the long url. It may contain `/`.\", \"schema\": {\"type\": \"string\"}}\n" +
+	"        ],\n" +
+	"        \"responses\": {\n" +
+	"          \"301\": {\"$ref\": \"#/components/responses/Redirect\"},\n" +
+	"          \"302\": {\"$ref\": \"#/components/responses/Redirect\"},\n" +
+	"          \"303\": {\"$ref\": \"#/components/responses/Redirect\"},\n" +
+	"          \"307\": {\"$ref\": \"#/components/responses/Redirect\"},\n" +
+	"          \"308\": {\"$ref\": \"#/components/responses/Redirect\"},\n" +
+	"          \"403\": {\"description\": \"The link has been disabled\", \"content\": {\"text/html\": {\"schema\": {\"type\": \"string\"}}}},\n" +
+	"          \"404\": {\"$ref\": \"#/components/responses/NotFound\"}\n" +
+	"        }\n" +
+	"      }\n" +
+	"    }\n" +
+	"  },\n" +
+	"  \"components\": {\n" +
+	"    \"securitySchemes\": {\n" +
+	"      \"adminToken\": {\"type\": \"http\", \"scheme\": \"bearer\", \"description\": \"The ADMIN_TOKEN\"}\n" +
+	"    },\n" +
+	"    \"parameters\": {\n" +
+	"      \"ShortID\": {\"name\": \"shortId\", \"in\": \"path\", \"required\": true, \"schema\": {\"type\": \"string\"}},\n" +
+	"      \"Domain\": {\"name\": \"domain\", \"in\": \"query\", \"description\": \"The host of the domain the short url is on e.g. `brand.ly`. The domain of BASE_URL is used if it is not given.\", \"schema\": {\"type\": \"string\"}},\n" +
+	"      \"IfNoneMatch\": {\"name\": \"If-None-Match\", \"in\": \"header\", \"description\": \"The ETag of the link from an earlier lookup. 304 Not Modified is returned if the link has not changed.\", \"schema\": {\"type\": \"string\"}},\n" +
+	"      \"IfModifiedSince\": {\"name\": \"If-Modified-Since\", \"in\": \"header\", \"description\": \"The Last-Modified date of the link from an earlier lookup. It is ignored if If-None-Match is sent.\", \"schema\": {\"type\": \"string\"}}\n" +
+	"    },\n" +
+	"    \"headers\": {\n" +
+	"      \"ETag\": {\"description\": \"Identifies the version of the link. Send it in If-None-Match when the link is looked up again.\", \"schema\": {\"type\": \"string\"}},\n" +
+	"      \"LastModified\": {\"description\": \"When the link was last changed. Send it in If-Modified-Since when the link is looked up again.\", \"schema\": {\"type\": \"string\"}}\n" +
+	"    },\n" +
+	"    \"responses\": {\n" +
+	"      \"Redirect\": {\n" +
+	"        \"description\": \"Redirects to the long url\",\n" +
+	"        \"headers\": {\"Location\": {\"schema\": {\"type\": \"string\", \"format\": \"uri\"}}}\n" +
+	"      },\n" +
+	"      \"BadRequest\": {\"description\": \"The request is not valid\", \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/Error\"}}, \"application/problem+json\": {\"schema\": {\"$ref\": \"#/components/schemas/Problem\"}}}},\n" +
+	"      \"Unauthorized\": {\"description\": \"A valid admin token is required\", \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/Error\"}}, \"application/problem+json\": {\"schema\": {\"$ref\": \"#/components/schemas/Problem\"}}}},\n" +
+	"      \"Forbidden\": {\"description\": \"The link has been disabled\", \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/Error\"}}, \"application/problem+json\": {\"schema\": {\"$ref\": \"#/components/schemas/Problem\"}}}},\n" +
+	"      \"NotFound\": {\"description\": \"The short url does not exist\", \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/Error\"}}, \"application/problem+json\": {\"schema\": {\"$ref\": \"#/components/schemas/Problem\"}}}},\n" +
+	"      \"InternalServerError\": {\"description\": \"The request failed\", \"content\": {\"application/json\": {\"schema\": {\"$ref\": \"#/components/schemas/Error\"}}, \"application/problem+json\": {\"schema\": {\"$ref\": \"#/components/schemas/Problem\"}}}}\n" +
+	"    },\n" +
+	"    \"schemas\": {\n" +
+	"      \"Error\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"required\": [\"code\", \"domain\", \"message\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"code\": {\n" +
+	"            \"type\": \"integer\",\n" +
+	"            \"description\": \"Identifies the error. The first two digits are the operation and the last three digits the kind of error (2xx decoding, 3xx validation, 4xx not found or not saved, 5xx failure, 999 undocumented).\",\n" +
+	"            \"enum\": [\n" +
+	"              10200, 10300, 10301, 10302, 10303, 10304, 10305, 10306, 10307, 10400, 10401, 10402, 10403, 10404, 10999,\n" +
+	"              11200, 11300, 11301, 11400, 11401, 11402, 11500, 11999,\n" +
+	"              12100, 12999,\n" +
+	"              13000,\n" +
+	"              14200, 14300, 14400, 14500,\n" +
+	"              15100,\n" +
+	"              16300, 16400, 16500,\n" +
+	"              17300, 17400, 17401, 17500,\n" +
+	"              18200, 18300,\n" +
+	"              19300, 19500\n" +
+	"            ]\n" +
+	"          },\n" +
+	"          \"domain\": {\"type\": \"string\", \"description\": \"The name of the error e.g. `shortenUrl.validation`\"},\n" +
+	"          \"message\": {\"type\": \"string\"},\n" +
+	"          \"fields\": {\"type\": \"object\", \"additionalProperties\": {\"type\": \"string\"}, \"nullable\": true}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"Problem\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"description\": \"An RFC 7807 problem, returned instead of the Error if the request accepts `application/problem+json`\",\n" +
+	"        \"required\": [\"type\", \"title\", \"status\", \"code\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"type\": {\"type\": \"string\", \"format\": \"uri\", \"description\": \"`urn:short-url:problem:` followed by the domain of the error e.g. `urn:short-url:problem:shortenUrl.validation`\"},\n" +
+	"          \"title\": {\"type\": \"string\"},\n" +
+	"          \"status\": {\"type\": \"integer\"},\n" +
+	"          \"detail\": {\"type\": \"string\"},\n" +
+	"          \"instance\": {\"type\": \"string\", \"description\": \"The path of the request\"},\n" +
+	"          \"code\": {\"$ref\": \"#/components/schemas/Error/properties/code\"},\n" +
+	"          \"fields\": {\"type\": \"object\", \"additionalProperties\": {\"type\": \"string\"}}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"UTMParameters\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"required\": [\"source\", \"medium\", \"campaign\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"source\": {\"type\": \"string\"},\n" +
+	"          \"medium\": {\"type\": \"string\"},\n" +
+	"          \"campaign\": {\"type\": \"string\"},\n" +
+	"          \"term\": {\"type\": \"string\"},\n" +
+	"          \"content\": {\"type\": \"string\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"TargetingRule\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"description\": \"Sends visitors that match all of the conditions to a different long url. Empty conditions match every visitor.\",\n" +
+	"        \"required\": [\"longUrl\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"platform\": {\"type\": \"string\", \"enum\": [\"ios\", \"android\", \"mobile\", \"desktop\"]},\n" +
+	"          \"language\": {\"type\": \"string\", \"description\": \"A language tag e.g. `en` or `pt-BR`\"},\n" +
+	"          \"country\": {\"type\": \"string\", \"description\": \"A two letter country code\"},\n" +
+	"          \"longUrl\": {\"type\": \"string\", \"format\": \"uri\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"Destination\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"required\": [\"variant\", \"longUrl\", \"weight\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"variant\": {\"type\": \"string\"},\n" +
+	"          \"longUrl\": {\"type\": \"string\", \"format\": \"uri\"},\n" +
+	"          \"weight\": {\"type\": \"integer\", \"minimum\": 1, \"maximum\": 10000}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"SocialMetadata\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"properties\": {\n" +
+	"          \"title\": {\"type\": \"string\"},\n" +
+	"          \"description\": {\"type\": \"string\"},\n" +
+	"          \"image\": {\"type\": \"string\", \"format\": \"uri\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"Tags\": {\"type\": \"array\", \"items\": {\"type\": \"string\"}},\n" +
+	"      \"Metadata\": {\"type\": \"object\", \"additionalProperties\": {\"type\": \"string\"}},\n" +
+	"      \"ShortenURLRequest\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"required\": [\"longUrl\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"longUrl\": {\"type\": \"string\", \"format\": \"uri\"},\n" +
+	"          \"ShortId\": {\"type\": \"string\", \"description\": \"The shortId to use instead of a generated one\"},\n" +
+	"          \"domain\": {\"type\": \"string\", \"description\": \"The host of the branded domain to shorten the url on. The domain of BASE_URL is used if it is not given.\"},\n" +
+	"          \"owner\": {\"type\": \"string\"},\n" +
+	"          \"dedupe\": {\"type\": \"string\", \"enum\": [\"global\", \"per-owner\", \"never\"]},\n" +
+	"          \"redirectStatus\": {\"type\": \"integer\", \"enum\": [301, 302, 303, 307, 308]},\n" +
+	"          \"queryMerge\": {\"type\": \"string\", \"enum\": [\"keep\", \"replace\", \"append\"]},\n" +
+	"          \"wildcard\": {\"type\": \"boolean\"},\n" +
+	"          \"utm\": {\"$ref\": \"#/components/schemas/UTMParameters\"},\n" +
+	"          \"targeting\": {\"type\": \"array\", \"items\": {\"$ref\": \"#/components/schemas/TargetingRule\"}},\n" +
+	"          \"destinations\": {\"type\": \"array\", \"items\": {\"$ref\": \"#/components/schemas/Destination\"}},\n" +
+	"          \"social\": {\"$ref\": \"#/components/schemas/SocialMetadata\"},\n" +
+	"          \"tags\": {\"$ref\": \"#/components/schemas/Tags\"},\n" +
+	"          \"metadata\": {\"$ref\": \"#/components/schemas/Metadata\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"ShortenURLResponse\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"required\": [\"longUrl\", \"shortUrl\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"longUrl\": {\"type\": \"string\", \"format\": \"uri\"},\n" +
+	"          \"shortUrl\": {\"type\": \"string\", \"format\": \"uri\"},\n" +
+	"          \"redirectStatus\": {\"type\": \"integer\"},\n" +
+	"          \"utm\": {\"$ref\": \"#/components/schemas/UTMParameters\"},\n" +
+	"          \"targeting\": {\"type\": \"array\", \"items\": {\"$ref\": \"#/components/schemas/TargetingRule\"}},\n" +
+	"          \"destinations\": {\"type\": \"array\", \"items\": {\"$ref\": \"#/components/schemas/Destination\"}},\n" +
+	"          \"social\": {\"$ref\": \"#/components/schemas/SocialMetadata\"},\n" +
+	"          \"tags\": {\"$ref\": \"#/components/schemas/Tags\"},\n" +
+	"          \"metadata\": {\"$ref\": \"#/components/schemas/Metadata\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"BatchShortenURLResult\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"required\": [\"index\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"index\": {\"type\": \"integer\", \"description\": \"The position of the url in the batch\"},\n" +
+	"          \"result\": {\"$ref\": \"#/components/schemas/ShortenURLResponse\"},\n" +
+	"          \"error\": {\"$ref\": \"#/components/schemas/Error\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"RetrieveOriginalURLResponse\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"required\": [\"longUrl\", \"shortUrl\", \"redirectStatus\", \"createTime\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"longUrl\": {\"type\": \"string\", \"format\": \"uri\"},\n" +
+	"          \"shortUrl\": {\"type\": \"string\", \"format\": \"uri\"},\n" +
+	"          \"redirectStatus\": {\"type\": \"integer\"},\n" +
+	"          \"createTime\": {\"type\": \"string\", \"format\": \"date-time\"},\n" +
+	"          \"variant\": {\"type\": \"string\"},\n" +
+	"          \"social\": {\"$ref\": \"#/components/schemas/SocialMetadata\"},\n" +
+	"          \"tags\": {\"$ref\": \"#/components/schemas/Tags\"},\n" +
+	"          \"metadata\": {\"$ref\": \"#/components/schemas/Metadata\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"URLSummary\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"required\": [\"shortId\", \"domain\", \"shortUrl\", \"longUrl\", \"createTime\", \"disabled\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"shortId\": {\"type\": \"string\"},\n" +
+	"          \"domain\": {\"type\": \"string\"},\n" +
+	"          \"shortUrl\": {\"type\": \"string\", \"format\": \"uri\"},\n" +
+	"          \"longUrl\": {\"type\": \"string\", \"format\": \"uri\"},\n" +
+	"          \"owner\": {\"type\": \"string\"},\n" +
+	"          \"createTime\": {\"type\": \"string\", \"format\": \"date-time\"},\n" +
+	"          \"disabled\": {\"type\": \"boolean\"},\n" +
+	"          \"disabledReason\": {\"type\": \"string\"},\n" +
+	"          \"tags\": {\"$ref\": \"#/components/schemas/Tags\"},\n" +
+	"          \"metadata\": {\"$ref\": \"#/components/schemas/Metadata\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"ListURLsResponse\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"required\": [\"urls\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"urls\": {\"type\": \"array\", \"items\": {\"$ref\": \"#/components/schemas/URLSummary\"}},\n" +
+	"          \"nextCursor\": {\"type\": \"string\", \"description\": \"Passed as `cursor` to load the next page. It is omitted on the last page.\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"UpdateURLRequest\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"properties\": {\n" +
+	"          \"disabled\": {\"type\": \"boolean\"},\n" +
+	"          \"disabledReason\": {\"type\": \"string\"},\n" +
+	"          \"redirectStatus\": {\"type\": \"integer\", \"enum\": [301, 302, 303, 307, 308]},\n" +
+	"          \"queryMerge\": {\"type\": \"string\", \"enum\": [\"\", \"keep\", \"replace\", \"append\"]},\n" +
+	"          \"wildcard\": {\"type\": \"boolean\"},\n" +
+	"          \"targeting\": {\"type\": \"array\", \"items\": {\"$ref\": \"#/components/schemas/TargetingRule\"}},\n" +
+	"          \"destinations\": {\"type\": \"array\", \"items\": {\"$ref\": \"#/components/schemas/Destination\"}},\n" +
+	"          \"tags\": {\"$ref\": \"#/components/schemas/Tags\"},\n" +
+	"          \"metadata\": {\"type\": \"object\", \"description\": \"Keys set to null are removed\", \"additionalProperties\": {\"type\": \"string\", \"nullable\": true}}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"UpdateURLResponse\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"required\": [\"shortId\", \"longUrl\", \"disabled\", \"wildcard\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"shortId\": {\"type\": \"string\"},\n" +
+	"          \"longUrl\": {\"type\": \"string\", \"format\": \"uri\"},\n" +
+	"          \"disabled\": {\"type\": \"boolean\"},\n" +
+	"          \"disabledReason\": {\"type\": \"string\"},\n" +
+	"          \"redirectStatus\": {\"type\": \"integer\"},\n" +
+	"          \"queryMerge\": {\"type\": \"string\"},\n" +
+	"          \"wildcard\": {\"type\": \"boolean\"},\n" +
+	"          \"targeting\": {\"type\": \"array\", \"items\": {\"$ref\": \"#/components/schemas/TargetingRule\"}},\n" +
+	"          \"destinations\": {\"type\": \"array\", \"items\": {\"$ref\": \"#/components/schemas/Destination\"}},\n" +
+	"          \"tags\": {\"$ref\": \"#/components/schemas/Tags\"},\n" +
+	"          \"metadata\": {\"$ref\": \"#/components/schemas/Metadata\"}\n" +
+	"        }\n" +
+	"      },\n" +
+	"      \"ClickStatsResponse\": {\n" +
+	"        \"type\": \"object\",\n" +
+	"        \"required\": [\"shortId\", \"clicks\"],\n" +
+	"        \"properties\": {\n" +
+	"          \"shortId\": {\"type\": \"string\"},\n" +
+	"          \"clicks\": {\"type\": \"integer\"},\n" +
+	"          \"variants\": {\n" +
+	"            \"type\": \"array\",\n" +
+	"            \"items\": {\n" +
+	"              \"type\": \"object\",\n" +
+	"              \"required\": [\"variant\", \"longUrl\", \"weight\", \"clicks\"],\n" +
+	"              \"properties\": {\n" +
+	"                \"variant\": {\"type\": \"string\"},\n" +
+	"                \"longUrl\": {\"type\": \"string\", \"format\": \"uri\"},\n" +
+	"                \"weight\": {\"type\": \"integer\"},\n" +
+	"                \"clicks\": {\"type\": \"integer\"}\n" +
+	"              }\n" +
+	"            }\n" +
+	"          }\n" +
+	"        }\n" +
+	"      }\n" +
+	"    }\n" +
+	"  }\n" +
+	"}\n"
@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/w-k-s/short-url/adapters/web"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// routePattern matches the regular expression of a path variable e.g. `{suffix:.*}`
+var routePattern = regexp.MustCompile(`\{([^}:]+):[^}]*\}`)
+
+// declaredRoutes finds the paths and methods of the routes registered by the Route methods in this package,
+// e.g. `r.HandleFunc("/urlshortener/v1/url", h).Methods("POST")`
+func declaredRoutes(t *testing.T) map[string][]string {
+	fileSet := token.NewFileSet()
+	packages, err := parser.ParseDir(fileSet, ".", func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse controllers: %s", err)
+	}
+
+	routes := map[string][]string{}
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				function, ok := decl.(*ast.FuncDecl)
+				if !ok || function.Recv == nil || function.Name.Name != "Route" {
+					continue
+				}
+				ast.Inspect(function.Body, func(node ast.Node) bool {
+					methods, ok := node.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					selector, ok := methods.Fun.(*ast.SelectorExpr)
+					if !ok || selector.Sel.Name != "Methods" {
+						return true
+					}
+					handleFunc, ok := selector.X.(*ast.CallExpr)
+					if !ok || len(handleFunc.Args) == 0 {
+						return true
+					}
+					path := stringLiteral(t, handleFunc.Args[0])
+					path = routePattern.ReplaceAllString(path, "{$1}")
+					for _, method := range methods.Args {
+						routes[path] = append(routes[path], strings.ToLower(stringLiteral(t, method)))
+					}
+					return false
+				})
+			}
+		}
+	}
+	return routes
+}
+
+func stringLiteral(t *testing.T, expr ast.Expr) string {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		t.Fatalf("Expected routes to be registered with string literals")
+	}
+	value, _ := strconv.Unquote(literal.Value)
+	return value
+}
+
+func TestEveryRouteHasOpenAPIEntry(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(openAPISpec), &spec); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %s", err)
+	}
+
+	routes := declaredRoutes(t)
+	assert.NotEmpty(t, routes)
+
+	for path, methods := range routes {
+		for _, method := range methods {
+			_, ok := spec.Paths[path][method]
+			assert.True(t, ok, "openapi.json does not describe %s %s", strings.ToUpper(method), path)
+		}
+	}
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+			found := false
+			for _, routeMethod := range routes[path] {
+				found = found || routeMethod == method
+			}
+			assert.True(t, found, "openapi.json describes %s %s, which is not a route", strings.ToUpper(method), path)
+		}
+	}
+}
+
+// errorsFile declares the error codes of the use cases
+const errorsFile = "../../../domain/urlshortener/usecase/errors.go"
+
+// declaredErrorCodes finds the values of the error code constants e.g. `ShortenURLValidation = 10300`
+func declaredErrorCodes(t *testing.T) []int {
+	file, err := parser.ParseFile(token.NewFileSet(), errorsFile, nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse error codes: %s", err)
+	}
+
+	var codes []int
+	for _, decl := range file.Decls {
+		constants, ok := decl.(*ast.GenDecl)
+		if !ok || constants.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range constants.Specs {
+			for _, value := range spec.(*ast.ValueSpec).Values {
+				literal, ok := value.(*ast.BasicLit)
+				if !ok || literal.Kind != token.INT {
+					t.Fatalf("Expected error codes to be declared with integer literals")
+				}
+				code, _ := strconv.Atoi(literal.Value)
+				codes = append(codes, code)
+			}
+		}
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+func TestEveryErrorCodeHasOpenAPIEntry(t *testing.T) {
+	var spec struct {
+		Components struct {
+			Schemas struct {
+				Error struct {
+					Properties struct {
+						Code struct {
+							Enum []int `json:"enum"`
+						} `json:"code"`
+					} `json:"properties"`
+				} `json:"Error"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal([]byte(openAPISpec), &spec); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %s", err)
+	}
+
+	documented := spec.Components.Schemas.Error.Properties.Code.Enum
+	sort.Ints(documented)
+
+	codes := declaredErrorCodes(t)
+	assert.NotEmpty(t, codes)
+	assert.Equal(t, codes, documented, "The error codes in openapi.json are not the codes in errors.go")
+}
+
+func TestOpenAPISpecIsGenerated(t *testing.T) {
+	spec, err := ioutil.ReadFile("openapi.json")
+	if err != nil {
+		t.Fatalf("Failed to read openapi.json: %s", err)
+	}
+	assert.True(t, string(spec) == openAPISpec, "openapiSpec.go is out of date. Run `go generate` in adapters/web/controllers")
+}
+
+func TestOpenAPIServed(t *testing.T) {
+	w := httptest.NewRecorder()
+	GetOpenAPIHandler(web.NewJsonFmt())(w, httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/openapi.json", nil))
+
+	var spec map[string]interface{}
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, "3.0.3", spec["openapi"])
+}
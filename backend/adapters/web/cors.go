@@ -0,0 +1,176 @@
+package web
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy decides which cross-origin requests browsers let scripts make to the API (https://fetch.spec.whatwg.org/#http-cors-protocol)
+type CORSPolicy struct {
+	// AllowedOrigins are origins e.g. `https://www.small.ml`, or patterns in which `*` matches any part of a host e.g. `https://*.small.ml`.
+	// `*` allows every origin.
+	AllowedOrigins []string
+	// AllowedMethods are the methods that preflight requests are allowed for
+	AllowedMethods []string
+	// AllowedHeaders are the request headers that preflight requests are allowed for. `*` allows every header.
+	AllowedHeaders []string
+	// ExposedHeaders are the response headers that scripts may read besides the CORS-safelisted headers e.g. ETag
+	ExposedHeaders []string
+	// AllowCredentials lets scripts send cookies and authorization headers, and read the responses
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache the response to a preflight request. Browsers use their own default if it is 0.
+	MaxAge time.Duration
+}
+
+// originPatternHost matches the characters that `*` stands for in an origin pattern; it can not match the `/` or `:` of a different origin
+const originPatternHost = `[a-z0-9.-]*`
+
+// CORSHandler adds the CORS headers to every response of the router, including errors and redirects,
+// and answers the preflight requests of the routes.
+type CORSHandler struct {
+	policy CORSPolicy
+	router *mux.Router
+	// withoutPreflight are the routes that preflight requests are not answered for e.g. redirects
+	withoutPreflight map[*mux.Route]bool
+	allowAll         bool
+	origins          map[string]bool
+	originPatterns   []*regexp.Regexp
+}
+
+func NewCORSHandler(policy CORSPolicy, router *mux.Router, withoutPreflight map[*mux.Route]bool) *CORSHandler {
+	handler := &CORSHandler{
+		policy:           policy,
+		router:           router,
+		withoutPreflight: withoutPreflight,
+		origins:          map[string]bool{},
+	}
+	for _, origin := range policy.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+		switch {
+		case origin == "*":
+			handler.allowAll = true
+		case strings.Contains(origin, "*"):
+			parts := strings.Split(origin, "*")
+			for index, part := range parts {
+				parts[index] = regexp.QuoteMeta(part)
+			}
+			handler.originPatterns = append(handler.originPatterns, regexp.MustCompile("^"+strings.Join(parts, originPatternHost)+"$"))
+		default:
+			handler.origins[origin] = true
+		}
+	}
+	return handler
+}
+
+func (h *CORSHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	origin := req.Header.Get("Origin")
+	if len(origin) == 0 {
+		h.router.ServeHTTP(w, req)
+		return
+	}
+
+	if req.Method == http.MethodOptions && len(req.Header.Get("Access-Control-Request-Method")) > 0 {
+		h.preflight(w, req, origin)
+		return
+	}
+
+	if h.setAllowOrigin(w, origin) && len(h.policy.ExposedHeaders) > 0 {
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(h.policy.ExposedHeaders, ", "))
+	}
+	h.router.ServeHTTP(w, req)
+}
+
+// preflight tells the browser whether the request that it is about to make is allowed.
+// Preflight requests for paths and methods that have no route, or whose route is without preflight, are handled by the router (i.e. 404 or 405).
+func (h *CORSHandler) preflight(w http.ResponseWriter, req *http.Request, origin string) {
+	method := strings.ToUpper(req.Header.Get("Access-Control-Request-Method"))
+	if !h.hasRoute(req, method) {
+		h.router.ServeHTTP(w, req)
+		return
+	}
+
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+	requestedHeaders := req.Header.Get("Access-Control-Request-Headers")
+	if !h.setAllowOrigin(w, origin) || !contains(h.policy.AllowedMethods, method) || !h.allowsHeaders(requestedHeaders) {
+		http.Error(w, "The cross-origin request is not allowed", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(h.policy.AllowedMethods, ", "))
+	if len(requestedHeaders) > 0 {
+		w.Header().Set("Access-Control-Allow-Headers", requestedHeaders)
+	}
+	if h.policy.MaxAge > 0 {
+		w.Header().Set("Access-Control-Max-Age", strconv.FormatInt(int64(h.policy.MaxAge/time.Second), 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setAllowOrigin sets the headers that allow the origin to read the response, and returns false if the origin is not allowed
+func (h *CORSHandler) setAllowOrigin(w http.ResponseWriter, origin string) bool {
+	// Every origin gets the same response, unless credentials are allowed; `*` can not be used with credentials
+	if h.allowAll && !h.policy.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return true
+	}
+
+	w.Header().Add("Vary", "Origin")
+	if !h.allowsOrigin(origin) {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if h.policy.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	return true
+}
+
+func (h *CORSHandler) allowsOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	if h.allowAll || h.origins[origin] {
+		return true
+	}
+	for _, pattern := range h.originPatterns {
+		if pattern.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsHeaders returns true if every header in the comma-separated list is allowed
+func (h *CORSHandler) allowsHeaders(requestedHeaders string) bool {
+	if contains(h.policy.AllowedHeaders, "*") {
+		return true
+	}
+	for _, header := range strings.Split(requestedHeaders, ",") {
+		header = strings.TrimSpace(header)
+		if len(header) > 0 && !contains(h.policy.AllowedHeaders, header) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasRoute returns true if the router has a route for the path of the request and the method, and the route is not without preflight
+func (h *CORSHandler) hasRoute(req *http.Request, method string) bool {
+	routed := *req
+	routed.Method = method
+	var match mux.RouteMatch
+	return h.router.Match(&routed, &match) && match.MatchErr == nil && !h.withoutPreflight[match.Route]
+}
+
+// contains compares the values case-insensitively, since methods and header names are case-insensitive
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
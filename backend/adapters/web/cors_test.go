@@ -0,0 +1,147 @@
+package web
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func corsTestHandler(policy CORSPolicy) http.Handler {
+	router := mux.NewRouter()
+	router.HandleFunc("/urlshortener/v1/url", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET", "POST")
+	redirect := router.HandleFunc("/{shortId}", func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, "https://www.example.com", http.StatusSeeOther)
+	}).Methods("GET")
+	return NewCORSHandler(policy, router, map[*mux.Route]bool{redirect: true})
+}
+
+func corsTestPolicy() CORSPolicy {
+	return CORSPolicy{
+		AllowedOrigins: []string{"https://www.small.ml", "https://*.small.ml"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "Idempotency-Key"},
+		ExposedHeaders: []string{"ETag", "Idempotent-Replayed"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+func TestCORSActualRequests(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		policy      CORSPolicy
+		path        string
+		origin      string
+		status      int
+		allowOrigin string
+	}{
+		{"no origin", corsTestPolicy(), "/urlshortener/v1/url", "", http.StatusOK, ""},
+		{"allowed origin", corsTestPolicy(), "/urlshortener/v1/url", "https://www.small.ml", http.StatusOK, "https://www.small.ml"},
+		{"origin matching pattern", corsTestPolicy(), "/urlshortener/v1/url", "https://admin.small.ml", http.StatusOK, "https://admin.small.ml"},
+		{"origin matching pattern with other scheme", corsTestPolicy(), "/urlshortener/v1/url", "http://admin.small.ml", http.StatusOK, ""},
+		{"origin ending with pattern", corsTestPolicy(), "/urlshortener/v1/url", "https://evil.com.small.ml.evil.com", http.StatusOK, ""},
+		{"other origin", corsTestPolicy(), "/urlshortener/v1/url", "https://www.example.com", http.StatusOK, ""},
+		{"redirect", corsTestPolicy(), "/shrt", "https://www.small.ml", http.StatusSeeOther, "https://www.small.ml"},
+		{"not found", corsTestPolicy(), "/shrt/none", "https://www.small.ml", http.StatusNotFound, "https://www.small.ml"},
+		{"any origin", CORSPolicy{AllowedOrigins: []string{"*"}}, "/urlshortener/v1/url", "https://www.example.com", http.StatusOK, "*"},
+		{"any origin with credentials", CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true}, "/urlshortener/v1/url", "https://www.example.com", http.StatusOK, "https://www.example.com"},
+	}
+
+	for _, testCase := range testCases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "http://small.ml"+testCase.path, nil)
+		if len(testCase.origin) > 0 {
+			req.Header.Set("Origin", testCase.origin)
+		}
+
+		corsTestHandler(testCase.policy).ServeHTTP(w, req)
+
+		if w.Code != testCase.status {
+			t.Errorf("%s: status is %d, want: %d", testCase.name, w.Code, testCase.status)
+		}
+		if allowOrigin := w.Header().Get("Access-Control-Allow-Origin"); allowOrigin != testCase.allowOrigin {
+			t.Errorf("%s: Access-Control-Allow-Origin is %q, want: %q", testCase.name, allowOrigin, testCase.allowOrigin)
+		}
+		if exposed := w.Header().Get("Access-Control-Expose-Headers"); testCase.policy.ExposedHeaders != nil && len(testCase.allowOrigin) > 0 && exposed != "ETag, Idempotent-Replayed" {
+			t.Errorf("%s: Access-Control-Expose-Headers is %q", testCase.name, exposed)
+		}
+		if len(testCase.origin) > 0 && testCase.allowOrigin != "*" && w.Header().Get("Vary") != "Origin" {
+			t.Errorf("%s: Vary is %q, want: Origin", testCase.name, w.Header().Get("Vary"))
+		}
+	}
+}
+
+func TestCORSPreflightRequests(t *testing.T) {
+
+	testCases := []struct {
+		name         string
+		path         string
+		origin       string
+		method       string
+		headers      string
+		status       int
+		allowHeaders string
+	}{
+		{"allowed", "/urlshortener/v1/url", "https://www.small.ml", "POST", "content-type, idempotency-key", http.StatusNoContent, "content-type, idempotency-key"},
+		{"allowed without headers", "/urlshortener/v1/url", "https://admin.small.ml", "GET", "", http.StatusNoContent, ""},
+		{"other origin", "/urlshortener/v1/url", "https://www.example.com", "POST", "", http.StatusForbidden, ""},
+		{"method not allowed", "/urlshortener/v1/url", "https://www.small.ml", "PATCH", "", http.StatusMethodNotAllowed, ""},
+		{"header not allowed", "/urlshortener/v1/url", "https://www.small.ml", "POST", "X-Requested-With", http.StatusForbidden, ""},
+		{"not found", "/shrt/none", "https://www.small.ml", "GET", "", http.StatusNotFound, ""},
+		{"redirect", "/shrt", "https://www.small.ml", "GET", "", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, testCase := range testCases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("OPTIONS", "http://small.ml"+testCase.path, nil)
+		req.Header.Set("Origin", testCase.origin)
+		req.Header.Set("Access-Control-Request-Method", testCase.method)
+		if len(testCase.headers) > 0 {
+			req.Header.Set("Access-Control-Request-Headers", testCase.headers)
+		}
+
+		corsTestHandler(corsTestPolicy()).ServeHTTP(w, req)
+
+		if w.Code != testCase.status {
+			t.Errorf("%s: status is %d, want: %d", testCase.name, w.Code, testCase.status)
+		}
+		if w.Code != http.StatusNoContent {
+			continue
+		}
+		if allowOrigin := w.Header().Get("Access-Control-Allow-Origin"); allowOrigin != testCase.origin {
+			t.Errorf("%s: Access-Control-Allow-Origin is %q, want: %q", testCase.name, allowOrigin, testCase.origin)
+		}
+		if allowMethods := w.Header().Get("Access-Control-Allow-Methods"); allowMethods != "GET, POST" {
+			t.Errorf("%s: Access-Control-Allow-Methods is %q", testCase.name, allowMethods)
+		}
+		if allowHeaders := w.Header().Get("Access-Control-Allow-Headers"); allowHeaders != testCase.allowHeaders {
+			t.Errorf("%s: Access-Control-Allow-Headers is %q, want: %q", testCase.name, allowHeaders, testCase.allowHeaders)
+		}
+		if maxAge := w.Header().Get("Access-Control-Max-Age"); maxAge != "600" {
+			t.Errorf("%s: Access-Control-Max-Age is %q, want: 600", testCase.name, maxAge)
+		}
+	}
+}
+
+func TestCORSPreflightRequestWithCredentials(t *testing.T) {
+	policy := corsTestPolicy()
+	policy.AllowCredentials = true
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("OPTIONS", "http://small.ml/urlshortener/v1/url", nil)
+	req.Header.Set("Origin", "https://www.small.ml")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+
+	corsTestHandler(policy).ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status is %d, want: %d", w.Code, http.StatusNoContent)
+	}
+	if credentials := w.Header().Get("Access-Control-Allow-Credentials"); credentials != "true" {
+		t.Errorf("Access-Control-Allow-Credentials is %q, want: true", credentials)
+	}
+}
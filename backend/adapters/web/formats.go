@@ -0,0 +1,93 @@
+package web
+
+import (
+	"github.com/w-k-s/short-url/codec"
+	"github.com/w-k-s/short-url/domain"
+	"net/http"
+)
+
+// XML
+
+const (
+	// xmlResponseRoot is the root element of a response in XML
+	xmlResponseRoot = "response"
+	// xmlErrorRoot is the root element of an error in XML
+	xmlErrorRoot = "error"
+)
+
+// XmlFmt writes responses as XML, with the element names of the `xml` tags of the response types.
+// Lists are written as `<item>` elements.
+type XmlFmt struct{}
+
+// xmlErrorBody is the ErrorBody in XML, which can not encode maps
+type xmlErrorBody struct {
+	Code    domain.Code     `xml:"code"`
+	Domain  string          `xml:"domain"`
+	Message string          `xml:"message"`
+	Fields  codec.StringMap `xml:"fields,omitempty"`
+}
+
+func NewXmlFmt() XmlFmt {
+	return XmlFmt{}
+}
+
+func (xmlFmt XmlFmt) Print(w http.ResponseWriter, req *http.Request, status int, body interface{}) {
+	data, err := codec.MarshalXML(xmlResponseRoot, body)
+	writeEncoded(w, status, codec.XMLContentType+";charset=utf-8", body, data, err)
+}
+
+func (xmlFmt XmlFmt) Error(w http.ResponseWriter, req *http.Request, e domain.Err) {
+	data, err := codec.MarshalXML(xmlErrorRoot, xmlErrorBody{e.Code(), e.Domain(), e.Error(), e.Fields()})
+	writeEncoded(w, HTTPStatusCode(e.Code()), codec.XMLContentType+";charset=utf-8", e, data, err)
+}
+
+// YAML
+
+// YamlFmt writes responses as YAML, with the JSON field names as keys
+type YamlFmt struct{}
+
+func NewYamlFmt() YamlFmt {
+	return YamlFmt{}
+}
+
+func (yamlFmt YamlFmt) Print(w http.ResponseWriter, req *http.Request, status int, body interface{}) {
+	data, err := codec.MarshalYAML(body)
+	writeEncoded(w, status, codec.YAMLContentType+";charset=utf-8", body, data, err)
+}
+
+func (yamlFmt YamlFmt) Error(w http.ResponseWriter, req *http.Request, e domain.Err) {
+	data, err := codec.MarshalYAML(ErrorBody(e))
+	writeEncoded(w, HTTPStatusCode(e.Code()), codec.YAMLContentType+";charset=utf-8", e, data, err)
+}
+
+// MessagePack
+
+// MsgPackFmt writes responses as MessagePack, with the JSON field names as keys
+type MsgPackFmt struct{}
+
+func NewMsgPackFmt() MsgPackFmt {
+	return MsgPackFmt{}
+}
+
+func (msgPackFmt MsgPackFmt) Print(w http.ResponseWriter, req *http.Request, status int, body interface{}) {
+	data, err := codec.MarshalMsgPack(body)
+	writeEncoded(w, status, codec.MsgPackContentType, body, data, err)
+}
+
+func (msgPackFmt MsgPackFmt) Error(w http.ResponseWriter, req *http.Request, e domain.Err) {
+	data, err := codec.MarshalMsgPack(ErrorBody(e))
+	writeEncoded(w, HTTPStatusCode(e.Code()), codec.MsgPackContentType, e, data, err)
+}
+
+// writeEncoded writes a body that has been encoded, or an encoding error if it could not be encoded.
+// The body is encoded before the headers are written so that the status of an encoding error can still be sent.
+func writeEncoded(w http.ResponseWriter, status int, contentType string, encodee interface{}, data []byte, err error) {
+	if err != nil {
+		sendEncodingError(w, encodee, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	w.Write(data)
+}
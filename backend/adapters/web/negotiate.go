@@ -0,0 +1,134 @@
+package web
+
+import (
+	"github.com/w-k-s/short-url/codec"
+	"github.com/w-k-s/short-url/domain"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// mediaRange is a media range of an Accept header e.g. `application/xml;q=0.9`
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// matches returns true if the media range includes the media type e.g. `application/*` includes `application/xml`
+func (r mediaRange) matches(mediaType string) bool {
+	if r.mediaType == "*/*" || r.mediaType == mediaType {
+		return true
+	}
+	return strings.HasSuffix(r.mediaType, "/*") &&
+		strings.HasPrefix(mediaType, strings.TrimSuffix(r.mediaType, "*"))
+}
+
+// specificity orders the media ranges that match a media type; the most specific range determines its quality
+func (r mediaRange) specificity() int {
+	switch {
+	case r.mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(r.mediaType, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// acceptedMediaRanges parses the Accept headers of the request. Malformed media ranges are ignored.
+func acceptedMediaRanges(req *http.Request) []mediaRange {
+	if req == nil {
+		return nil
+	}
+
+	var ranges []mediaRange
+	for _, accept := range req.Header["Accept"] {
+		for _, value := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+			if err != nil {
+				continue
+			}
+			quality := 1.0
+			if q, err := strconv.ParseFloat(params["q"], 64); err == nil {
+				quality = q
+			}
+			ranges = append(ranges, mediaRange{mediaType, quality})
+		}
+	}
+	return ranges
+}
+
+// NegotiatedFormat is a format that NegotiatingFmt can write, and the media types that select it
+type NegotiatedFormat struct {
+	MediaTypes []string
+	Fmt        ResponseFmt
+}
+
+// NegotiatingFmt writes responses in the format that the Accept header of the request prefers.
+// JSON is written if the request has no Accept header or accepts none of the formats.
+type NegotiatingFmt struct {
+	jsonFmt JsonFmt
+	formats []NegotiatedFormat
+}
+
+// NewNegotiatingFmt negotiates between JSON, XML, YAML and MessagePack
+func NewNegotiatingFmt() NegotiatingFmt {
+	jsonFmt := NewJsonFmt()
+	return NegotiatingFmt{
+		jsonFmt: jsonFmt,
+		formats: []NegotiatedFormat{
+			{[]string{"application/json"}, jsonFmt},
+			{[]string{codec.XMLContentType, "text/xml"}, NewXmlFmt()},
+			{[]string{codec.YAMLContentType, "application/x-yaml", "text/yaml"}, NewYamlFmt()},
+			{[]string{codec.MsgPackContentType, "application/x-msgpack", "application/vnd.msgpack"}, NewMsgPackFmt()},
+		},
+	}
+}
+
+// Negotiate returns the format with the highest quality in the Accept header of the request.
+// Formats of the same quality are preferred in the order JSON, XML, YAML, MessagePack.
+func (negotiatingFmt NegotiatingFmt) Negotiate(req *http.Request) ResponseFmt {
+	ranges := acceptedMediaRanges(req)
+
+	var preferred ResponseFmt = negotiatingFmt.jsonFmt
+	preferredQuality := 0.0
+	for _, format := range negotiatingFmt.formats {
+		if quality := formatQuality(ranges, format.MediaTypes); quality > preferredQuality {
+			preferred = format.Fmt
+			preferredQuality = quality
+		}
+	}
+	return preferred
+}
+
+// formatQuality returns the quality of the media type that the Accept header prefers among those of a format
+func formatQuality(ranges []mediaRange, mediaTypes []string) float64 {
+	best := 0.0
+	for _, mediaType := range mediaTypes {
+		quality, specificity := 0.0, -1
+		for _, r := range ranges {
+			if r.matches(mediaType) && r.specificity() > specificity {
+				quality, specificity = r.quality, r.specificity()
+			}
+		}
+		if quality > best {
+			best = quality
+		}
+	}
+	return best
+}
+
+func (negotiatingFmt NegotiatingFmt) Print(w http.ResponseWriter, req *http.Request, status int, body interface{}) {
+	negotiatingFmt.Negotiate(req).Print(w, req, status, body)
+}
+
+// Error writes an RFC 7807 problem if the request accepts `application/problem+json`,
+// otherwise the error is written in the format that the request prefers
+func (negotiatingFmt NegotiatingFmt) Error(w http.ResponseWriter, req *http.Request, e domain.Err) {
+	if AcceptsProblemJSON(req) {
+		negotiatingFmt.jsonFmt.Error(w, req, e)
+		return
+	}
+	negotiatingFmt.Negotiate(req).Error(w, req, e)
+}
@@ -0,0 +1,83 @@
+package web
+
+import (
+	"github.com/w-k-s/short-url/codec"
+	domain "github.com/w-k-s/short-url/domain"
+	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateResponseFormat(t *testing.T) {
+
+	testCases := map[string]string{
+		"":                    "application/json;charset=utf-8",
+		"*/*":                 "application/json;charset=utf-8",
+		"text/html":           "application/json;charset=utf-8",
+		"application/json":    "application/json;charset=utf-8",
+		"application/xml":     "application/xml;charset=utf-8",
+		"text/xml":            "application/xml;charset=utf-8",
+		"application/x-yaml":  "application/yaml;charset=utf-8",
+		"application/msgpack": "application/msgpack",
+		"application/json;q=0.5, application/xml":      "application/xml;charset=utf-8",
+		"application/xml;q=0.5, application/*":         "application/json;charset=utf-8",
+		"application/*, application/json;q=0":          "application/xml;charset=utf-8",
+		"text/html, application/yaml;q=0.9, */*;q=0.8": "application/yaml;charset=utf-8",
+	}
+
+	negotiatingFmt := NewNegotiatingFmt()
+
+	for accept, expectation := range testCases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/url", nil)
+		req.Header.Set("Accept", accept)
+
+		negotiatingFmt.Print(w, req, http.StatusOK, usecase.ShortenURLResponse{ShortURL: "https://small.ml/shrt"})
+
+		if contentType := w.Header().Get("Content-Type"); contentType != expectation {
+			t.Errorf("Accept %q: got content type %s, want: %s.", accept, contentType, expectation)
+		}
+	}
+}
+
+func TestSendErrorAsXML(t *testing.T) {
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/url", nil)
+	req.Header.Set("Accept", "application/xml")
+
+	NewNegotiatingFmt().Error(w, req, domain.NewError(10300, "shortenUrl.validation", "message", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Send Error sends wrong status code, got: %d, want: %d.", w.Code, http.StatusBadRequest)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "<error><code>10300</code><domain>shortenUrl.validation</domain>") {
+		t.Errorf("Incorrect XML error, got: %s", body)
+	}
+}
+
+func TestSendErrorAsProblemWhenNegotiating(t *testing.T) {
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/url", nil)
+	req.Header.Set("Accept", "application/problem+json, application/xml;q=0.9")
+
+	NewNegotiatingFmt().Error(w, req, domain.NewError(10300, "shortenUrl.validation", "message", nil))
+
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json;charset=utf-8" {
+		t.Errorf("Send Error sends wrong content type, got: %s", contentType)
+	}
+}
+
+func TestPrintMsgPack(t *testing.T) {
+
+	w := httptest.NewRecorder()
+	NewMsgPackFmt().Print(w, nil, http.StatusOK, map[string]interface{}{"shortUrl": "https://small.ml/shrt"})
+
+	var body map[string]interface{}
+	if err := codec.UnmarshalMsgPack(w.Body.Bytes(), &body); err != nil || body["shortUrl"] != "https://small.ml/shrt" {
+		t.Errorf("Incorrect MessagePack body, got: %v (%v)", body, err)
+	}
+}
@@ -0,0 +1,118 @@
+package web
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+)
+
+var warningPage = template.Must(template.New("warning").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="robots" content="noindex">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>Warning: {{.Title}}</title>
+</head>
+<body>
+	<h1>{{.Title}}</h1>
+	<p>{{.Message}}</p>
+	{{if .Reason}}<p>Reason: {{.Reason}}</p>{{end}}
+</body>
+</html>
+`))
+
+// WarningPage renders an html page in place of a redirect, e.g. when a link has been disabled
+func WarningPage(w http.ResponseWriter, status int, title string, message string, reason string) {
+	w.Header().Set("Content-Type", "text/html;charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	warningPage.Execute(w, map[string]string{
+		"Title":   title,
+		"Message": message,
+		"Reason":  reason,
+	})
+}
+
+var previewPage = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="robots" content="noindex">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>Preview: {{.ShortURL}}</title>
+</head>
+<body>
+	<h1>{{.ShortURL}}</h1>
+	<p>This link takes you to:</p>
+	<p><a href="{{.LongURL}}" rel="noopener noreferrer nofollow">{{.LongURL}}</a></p>
+	<dl>
+		<dt>Created</dt>
+		<dd><time datetime="{{.CreateTime.Format "2006-01-02T15:04:05Z07:00"}}">{{.CreateTime.Format "2 January 2006 15:04 MST"}}</time></dd>
+		{{if ge .Clicks 0}}<dt>Clicks</dt>
+		<dd>{{.Clicks}}</dd>{{end}}
+	</dl>
+</body>
+</html>
+`))
+
+// PreviewPage renders an html page showing where a short url leads, in place of a redirect
+func PreviewPage(w http.ResponseWriter, shortURL string, longURL string, createTime time.Time, clicks int64) {
+	w.Header().Set("Content-Type", "text/html;charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	previewPage.Execute(w, map[string]interface{}{
+		"ShortURL":   shortURL,
+		"LongURL":    longURL,
+		"CreateTime": createTime.UTC(),
+		"Clicks":     clicks,
+	})
+}
+
+var socialPage = template.Must(template.New("social").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="robots" content="noindex">
+	<title>{{.Title}}</title>
+	<meta property="og:type" content="website">
+	<meta property="og:url" content="{{.ShortURL}}">
+	<meta property="og:title" content="{{.Title}}">
+	<meta name="twitter:title" content="{{.Title}}">
+	{{if .Description}}<meta property="og:description" content="{{.Description}}">
+	<meta name="twitter:description" content="{{.Description}}">
+	<meta name="description" content="{{.Description}}">{{end}}
+	{{if .Image}}<meta property="og:image" content="{{.Image}}">
+	<meta name="twitter:image" content="{{.Image}}">
+	<meta name="twitter:card" content="summary_large_image">{{else}}<meta name="twitter:card" content="summary">{{end}}
+	<meta http-equiv="refresh" content="0; url={{.LongURL}}">
+</head>
+<body>
+	<p><a href="{{.LongURL}}">{{.Title}}</a></p>
+</body>
+</html>
+`))
+
+// SocialPage renders an html page with OpenGraph and Twitter card tags in place of a redirect,
+// so that chat apps and social networks unfurl the short url with the title, description and image of the link.
+// It is cached like the redirect that it replaces (see RedirectCachePolicy).
+func SocialPage(w http.ResponseWriter, cacheControl string, shortURL string, longURL string, title string, description string, image string) {
+	if len(title) == 0 {
+		title = longURL
+	}
+
+	w.Header().Set("Content-Type", "text/html;charset=utf-8")
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Add("Vary", "User-Agent")
+	w.WriteHeader(http.StatusOK)
+
+	socialPage.Execute(w, map[string]string{
+		"ShortURL":    shortURL,
+		"LongURL":     longURL,
+		"Title":       title,
+		"Description": description,
+		"Image":       image,
+	})
+}
@@ -0,0 +1,52 @@
+package web
+
+import (
+	"github.com/w-k-s/short-url/domain"
+	"net/http"
+)
+
+// ProblemJSONContentType is the media type of RFC 7807 problem details
+const ProblemJSONContentType = "application/problem+json"
+
+// ProblemTypePrefix is prefixed to the domain of an error (e.g. `shortenUrl.validation`) to build the type of the problem
+const ProblemTypePrefix = "urn:short-url:problem:"
+
+// Problem is an error described by RFC 7807 (https://tools.ietf.org/html/rfc7807).
+// Code and Fields are extension members carrying the same values as the legacy error body.
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     domain.Code       `json:"code"`
+	Fields   map[string]string `json:"fields,omitempty"`
+}
+
+// ProblemBody describes the error that occurred while handling the request
+func ProblemBody(req *http.Request, e domain.Err) Problem {
+	status := HTTPStatusCode(e.Code())
+	problem := Problem{
+		Type:   ProblemTypePrefix + e.Domain(),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: e.Error(),
+		Code:   e.Code(),
+		Fields: e.Fields(),
+	}
+	if req != nil && req.URL != nil {
+		problem.Instance = req.URL.Path
+	}
+	return problem
+}
+
+// AcceptsProblemJSON returns true if the Accept header of the request lists `application/problem+json`
+// without a quality of 0, e.g. `Accept: application/problem+json, application/json;q=0.5`
+func AcceptsProblemJSON(req *http.Request) bool {
+	for _, r := range acceptedMediaRanges(req) {
+		if r.mediaType == ProblemJSONContentType && r.quality != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package web
+
+import (
+	"encoding/json"
+	domain "github.com/w-k-s/short-url/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendErrorAsProblem(t *testing.T) {
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "http://small.ml/urlshortener/v1/url?shortUrl=nil", nil)
+	req.Header.Set("Accept", "application/problem+json, application/json;q=0.5")
+
+	inputErr := domain.NewError(
+		10300,
+		"shortenUrl.validation",
+		"message",
+		map[string]string{"FIELD": "VALUE"},
+	)
+
+	NewJsonFmt().Error(w, req, inputErr)
+
+	resp := w.Result()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Send Error sends wrong status code, got: %d, want: %d.", resp.StatusCode, http.StatusBadRequest)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "application/problem+json;charset=utf-8" {
+		t.Errorf("Send Error sends wrong content type, got: %s", contentType)
+	}
+
+	var problem Problem
+	json.NewDecoder(resp.Body).Decode(&problem)
+
+	expectation := Problem{
+		Type:     "urn:short-url:problem:shortenUrl.validation",
+		Title:    "Bad Request",
+		Status:   http.StatusBadRequest,
+		Detail:   "message",
+		Instance: "/urlshortener/v1/url",
+		Code:     10300,
+		Fields:   map[string]string{"FIELD": "VALUE"},
+	}
+	if problem.Type != expectation.Type || problem.Title != expectation.Title || problem.Status != expectation.Status ||
+		problem.Detail != expectation.Detail || problem.Instance != expectation.Instance || problem.Code != expectation.Code ||
+		problem.Fields["FIELD"] != "VALUE" {
+		t.Errorf("Incorrect problem, got: %+v, want: %+v.", problem, expectation)
+	}
+}
+
+func TestAcceptsProblemJSON(t *testing.T) {
+
+	testCases := map[string]bool{
+		"":                                    false,
+		"application/json":                    false,
+		"*/*":                                 false,
+		"application/problem+json":            true,
+		"text/html, application/problem+json": true,
+		"application/problem+json;q=0":        false,
+	}
+
+	for accept, expectation := range testCases {
+		req := httptest.NewRequest("GET", "http://small.ml/", nil)
+		req.Header.Set("Accept", accept)
+
+		if AcceptsProblemJSON(req) != expectation {
+			t.Errorf("AcceptsProblemJSON(%q), got: %t, want: %t.", accept, !expectation, expectation)
+		}
+	}
+}
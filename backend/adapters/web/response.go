@@ -5,39 +5,38 @@ import (
 	"fmt"
 	"github.com/w-k-s/short-url/domain"
 	"github.com/w-k-s/short-url/domain/urlshortener/usecase"
+	"io"
 	"net/http"
 )
 
 type ResponseFmt interface {
-	Print(w http.ResponseWriter, status int, body interface{})
-	Error(w http.ResponseWriter, err domain.Err)
+	// Print writes the body in the format that the request accepts
+	Print(w http.ResponseWriter, req *http.Request, status int, body interface{})
+	// Error writes the error in the format that the request accepts
+	Error(w http.ResponseWriter, req *http.Request, err domain.Err)
 }
 
-type JsonFmt struct {
-	additionalHeaders map[string]string
+// StreamingFmt is a format that can write responses whose items are written as they become available
+type StreamingFmt interface {
+	Stream(w http.ResponseWriter, status int, ndjson bool) *Stream
 }
 
+type JsonFmt struct{}
+
 func NewJsonFmt() JsonFmt {
 	return JsonFmt{}
 }
 
-func NewJsonFmtWithHeaders(headers map[string]string) JsonFmt {
-	return JsonFmt{
-		headers,
-	}
-}
-
 func (jsonFmt *JsonFmt) setHeaders(w http.ResponseWriter, status int) {
+	jsonFmt.setHeadersWithContentType(w, status, "application/json;charset=utf-8")
+}
 
-	for key, value := range jsonFmt.additionalHeaders {
-		w.Header().Set(key, value)
-	}
-
-	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+func (jsonFmt *JsonFmt) setHeadersWithContentType(w http.ResponseWriter, status int, contentType string) {
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
 }
 
-func (jsonFmt JsonFmt) Print(w http.ResponseWriter, status int, body interface{}) {
+func (jsonFmt JsonFmt) Print(w http.ResponseWriter, req *http.Request, status int, body interface{}) {
 
 	jsonFmt.setHeaders(w, status)
 	encoder := json.NewEncoder(w)
@@ -49,34 +48,167 @@ func (jsonFmt JsonFmt) Print(w http.ResponseWriter, status int, body interface{}
 	}
 }
 
-func (jsonFmt JsonFmt) Error(w http.ResponseWriter, e domain.Err) {
+// Error writes the error as an RFC 7807 problem if the request accepts `application/problem+json`.
+// Otherwise the error is written in the legacy `{code,message,domain,fields}` format.
+func (jsonFmt JsonFmt) Error(w http.ResponseWriter, req *http.Request, e domain.Err) {
 
 	encoder := json.NewEncoder(w)
 
-	jsonFmt.setHeaders(w, httpStatusCode(e.Code()))
-	err := encoder.Encode(map[string]interface{}{
+	var body interface{} = ErrorBody(e)
+	contentType := "application/json;charset=utf-8"
+	if AcceptsProblemJSON(req) {
+		body = ProblemBody(req, e)
+		contentType = ProblemJSONContentType + ";charset=utf-8"
+	}
+
+	jsonFmt.setHeadersWithContentType(w, HTTPStatusCode(e.Code()), contentType)
+	err := encoder.Encode(body)
+	if err != nil {
+		sendEncodingError(w, e, err)
+	}
+}
+
+// Stream starts a response whose items are written as they become available,
+// either as a JSON array or as newline-delimited JSON (one item per line)
+func (jsonFmt JsonFmt) Stream(w http.ResponseWriter, status int, ndjson bool) *Stream {
+	contentType := "application/json;charset=utf-8"
+	if ndjson {
+		contentType = "application/x-ndjson;charset=utf-8"
+	}
+	jsonFmt.setHeadersWithContentType(w, status, contentType)
+
+	return &Stream{
+		w:      w,
+		ndjson: ndjson,
+	}
+}
+
+// Stream writes the items of a streamed response, sending each item to the client as soon as it is written
+type Stream struct {
+	w      http.ResponseWriter
+	ndjson bool
+	count  int
+}
+
+func (s *Stream) Write(item interface{}) error {
+	body, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	var prefix, suffix string
+	switch {
+	case s.ndjson:
+		suffix = "\n"
+	case s.count == 0:
+		prefix = "["
+	default:
+		prefix = ",\n"
+	}
+	s.count++
+
+	if _, err = io.WriteString(s.w, prefix+string(body)+suffix); err != nil {
+		return err
+	}
+	s.flush()
+	return nil
+}
+
+// Close ends the response
+func (s *Stream) Close() error {
+	if s.ndjson {
+		return nil
+	}
+
+	end := "]\n"
+	if s.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := io.WriteString(s.w, end); err != nil {
+		return err
+	}
+	s.flush()
+	return nil
+}
+
+func (s *Stream) flush() {
+	// Not every ResponseWriter can be flushed; the response is then sent when the handler returns
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// ErrorBody is the body of an error response
+func ErrorBody(e domain.Err) map[string]interface{} {
+	return map[string]interface{}{
 		"code":    e.Code(),
 		"message": e.Error(),
 		"domain":  e.Domain(),
 		"fields":  e.Fields(),
-	})
-	if err != nil {
-		sendEncodingError(w, e, err)
 	}
 }
 
-func httpStatusCode(e domain.Code) int {
+// HTTPStatusCode returns the HTTP status of errors with the code. The gRPC API derives its status codes from it.
+func HTTPStatusCode(e domain.Code) int {
 	switch e {
 	case usecase.ShortenURLValidation:
 		fallthrough
+	case usecase.ShortenURLSchemeNotAllowed:
+		fallthrough
+	case usecase.ShortenURLHostNotAllowed:
+		fallthrough
+	case usecase.ShortenURLTooLong:
+		fallthrough
+	case usecase.ShortenURLSelfLink:
+		fallthrough
+	case usecase.ShortenURLRedirectLoop:
+		fallthrough
+	case usecase.ShortenURLMalicious:
+		fallthrough
+	case usecase.ShortenURLDomainNotAllowed:
+		fallthrough
+	case usecase.UpdateURLDecoding:
+		fallthrough
+	case usecase.UpdateURLValidation:
+		fallthrough
+	case usecase.QRCodeValidation:
+		fallthrough
+	case usecase.BatchShortenURLDecoding:
+		fallthrough
+	case usecase.ListURLsValidation:
+		fallthrough
+	case usecase.ClickStatsValidation:
+		fallthrough
 	case usecase.RetrieveFullURLValidation:
 		fallthrough
+	case usecase.RetrieveFullURLDomainNotAllowed:
+		fallthrough
 	case usecase.ShortenURLShortIDInUse:
 		return http.StatusBadRequest
 	case usecase.RetrieveFullURLNotFound:
 		fallthrough
 	case usecase.RedirectionFullURLNotFound:
+		fallthrough
+	case usecase.UpdateURLNotFound:
+		fallthrough
+	case usecase.ClickStatsNotFound:
+		fallthrough
+	case usecase.QRCodeNotFound:
 		return http.StatusNotFound
+	case usecase.RetrieveFullURLDisabled:
+		fallthrough
+	case usecase.QRCodeDisabled:
+		return http.StatusForbidden
+	case usecase.Unauthorized:
+		return http.StatusUnauthorized
+	case usecase.ShortenURLIdempotencyKeyInProgress:
+		return http.StatusConflict
+	case usecase.ShortenURLIdempotencyKeyReused:
+		return http.StatusUnprocessableEntity
+	case usecase.BatchShortenURLTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case usecase.RetrieveFullURLRedirectLoop:
+		return http.StatusLoopDetected
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,76 @@
+package codec
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+)
+
+// maxBodySize is the largest request body that is decoded
+const maxBodySize = 1 << 20
+
+// IsMsgPackContentType returns true if the content type is one of the media types used for MessagePack
+func IsMsgPackContentType(mediaType string) bool {
+	switch mediaType {
+	case MsgPackContentType, "application/x-msgpack", "application/vnd.msgpack":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsXMLContentType returns true if the content type is one of the media types used for XML
+func IsXMLContentType(mediaType string) bool {
+	return mediaType == XMLContentType || mediaType == "text/xml"
+}
+
+// IsYAMLContentType returns true if the content type is one of the media types used for YAML
+func IsYAMLContentType(mediaType string) bool {
+	switch mediaType {
+	case YAMLContentType, "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	default:
+		return false
+	}
+}
+
+// DecodeBody decodes the body of the request into the value that v points at, in the format of its Content-Type:
+// forms, XML, YAML or MessagePack. JSON is the default because clients do not always set a Content-Type.
+func DecodeBody(req *http.Request, v interface{}) error {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+
+	switch {
+	case mediaType == FormContentType || mediaType == "multipart/form-data":
+		req.Body = http.MaxBytesReader(nil, req.Body, maxBodySize)
+		if err := req.ParseMultipartForm(maxBodySize); err != nil && err != http.ErrNotMultipart {
+			return err
+		}
+		return UnmarshalForm(req.PostForm, v)
+	case IsXMLContentType(mediaType):
+		data, err := readBody(req.Body)
+		if err != nil {
+			return err
+		}
+		return UnmarshalXML(data, v)
+	case IsYAMLContentType(mediaType):
+		data, err := readBody(req.Body)
+		if err != nil {
+			return err
+		}
+		return UnmarshalYAML(data, v)
+	case IsMsgPackContentType(mediaType):
+		data, err := readBody(req.Body)
+		if err != nil {
+			return err
+		}
+		return UnmarshalMsgPack(data, v)
+	default:
+		return json.NewDecoder(req.Body).Decode(v)
+	}
+}
+
+func readBody(body io.Reader) ([]byte, error) {
+	return ioutil.ReadAll(io.LimitReader(body, maxBodySize))
+}
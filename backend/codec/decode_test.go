@@ -0,0 +1,97 @@
+package codec
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecodeFormBody(t *testing.T) {
+	//Given
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v1/url", strings.NewReader(
+		"longUrl=http%3A%2F%2Fwww.example.com&count=3&enabled=on&tags=a&tags=b&metadata.campaign=launch",
+	))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	//When
+	var decoded testRecord
+	err := DecodeBody(req, &decoded)
+
+	//Then
+	assert.Nil(t, err)
+	assert.Equal(t, testRecord{
+		LongURL:  "http://www.example.com",
+		Count:    3,
+		Enabled:  true,
+		Tags:     []string{"a", "b"},
+		Metadata: map[string]string{"campaign": "launch"},
+	}, decoded)
+}
+
+func TestDecodeMultipartBody(t *testing.T) {
+	//Given
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("longUrl", "http://www.example.com")
+	writer.WriteField("enabled", "true")
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v1/url", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	//When
+	var decoded testRecord
+	err := DecodeBody(req, &decoded)
+
+	//Then
+	assert.Nil(t, err)
+	assert.Equal(t, "http://www.example.com", decoded.LongURL)
+	assert.True(t, decoded.Enabled)
+}
+
+func TestDecodeYAMLBody(t *testing.T) {
+	//Given
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v1/url", strings.NewReader(
+		"longUrl: http://www.example.com\ncount: 3\nratio: 0.5\ntags: [a, b]\nmetadata:\n  campaign: launch\n",
+	))
+	req.Header.Set("Content-Type", "application/x-yaml")
+
+	//When
+	var decoded testRecord
+	err := DecodeBody(req, &decoded)
+
+	//Then
+	assert.Nil(t, err)
+	assert.Equal(t, testRecord{
+		LongURL:  "http://www.example.com",
+		Count:    3,
+		Ratio:    0.5,
+		Tags:     []string{"a", "b"},
+		Metadata: map[string]string{"campaign": "launch"},
+	}, decoded)
+}
+
+func TestDecodeJSONBodyByDefault(t *testing.T) {
+	req := httptest.NewRequest("POST", "http://small.ml/urlshortener/v1/url", strings.NewReader(`{"longUrl":"http://www.example.com"}`))
+
+	var decoded testRecord
+	err := DecodeBody(req, &decoded)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "http://www.example.com", decoded.LongURL)
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	record := testRecord{LongURL: "http://www.example.com", Count: 7, Tags: []string{"a"}}
+
+	data, err := MarshalYAML(record)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "longUrl: http://www.example.com")
+
+	var decoded testRecord
+	assert.Nil(t, UnmarshalYAML(data, &decoded))
+	assert.Equal(t, record, decoded)
+}
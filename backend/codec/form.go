@@ -0,0 +1,43 @@
+package codec
+
+import (
+	"net/url"
+	"strings"
+)
+
+// FormContentType is the media type of url-encoded forms
+const FormContentType = "application/x-www-form-urlencoded"
+
+// UnmarshalForm decodes the form into the value that v points at, as if it had been decoded from JSON.
+// Dots separate the fields of nested objects (e.g. `utm.source=newsletter`) and repeated fields are lists (e.g. `tags=a&tags=b`).
+func UnmarshalForm(form url.Values, v interface{}) error {
+	return FromGeneric(DecodeForm(form), v)
+}
+
+// DecodeForm decodes the form into generic values
+func DecodeForm(form url.Values) map[string]interface{} {
+	object := map[string]interface{}{}
+	for key, values := range form {
+		var value interface{} = values[0]
+		if len(values) > 1 {
+			list := make([]interface{}, len(values))
+			for index, item := range values {
+				list[index] = item
+			}
+			value = list
+		}
+
+		path := strings.Split(key, ".")
+		parent := object
+		for _, name := range path[:len(path)-1] {
+			child, ok := parent[name].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				parent[name] = child
+			}
+			parent = child
+		}
+		parent[path[len(path)-1]] = value
+	}
+	return object
+}
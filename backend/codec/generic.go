@@ -0,0 +1,184 @@
+// Package codec converts values to and from the formats that the API accepts besides JSON
+// (XML, YAML, MessagePack and forms).
+//
+// XML uses the `xml` tags of the types, and MessagePack their `json` tags.
+// YAML and forms are converted through the generic values that encoding/json produces (maps, slices, strings, numbers, booleans and nil),
+// so that they have the same field names as JSON.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ToGeneric converts the value into the generic values that it is encoded as in JSON.
+// Whole numbers are int64 and other numbers are float64.
+func ToGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return fromJSONNumbers(generic), nil
+}
+
+func fromJSONNumbers(value interface{}) interface{} {
+	switch value := value.(type) {
+	case json.Number:
+		if number, err := value.Int64(); err == nil {
+			return number
+		}
+		number, _ := value.Float64()
+		return number
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = fromJSONNumbers(item)
+		}
+	case []interface{}:
+		for index, item := range value {
+			value[index] = fromJSONNumbers(item)
+		}
+	}
+	return value
+}
+
+// FromGeneric decodes generic values into the value that v points at, as if the generic values had been decoded from JSON.
+// Strings are converted into the numbers, booleans and lists that v expects, because forms and XML only have text.
+func FromGeneric(generic interface{}, v interface{}) error {
+	target := reflect.TypeOf(v)
+	if target == nil || target.Kind() != reflect.Ptr {
+		return fmt.Errorf("codec: FromGeneric expects a pointer, got %T", v)
+	}
+
+	data, err := json.Marshal(coerce(generic, target.Elem()))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// coerce converts the generic value into the kind of value that is decoded into the type
+func coerce(value interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	text, isText := value.(string)
+
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		// An empty element in XML or an empty form field
+		if isText && len(strings.TrimSpace(text)) == 0 {
+			return nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		fields := jsonFields(t)
+		for key, item := range object {
+			for name, fieldType := range fields {
+				if strings.EqualFold(key, name) {
+					object[key] = coerce(item, fieldType)
+					break
+				}
+			}
+		}
+		return object
+	case reflect.Map:
+		if object, ok := value.(map[string]interface{}); ok {
+			for key, item := range object {
+				object[key] = coerce(item, t.Elem())
+			}
+		}
+		return value
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return value
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			// A list with a single item can not be told apart from the item in forms and XML
+			if value == nil || (isText && len(text) == 0) {
+				return []interface{}{}
+			}
+			list = []interface{}{value}
+		}
+		for index, item := range list {
+			list[index] = coerce(item, t.Elem())
+		}
+		return list
+	case reflect.Bool:
+		if isText {
+			// Checkboxes in HTML forms are sent as `on`
+			switch strings.ToLower(strings.TrimSpace(text)) {
+			case "on":
+				return true
+			case "off":
+				return false
+			}
+			if parsed, err := strconv.ParseBool(strings.TrimSpace(text)); err == nil {
+				return parsed
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if isText {
+			if _, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err == nil {
+				return json.Number(strings.TrimSpace(text))
+			}
+		}
+	case reflect.String:
+		switch value.(type) {
+		case bool, int64, float64:
+			return fmt.Sprint(value)
+		}
+	}
+	return value
+}
+
+// jsonFields returns the types of the fields of the struct by the name that they have in JSON
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for index := 0; index < t.NumField(); index++ {
+		field := t.Field(index)
+		if len(field.PkgPath) > 0 && !field.Anonymous {
+			continue
+		}
+
+		name := field.Name
+		if tag := field.Tag.Get("json"); len(tag) > 0 {
+			if tag == "-" {
+				continue
+			}
+			if tagName := strings.Split(tag, ",")[0]; len(tagName) > 0 {
+				name = tagName
+			}
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && len(field.Tag.Get("json")) == 0 {
+			for embeddedName, embeddedType := range jsonFields(field.Type) {
+				fields[embeddedName] = embeddedType
+			}
+			continue
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
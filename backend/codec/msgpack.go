@@ -0,0 +1,24 @@
+package codec
+
+import (
+	"bytes"
+	"github.com/vmihailenco/msgpack"
+)
+
+// MsgPackContentType is the media type of MessagePack (https://msgpack.org)
+const MsgPackContentType = "application/msgpack"
+
+// MarshalMsgPack encodes the value as MessagePack, with the same field names as JSON.
+// Numbers are encoded in the fewest bytes, and map keys are sorted so that the same value is always encoded the same way.
+func MarshalMsgPack(v interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := msgpack.NewEncoder(&buffer).UseJSONTag(true).UseCompactEncoding(true).SortMapKeys(true).Encode(v); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalMsgPack decodes MessagePack into the value that v points at, with the same field names as JSON
+func UnmarshalMsgPack(data []byte, v interface{}) error {
+	return msgpack.NewDecoder(bytes.NewReader(data)).UseJSONTag(true).Decode(v)
+}
@@ -0,0 +1,56 @@
+package codec
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strings"
+	"testing"
+)
+
+type testRecord struct {
+	LongURL  string    `json:"longUrl" xml:"longUrl"`
+	Count    int64     `json:"count" xml:"count"`
+	Ratio    float64   `json:"ratio" xml:"ratio"`
+	Enabled  bool      `json:"enabled" xml:"enabled"`
+	Tags     []string  `json:"tags" xml:"tags>item"`
+	Metadata StringMap `json:"metadata" xml:"metadata"`
+	Owner    *string   `json:"owner" xml:"owner"`
+}
+
+func TestMsgPackRoundTrip(t *testing.T) {
+	//Given
+	record := testRecord{
+		LongURL:  strings.Repeat("a", 300),
+		Count:    math.MaxInt64,
+		Ratio:    0.25,
+		Enabled:  true,
+		Tags:     []string{"summer", "sale"},
+		Metadata: map[string]string{"campaign": "launch"},
+	}
+
+	//When
+	data, err := MarshalMsgPack(record)
+	assert.Nil(t, err)
+
+	var decoded testRecord
+	err = UnmarshalMsgPack(data, &decoded)
+
+	//Then
+	assert.Nil(t, err)
+	assert.Equal(t, record, decoded)
+}
+
+func TestMsgPackSmallValues(t *testing.T) {
+	data, err := MarshalMsgPack(map[string]interface{}{"a": 1, "b": -1, "c": nil, "d": false})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x84, 0xa1, 'a', 0x01, 0xa1, 'b', 0xff, 0xa1, 'c', 0xc0, 0xa1, 'd', 0xc2}, data)
+}
+
+func TestMsgPackTruncatedData(t *testing.T) {
+	var decoded testRecord
+
+	assert.NotNil(t, UnmarshalMsgPack([]byte{0x81, 0xa7, 'l', 'o', 'n', 'g'}, &decoded))
+	assert.NotNil(t, UnmarshalMsgPack([]byte{0xdd, 0xff, 0xff, 0xff, 0xff}, &decoded))
+	assert.NotNil(t, UnmarshalMsgPack([]byte{0xdb, 0xff, 0xff, 0xff, 0xff, 'a'}, &decoded))
+}
@@ -0,0 +1,68 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/xml"
+	"sort"
+)
+
+// XMLContentType is the media type of XML
+const XMLContentType = "application/xml"
+
+// MarshalXML encodes the value as XML in the root element, with the element names of the `xml` tags of its type
+func MarshalXML(root string, v interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buffer).EncodeElement(v, xml.StartElement{Name: xml.Name{Local: root}}); err != nil {
+		return nil, err
+	}
+	buffer.WriteString("\n")
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalXML decodes the root element into the value that v points at, whatever the name of the root element is
+func UnmarshalXML(data []byte, v interface{}) error {
+	return xml.Unmarshal(data, v)
+}
+
+// StringMap is a map of strings that can be encoded as XML, which encoding/xml does not do for maps.
+// Each key is an `<entry key="...">` element, since keys are not always valid element names
+// e.g. `<metadata><entry key="campaign">launch</entry></metadata>`.
+type StringMap map[string]string
+
+// xmlEntry is an entry of a StringMap in XML
+type xmlEntry struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (m StringMap) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]xmlEntry, len(keys))
+	for index, key := range keys {
+		entries[index] = xmlEntry{key, m[key]}
+	}
+	return encoder.EncodeElement(struct {
+		Entries []xmlEntry `xml:"entry"`
+	}{entries}, start)
+}
+
+func (m *StringMap) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var entries struct {
+		Entries []xmlEntry `xml:"entry"`
+	}
+	if err := decoder.DecodeElement(&entries, &start); err != nil {
+		return err
+	}
+
+	*m = make(StringMap, len(entries.Entries))
+	for _, entry := range entries.Entries {
+		(*m)[entry.Key] = entry.Value
+	}
+	return nil
+}
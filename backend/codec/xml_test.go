@@ -0,0 +1,46 @@
+package codec
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestXMLRoundTrip(t *testing.T) {
+	//Given
+	record := testRecord{
+		LongURL:  "http://www.example.com?a=1&b=<2>",
+		Count:    42,
+		Ratio:    0.5,
+		Enabled:  true,
+		Tags:     []string{"summer"},
+		Metadata: map[string]string{"1st": "first", "item": "reserved"},
+	}
+
+	//When
+	data, err := MarshalXML("response", record)
+	assert.Nil(t, err)
+
+	var decoded testRecord
+	err = UnmarshalXML(data, &decoded)
+
+	//Then
+	assert.Nil(t, err)
+	assert.Equal(t, record, decoded)
+	assert.Contains(t, string(data), `<tags><item>summer</item></tags>`)
+	assert.Contains(t, string(data), `<entry key="1st">first</entry>`)
+}
+
+func TestXMLRequest(t *testing.T) {
+	var decoded testRecord
+	err := UnmarshalXML([]byte(`<request><longUrl>http://www.example.com</longUrl><tags><item>a</item><item>b</item></tags><metadata/><unknown><a/></unknown></request>`), &decoded)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "http://www.example.com", decoded.LongURL)
+	assert.Equal(t, []string{"a", "b"}, decoded.Tags)
+	assert.Empty(t, decoded.Metadata)
+}
+
+func TestXMLWithoutRootElement(t *testing.T) {
+	var decoded testRecord
+	assert.NotNil(t, UnmarshalXML([]byte(`<?xml version="1.0"?>`), &decoded))
+}
@@ -0,0 +1,48 @@
+package codec
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+)
+
+// YAMLContentType is the media type of YAML
+const YAMLContentType = "application/yaml"
+
+// MarshalYAML encodes the value as YAML, with the same field names as JSON
+func MarshalYAML(v interface{}) ([]byte, error) {
+	generic, err := ToGeneric(v)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(generic)
+}
+
+// UnmarshalYAML decodes YAML into the value that v points at, as if it had been decoded from JSON
+func UnmarshalYAML(data []byte, v interface{}) error {
+	var generic interface{}
+	if err := yaml.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+	return FromGeneric(fromYAML(generic), v)
+}
+
+// fromYAML converts the maps that YAML decodes (which can have keys of any type) into maps with string keys
+func fromYAML(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			object[fmt.Sprint(key)] = fromYAML(item)
+		}
+		return object
+	case []interface{}:
+		for index, item := range value {
+			value[index] = fromYAML(item)
+		}
+		return value
+	case int:
+		return int64(value)
+	default:
+		return value
+	}
+}
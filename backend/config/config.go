@@ -4,14 +4,52 @@ import (
 	env "github.com/Netflix/go-env"
 	"github.com/w-k-s/short-url/log"
 	"net/url"
+	"strings"
+	"time"
 )
 
 type settings struct {
-	DatabaseConnectionString       string `env:"DB_CONN_STRING,required=true"`
-	ListenAddress                  string `env:"ADDRESS,default=:80"`
-	BaseURL                        string `env:"BASE_URL,required=true"`
-	AccessControlAllowOriginHeader string `env:"ALLOW_ORIGIN"`
+	DatabaseConnectionString       string        `env:"DB_CONN_STRING,required=true"`
+	ListenAddress                  string        `env:"ADDRESS,default=:80"`
+	RequestTimeout                 time.Duration `env:"REQUEST_TIMEOUT,default=5s"`
+	GRPCListenAddress              string        `env:"GRPC_ADDRESS"`
+	BaseURL                        string        `env:"BASE_URL,required=true"`
+	PathPrefix                     string        `env:"PATH_PREFIX"`
+	AccessControlAllowOriginHeader string        `env:"ALLOW_ORIGIN"`
+	CORSAllowedOrigins             string        `env:"CORS_ALLOWED_ORIGINS"`
+	CORSAllowedMethods             string        `env:"CORS_ALLOWED_METHODS,default=GET POST PATCH"`
+	CORSAllowedHeaders             string        `env:"CORS_ALLOWED_HEADERS,default=Accept Authorization Content-Type Idempotency-Key If-Modified-Since If-None-Match"`
+	CORSExposedHeaders             string        `env:"CORS_EXPOSED_HEADERS,default=ETag Idempotent-Replayed Last-Modified"`
+	CORSAllowCredentials           bool          `env:"CORS_ALLOW_CREDENTIALS,default=false"`
+	CORSMaxAge                     time.Duration `env:"CORS_MAX_AGE,default=10m"`
+	AllowedSchemes                 string        `env:"ALLOWED_SCHEMES,default=http https"`
+	DeniedHosts                    string        `env:"DENIED_HOSTS,default=localhost"`
+	DeniedNetworks                 string        `env:"DENIED_NETWORKS"`
+	MaxURLLength                   int           `env:"MAX_URL_LENGTH,default=2048"`
+	ResolveDestinationHosts        bool          `env:"RESOLVE_DESTINATION_HOSTS,default=false"`
+	AliasDomains                   string        `env:"ALIAS_DOMAINS"`
+	BrandedDomains                 string        `env:"BRANDED_DOMAINS"`
+	SelfLinkPolicy                 string        `env:"SELF_LINK_POLICY,default=resolve"`
+	MaxRedirectDepth               int           `env:"MAX_REDIRECT_DEPTH,default=5"`
+	BlocklistHostsFile             string        `env:"BLOCKLIST_HOSTS_FILE"`
+	BlocklistHashesFile            string        `env:"BLOCKLIST_HASHES_FILE"`
+	BlocklistReloadInterval        time.Duration `env:"BLOCKLIST_RELOAD_INTERVAL,default=30s"`
+	ScreenOnRedirect               bool          `env:"SCREEN_ON_REDIRECT,default=false"`
+	AdminToken                     string        `env:"ADMIN_TOKEN"`
+	RedirectStatus                 int           `env:"REDIRECT_STATUS,default=303"`
+	PermanentRedirectMaxAge        time.Duration `env:"PERMANENT_REDIRECT_MAX_AGE,default=24h"`
+	TemporaryRedirectMaxAge        time.Duration `env:"TEMPORARY_REDIRECT_MAX_AGE,default=0s"`
+	CountryHeader                  string        `env:"COUNTRY_HEADER,default=CloudFront-Viewer-Country"`
+	FetchSocialMetadata            bool          `env:"FETCH_SOCIAL_METADATA,default=false"`
+	SocialMetadataTimeout          time.Duration `env:"SOCIAL_METADATA_TIMEOUT,default=3s"`
+	BatchConcurrency               int           `env:"BATCH_CONCURRENCY,default=8"`
+	BatchInsertSize                int           `env:"BATCH_INSERT_SIZE,default=500"`
+	MaxBatchItems                  int           `env:"MAX_BATCH_ITEMS,default=10000"`
+	IdempotencyKeyTTL              time.Duration `env:"IDEMPOTENCY_KEY_TTL,default=24h"`
+	IdempotencyCleanUpInterval     time.Duration `env:"IDEMPOTENCY_CLEANUP_INTERVAL,default=10m"`
+	IdempotencyCleanUpBatchSize    int           `env:"IDEMPOTENCY_CLEANUP_BATCH_SIZE,default=1000"`
 	baseURL                        *url.URL
+	brandedDomains                 []*url.URL
 }
 
 var Settings settings
@@ -21,6 +59,74 @@ func (s settings) GetBaseURL() *url.URL {
 	return s.baseURL
 }
 
+// GetPathPrefix returns the PATH_PREFIX that routes are mounted under (e.g. `/go`), or the path of BASE_URL if it is not set.
+// It is empty if routes are mounted at the root.
+func (s settings) GetPathPrefix() string {
+	prefix := s.PathPrefix
+	if len(prefix) == 0 {
+		prefix = s.baseURL.Path
+	}
+	prefix = strings.Trim(prefix, "/")
+	if len(prefix) == 0 {
+		return ""
+	}
+	return "/" + prefix
+}
+
+func (s settings) GetAllowedSchemes() []string {
+	return splitList(s.AllowedSchemes)
+}
+
+func (s settings) GetDeniedHosts() []string {
+	return splitList(s.DeniedHosts)
+}
+
+// GetDeniedNetworks returns the DENIED_NETWORKS. It is empty if the variable is not set.
+func (s settings) GetDeniedNetworks() []string {
+	return splitList(s.DeniedNetworks)
+}
+
+// GetAliasDomains returns the ALIAS_DOMAINS, which serve the same short urls as BASE_URL
+func (s settings) GetAliasDomains() []string {
+	return splitList(s.AliasDomains)
+}
+
+// GetCORSAllowedOrigins returns the origins and origin patterns (e.g. `https://*.small.ml`) that may call the api from a browser.
+// ALLOW_ORIGIN is the single origin that was allowed before CORS_ALLOWED_ORIGINS; it is used if CORS_ALLOWED_ORIGINS is not set.
+func (s settings) GetCORSAllowedOrigins() []string {
+	if len(strings.TrimSpace(s.CORSAllowedOrigins)) == 0 {
+		return splitList(s.AccessControlAllowOriginHeader)
+	}
+	return splitList(s.CORSAllowedOrigins)
+}
+
+func (s settings) GetCORSAllowedMethods() []string {
+	return splitList(s.CORSAllowedMethods)
+}
+
+func (s settings) GetCORSAllowedHeaders() []string {
+	return splitList(s.CORSAllowedHeaders)
+}
+
+func (s settings) GetCORSExposedHeaders() []string {
+	return splitList(s.CORSExposedHeaders)
+}
+
+// GetBrandedDomains returns the base urls of the BRANDED_DOMAINS, e.g. `https://brand.ly https://go.example.com`.
+// Each branded domain has its own short urls.
+func (s settings) GetBrandedDomains() []*url.URL {
+	return s.brandedDomains
+}
+
+// GetDomains returns the host of BASE_URL followed by the ALIAS_DOMAINS and the hosts of the BRANDED_DOMAINS
+func (s settings) GetDomains() []string {
+	domains := append([]string{s.baseURL.Host}, s.GetAliasDomains()...)
+	for _, branded := range s.brandedDomains {
+		domains = append(domains, branded.Host)
+	}
+	return domains
+}
+
 func Init() {
 	_, err := env.UnmarshalFromEnviron(&Settings)
 	if err != nil {
@@ -38,4 +144,27 @@ func Init() {
 		log.Fatalf("Failed to determine host from BASE_URL %q", baseURL)
 	}
 	Settings.baseURL = baseURL
+
+	// Short urls are made from BASE_URL, so they would not be routed if its path is not the prefix that routes are mounted under
+	basePath := strings.Trim(baseURL.Path, "/")
+	if pathPrefix := strings.Trim(Settings.PathPrefix, "/"); len(pathPrefix) > 0 && len(basePath) > 0 && pathPrefix != basePath {
+		log.Fatalf("PATH_PREFIX %q is not the path of BASE_URL %q. Set one of them, or the same path in both", Settings.PathPrefix, Settings.BaseURL)
+	}
+
+	Settings.brandedDomains = nil
+	for _, rawURL := range splitList(Settings.BrandedDomains) {
+		branded, err := url.Parse(rawURL)
+		if err != nil || len(branded.Scheme) == 0 || len(branded.Host) == 0 {
+			log.Fatalf("Failed to parse branded domain %q in BRANDED_DOMAINS. Expected a url e.g. 'https://brand.ly'", rawURL)
+		}
+		Settings.brandedDomains = append(Settings.brandedDomains, branded)
+	}
+}
+
+// splitList splits a list separated by commas and/or whitespace.
+// Lists are usually space-separated because go-env does not allow commas in default values.
+func splitList(list string) []string {
+	return strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
 }
@@ -0,0 +1,22 @@
+package urlshortener
+
+import (
+	"time"
+)
+
+// Click is a visit to a short url. Variant is the Destination the visitor was sent to, if any.
+type Click struct {
+	Domain     string    `bson:"domain"`
+	ShortID    string    `bson:"shortId"`
+	Variant    string    `bson:"variant"`
+	CreateTime time.Time `bson:"createTime"`
+}
+
+// ClickCounts is the number of clicks on a short url, grouped by variant.
+// Clicks that were not split across destinations are counted under the empty variant.
+type ClickCounts map[string]int64
+
+type AnalyticsRepository interface {
+	SaveClick(click *Click) error
+	ClickCounts(domain string, shortID string) (ClickCounts, error)
+}
@@ -0,0 +1,30 @@
+package urlshortener
+
+import (
+	"time"
+)
+
+// IdempotencyRecord remembers the response to a request that was sent with an idempotency key,
+// so that a retry of the request gets the same response instead of being carried out again.
+type IdempotencyRecord struct {
+	// Owner scopes the key, so that clients can not replay or block each other's requests by sending the same key
+	Owner string
+	Key   string
+	// Fingerprint identifies the request, so that a key can not be reused for a different request
+	Fingerprint string
+	// Response is the encoded response, or nil while the request is in progress
+	Response   []byte
+	CreateTime time.Time
+	ExpireTime time.Time
+}
+
+type IdempotencyRepository interface {
+	// ReserveKey saves the record without a response, unless a record with the same owner and key has not expired yet.
+	// It returns false if the key is already reserved.
+	ReserveKey(record *IdempotencyRecord) (bool, error)
+	// FindKey returns the record of the owner's key, or nil if there is none
+	FindKey(owner string, key string) (*IdempotencyRecord, error)
+	SaveResponse(owner string, key string, response []byte) error
+	// ReleaseKey deletes the record of the owner's key, so that the request can be retried with the same key
+	ReleaseKey(owner string, key string) error
+}
@@ -0,0 +1,53 @@
+package urlshortener
+
+import (
+	"time"
+)
+
+// SortField is the field that listed records are ordered by. Records with the same value are ordered by shortId, then domain.
+type SortField string
+
+const (
+	SortByCreateTime SortField = "createTime"
+	SortByLongURL    SortField = "longUrl"
+	SortByShortID    SortField = "shortId"
+)
+
+// RecordQuery selects a page of records. Filters that are left empty match every record.
+type RecordQuery struct {
+	Domain string
+	Owner  string
+	// CreatedAfter is inclusive and CreatedBefore is exclusive
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Host is matched against the host of the long url, ignoring case
+	Host string
+	// Search is a substring of the long url or the shortId, ignoring case
+	Search string
+	// Tags are the tags that every record must have
+	Tags []string
+
+	SortBy     SortField
+	Descending bool
+	// After is the position of the last record of the previous page, or nil for the first page
+	After *RecordPosition
+	Limit int
+}
+
+// RecordPosition is the position of a record in a listing
+type RecordPosition struct {
+	CreateTime time.Time
+	LongURL    string
+	ShortID    string
+	Domain     string
+}
+
+// PositionOf returns the position of the record in a listing
+func PositionOf(record *URLRecord) RecordPosition {
+	return RecordPosition{
+		CreateTime: record.CreateTime,
+		LongURL:    record.LongURL,
+		ShortID:    record.ShortID,
+		Domain:     record.Domain,
+	}
+}
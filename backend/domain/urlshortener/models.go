@@ -5,13 +5,59 @@ import (
 )
 
 type URLRecord struct {
-	LongURL    string    `bson:"longUrl"`
-	ShortID    string    `bson:"shortId"`
-	CreateTime time.Time `bson:"createTime"`
+	LongURL string `bson:"longUrl"`
+	// Domain is the host the short url is served on. ShortIDs are unique per domain.
+	Domain         string          `bson:"domain"`
+	ShortID        string          `bson:"shortId"`
+	Owner          string          `bson:"owner"`
+	CreateTime     time.Time       `bson:"createTime"`
+	UpdateTime     time.Time       `bson:"updateTime"`
+	Disabled       bool            `bson:"disabled"`
+	DisabledReason string          `bson:"disabledReason"`
+	RedirectStatus int             `bson:"redirectStatus"`
+	QueryMerge     string          `bson:"queryMerge"`
+	Wildcard       bool            `bson:"wildcard"`
+	TargetingRules []TargetingRule `bson:"targetingRules"`
+	Destinations   []Destination   `bson:"destinations"`
+	Social         SocialMetadata  `bson:"social"`
+	// Tags and Metadata are used to organize links e.g. by campaign or team. They do not affect redirects.
+	Tags     []string          `bson:"tags"`
+	Metadata map[string]string `bson:"metadata"`
+}
+
+// TargetingRule sends visitors that match all of its non-empty conditions to LongURL.
+// Rules are evaluated in order; URLRecord.LongURL is used if no rule matches.
+type TargetingRule struct {
+	Platform string `bson:"platform"`
+	Language string `bson:"language"`
+	Country  string `bson:"country"`
+	LongURL  string `bson:"longUrl"`
+}
+
+// Destination is one of several long urls that visitors are split across, in proportion to its Weight.
+// Visitors that are not matched by a TargetingRule are split across the destinations instead of being sent to URLRecord.LongURL.
+type Destination struct {
+	Variant string `bson:"variant"`
+	LongURL string `bson:"longUrl"`
+	Weight  int    `bson:"weight"`
+}
+
+// SocialMetadata is shown by chat apps and social networks when a short url is shared (e.g. OpenGraph and Twitter card tags).
+type SocialMetadata struct {
+	Title       string `bson:"title"`
+	Description string `bson:"description"`
+	ImageURL    string `bson:"imageUrl"`
 }
 
 type URLRepository interface {
 	SaveRecord(record *URLRecord) (*URLRecord, error)
-	LongURL(shortID string) (*URLRecord, error)
-	ShortURL(longURL string) (*URLRecord, error)
+	// SaveRecords saves the records in bulk and returns those that were saved.
+	// Records whose shortId is already in use on their domain are skipped.
+	SaveRecords(records []*URLRecord) ([]*URLRecord, error)
+	LongURL(domain string, shortID string) (*URLRecord, error)
+	ShortURL(domain string, longURL string) (*URLRecord, error)
+	ShortURLForOwner(domain string, longURL string, owner string) (*URLRecord, error)
+	UpdateRecord(record *URLRecord) error
+	// ListRecords returns the records selected by the query, with their tags and metadata but without their targeting rules and destinations
+	ListRecords(query RecordQuery) ([]*URLRecord, error)
 }
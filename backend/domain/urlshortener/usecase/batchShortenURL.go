@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"github.com/w-k-s/short-url/log"
+	"sync"
+)
+
+const (
+	// DefaultBatchConcurrency is the number of items of a batch that are checked (and whose pages are fetched) at the same time
+	DefaultBatchConcurrency = 8
+	// DefaultBatchInsertSize is the number of records that are saved in one insert.
+	// Results are returned after each insert.
+	DefaultBatchInsertSize = 500
+	// DefaultMaxBatchItems is the maximum number of items in a batch
+	DefaultMaxBatchItems = 10000
+)
+
+// BatchShortenURLResult is the outcome of shortening the item at Index of a batch
+type BatchShortenURLResult struct {
+	Index    int
+	Response ShortenURLResponse
+	Err      domain.Err
+}
+
+// BatchShortenURLUseCase shortens many urls in one request, the same way ShortenURLUseCase shortens each url
+type BatchShortenURLUseCase struct {
+	shortener   *ShortenURLUseCase
+	concurrency int
+	insertSize  int
+	maxItems    int
+}
+
+// BatchShortenURLOption configures optional behaviour of the BatchShortenURLUseCase
+type BatchShortenURLOption func(*BatchShortenURLUseCase)
+
+// WithBatchConcurrency replaces the DefaultBatchConcurrency
+func WithBatchConcurrency(concurrency int) BatchShortenURLOption {
+	return func(b *BatchShortenURLUseCase) {
+		b.concurrency = concurrency
+	}
+}
+
+// WithBatchInsertSize replaces the DefaultBatchInsertSize
+func WithBatchInsertSize(insertSize int) BatchShortenURLOption {
+	return func(b *BatchShortenURLUseCase) {
+		b.insertSize = insertSize
+	}
+}
+
+// WithMaxBatchItems replaces the DefaultMaxBatchItems
+func WithMaxBatchItems(maxItems int) BatchShortenURLOption {
+	return func(b *BatchShortenURLUseCase) {
+		b.maxItems = maxItems
+	}
+}
+
+func NewBatchShortenURLUseCase(shortener *ShortenURLUseCase, options ...BatchShortenURLOption) *BatchShortenURLUseCase {
+	useCase := &BatchShortenURLUseCase{
+		shortener:   shortener,
+		concurrency: DefaultBatchConcurrency,
+		insertSize:  DefaultBatchInsertSize,
+		maxItems:    DefaultMaxBatchItems,
+	}
+	for _, option := range options {
+		option(useCase)
+	}
+	if useCase.concurrency < 1 {
+		useCase.concurrency = 1
+	}
+	if useCase.insertSize < 1 {
+		useCase.insertSize = 1
+	}
+	return useCase
+}
+
+// Execute shortens the items of the batch in chunks and emits the result of every item in input order.
+// It stops and returns the error if emit fails (e.g. because the client disconnected).
+func (b *BatchShortenURLUseCase) Execute(batchReq *BatchShortenURLRequest, emit func(BatchShortenURLResult) error) error {
+	index := 0
+	for {
+		chunk := make([]batchItem, 0, b.insertSize)
+		for len(chunk) < b.insertSize {
+			item, ok := batchReq.next()
+			if !ok {
+				break
+			}
+			if index+len(chunk) >= b.maxItems {
+				item = batchItem{err: NewError(
+					BatchShortenURLTooLarge,
+					fmt.Sprintf("A batch can have at most %d items. The remaining items were not shortened", b.maxItems),
+					map[string]string{"maxItems": fmt.Sprint(b.maxItems)},
+				)}
+				batchReq.done = true
+			}
+			chunk = append(chunk, item)
+		}
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		for i, result := range b.shortenChunk(chunk) {
+			result.Index = index + i
+			if err := emit(result); err != nil {
+				return err
+			}
+		}
+		index += len(chunk)
+	}
+}
+
+// pendingItem is an item that needs a new record
+type pendingItem struct {
+	request ShortenURLRequest
+	record  *u.URLRecord
+	// duplicateOf is the index of an earlier item in the chunk for the same long url, whose record is reused
+	duplicateOf int
+}
+
+func (b *BatchShortenURLUseCase) shortenChunk(chunk []batchItem) []BatchShortenURLResult {
+	results := make([]BatchShortenURLResult, len(chunk))
+	pending := make([]*pendingItem, len(chunk))
+
+	// Checking urls (and fetching their pages) is slow, so items are prepared concurrently
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < b.concurrency && w < len(chunk); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				shortReq, existingRecord, err := b.shortener.prepare(chunk[i].request)
+				switch {
+				case err != nil:
+					results[i].Err = err
+				case existingRecord != nil:
+					results[i].Response = b.shortener.buildShortenedURLResponse(shortReq, existingRecord)
+				default:
+					pending[i] = &pendingItem{request: shortReq, duplicateOf: -1}
+				}
+			}
+		}()
+	}
+	for i, item := range chunk {
+		if item.err != nil {
+			results[i].Err = item.err
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Items for the same long url share a record, as they would if they were shortened one after the other
+	firstForURL := map[string]int{}
+	records := make([]*u.URLRecord, 0, len(chunk))
+	for i, item := range pending {
+		if item == nil {
+			continue
+		}
+		if scope, ok := dedupeScopeOf(item.request); ok {
+			if first, seen := firstForURL[scope.key()]; seen {
+				item.duplicateOf = first
+				continue
+			}
+			firstForURL[scope.key()] = i
+		}
+
+		item.record = b.shortener.newRecord(item.request)
+		if !item.request.UserDidSpecifyShortId() {
+			item.record.ShortID = b.shortener.generator.Generate(VeryShort)
+		}
+		records = append(records, item.record)
+	}
+
+	saved, err := b.shortener.repo.SaveRecords(records)
+	if err != nil {
+		log.Printf("Failed to save batch of %d records: %s", len(records), err)
+	}
+	isSaved := make(map[*u.URLRecord]bool, len(saved))
+	for _, record := range saved {
+		isSaved[record] = true
+	}
+
+	for i, item := range pending {
+		if item == nil || item.duplicateOf >= 0 {
+			continue
+		}
+		switch {
+		case err != nil:
+			results[i].Err = NewError(
+				ShortenURLFailedToSave,
+				fmt.Sprintf("Failed to save '%s'", item.request.LongURL),
+				map[string]string{"error": err.Error()},
+			)
+		case isSaved[item.record]:
+			results[i].Response = b.shortener.buildShortenedURLResponse(item.request, item.record)
+		default:
+			// The shortId is in use. save reports the conflict, or tries longer shortIds if the shortId was generated
+			if !item.request.UserDidSpecifyShortId() {
+				item.record.ShortID = ""
+			}
+			results[i].Response, results[i].Err = b.shortener.save(item.request, item.record)
+		}
+	}
+
+	for i, item := range pending {
+		if item == nil || item.duplicateOf < 0 {
+			continue
+		}
+		if results[item.duplicateOf].Err == nil {
+			results[i].Response = b.shortener.buildShortenedURLResponse(item.request, pending[item.duplicateOf].record)
+		} else {
+			// The earlier item could not be saved (e.g. its shortId is in use), so this item gets a record of its own
+			results[i].Response, results[i].Err = b.shortener.save(item.request, b.shortener.newRecord(item.request))
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"github.com/w-k-s/short-url/domain"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// maxBatchLineLength is the maximum length of a line of an NDJSON batch
+const maxBatchLineLength = 1024 * 1024
+
+// BatchShortenURLRequest reads the items of a batch one at a time, so that large batches do not have to be held in memory.
+// The body is either a JSON array of ShortenURLRequests or newline-delimited JSON (NDJSON) with one request per line.
+type BatchShortenURLRequest struct {
+	ndjson bool
+	// read returns the next item, io.EOF after the last item, or a domain.Err if only this item could not be decoded.
+	// Other errors mean the rest of the batch can not be read.
+	read func() (ShortenURLRequest, error)
+	done bool
+}
+
+// batchItem is a request of a batch, or the reason it could not be read
+type batchItem struct {
+	request ShortenURLRequest
+	err     domain.Err
+}
+
+// IsNDJSONContentType returns true if the content type is one of the media types used for newline-delimited JSON
+func IsNDJSONContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return true
+	default:
+		return false
+	}
+}
+
+func NewBatchShortenURLRequest(req *http.Request) (*BatchShortenURLRequest, domain.Err) {
+	if IsNDJSONContentType(req.Header.Get("Content-Type")) {
+		scanner := bufio.NewScanner(req.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxBatchLineLength)
+
+		return &BatchShortenURLRequest{
+			ndjson: true,
+			read: decodeEachItem(func() (json.RawMessage, error) {
+				for scanner.Scan() {
+					if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+						return json.RawMessage(append([]byte(nil), line...)), nil
+					}
+				}
+				if err := scanner.Err(); err != nil {
+					return nil, err
+				}
+				return nil, io.EOF
+			}),
+		}, nil
+	}
+
+	// JSON is the default because clients do not always set a Content-Type
+	decoder := json.NewDecoder(req.Body)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		fields := map[string]string{}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+		return nil, NewError(
+			BatchShortenURLDecoding,
+			"The body must be a JSON array of urls to shorten, or newline-delimited JSON with Content-Type 'application/x-ndjson'",
+			fields,
+		)
+	}
+
+	return &BatchShortenURLRequest{
+		read: decodeEachItem(func() (json.RawMessage, error) {
+			if !decoder.More() {
+				return nil, io.EOF
+			}
+			var item json.RawMessage
+			err := decoder.Decode(&item)
+			return item, err
+		}),
+	}, nil
+}
+
+// StreamedBatchShortenURLRequest reads the items of a batch that have already been decoded (e.g. from a gRPC stream).
+// read returns io.EOF after the last item.
+func StreamedBatchShortenURLRequest(read func() (ShortenURLRequest, error)) *BatchShortenURLRequest {
+	return &BatchShortenURLRequest{read: read}
+}
+
+// decodeEachItem decodes the JSON of each item that is read
+func decodeEachItem(read func() (json.RawMessage, error)) func() (ShortenURLRequest, error) {
+	return func() (ShortenURLRequest, error) {
+		raw, err := read()
+		if err != nil {
+			return ShortenURLRequest{}, err
+		}
+
+		var shortenReq ShortenURLRequest
+		if err := json.Unmarshal(raw, &shortenReq); err != nil {
+			return ShortenURLRequest{}, NewError(
+				ShortenURLDecoding,
+				"Each item must be a JSON object with a `longUrl`",
+				map[string]string{"error": err.Error()},
+			)
+		}
+		return shortenReq, nil
+	}
+}
+
+// IsNDJSON returns true if the batch is newline-delimited JSON, in which case results are expected in the same format
+func (b *BatchShortenURLRequest) IsNDJSON() bool {
+	return b.ndjson
+}
+
+// next returns the next item of the batch, or false if there are no more items.
+// A body that can not be read any further is returned as an item with an error, after which there are no more items.
+func (b *BatchShortenURLRequest) next() (batchItem, bool) {
+	if b.done {
+		return batchItem{}, false
+	}
+
+	shortenReq, err := b.read()
+	if err == io.EOF {
+		b.done = true
+		return batchItem{}, false
+	}
+	if itemErr, ok := err.(domain.Err); ok {
+		return batchItem{err: itemErr}, true
+	}
+	if err != nil {
+		b.done = true
+		return batchItem{err: NewError(
+			BatchShortenURLDecoding,
+			"Failed to read the rest of the batch",
+			map[string]string{"error": err.Error()},
+		)}, true
+	}
+
+	shortenReq, validationErr := ValidateShortenURLRequest(shortenReq)
+	return batchItem{request: shortenReq, err: validationErr}, true
+}
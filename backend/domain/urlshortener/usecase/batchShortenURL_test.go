@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"github.com/w-k-s/short-url/log"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+//-- SequentialShortIDGenerator
+
+type SequentialShortIDGenerator struct {
+	count *int
+}
+
+func (m SequentialShortIDGenerator) Generate(d ShortIDLength) string {
+	*m.count++
+	return fmt.Sprintf("id%d", *m.count)
+}
+
+//-- BatchURLRepository
+
+// BatchURLRepository saves records unless their shortId is taken, and remembers the size of each batch
+type BatchURLRepository struct {
+	MockURLRepository
+	Taken      map[string]bool
+	BatchSizes *[]int
+}
+
+func (m BatchURLRepository) SaveRecord(record *u.URLRecord) (*u.URLRecord, error) {
+	if m.Taken[record.ShortID] {
+		return nil, errors.New("duplicate key")
+	}
+	return record, nil
+}
+
+func (m BatchURLRepository) SaveRecords(records []*u.URLRecord) ([]*u.URLRecord, error) {
+	*m.BatchSizes = append(*m.BatchSizes, len(records))
+
+	var saved []*u.URLRecord
+	for _, record := range records {
+		if !m.Taken[record.ShortID] {
+			saved = append(saved, record)
+		}
+	}
+	return saved, nil
+}
+
+type BatchShortenURLUseCaseTestSuite struct {
+	suite.Suite
+	repo      BatchURLRepository
+	generator SequentialShortIDGenerator
+	shortener *ShortenURLUseCase
+}
+
+func TestBatchShortenURLUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchShortenURLUseCaseTestSuite))
+}
+
+func (suite *BatchShortenURLUseCaseTestSuite) SetupTest() {
+	log.Init()
+
+	baseURL, _ := url.Parse(baseURLString)
+	suite.repo = BatchURLRepository{Taken: map[string]bool{}, BatchSizes: &[]int{}}
+	suite.generator = SequentialShortIDGenerator{count: new(int)}
+	suite.shortener = NewShortenURLUseCase(suite.repo, baseURL, suite.generator)
+}
+
+func (suite *BatchShortenURLUseCaseTestSuite) execute(useCase *BatchShortenURLUseCase, contentType string, body string) []BatchShortenURLResult {
+	req := httptest.NewRequest("POST", "/urlshortener/v1/urls:batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+
+	batchReq, err := NewBatchShortenURLRequest(req)
+	if err != nil {
+		panic(err)
+	}
+
+	var results []BatchShortenURLResult
+	useCase.Execute(batchReq, func(result BatchShortenURLResult) error {
+		results = append(results, result)
+		return nil
+	})
+	return results
+}
+
+func (suite *BatchShortenURLUseCaseTestSuite) TestGivenJSONArray_WhenShortening_ThenResultsInInputOrder() {
+
+	//Given
+	useCase := NewBatchShortenURLUseCase(suite.shortener)
+	body := `[
+		{"longUrl": "https://www.example.com/1"},
+		{"longUrl": "/relative"},
+		{"longUrl": "https://www.example.com/3", "ShortId": "custom"},
+		{"longUrl": "https://www.example.com/1"}
+	]`
+
+	//When
+	results := suite.execute(useCase, "application/json", body)
+
+	//Then
+	if assert.Len(suite.T(), results, 4) {
+		for i, result := range results {
+			assert.Equal(suite.T(), i, result.Index)
+		}
+		assert.Nil(suite.T(), results[0].Err)
+		assert.Equal(suite.T(), baseURLString+"id1", results[0].Response.ShortURL)
+		assert.Equal(suite.T(), ShortenURLValidation, int(results[1].Err.Code()))
+		assert.Equal(suite.T(), baseURLString+"custom", results[2].Response.ShortURL)
+		assert.Equal(suite.T(), results[0].Response.ShortURL, results[3].Response.ShortURL, "Expected items for the same long url to share a short url")
+	}
+	assert.Equal(suite.T(), []int{2}, *suite.repo.BatchSizes, "Expected valid items to be saved in one insert")
+}
+
+func (suite *BatchShortenURLUseCaseTestSuite) TestGivenNDJSON_WhenLineIsMalformed_ThenOtherLinesShortened() {
+
+	//Given
+	useCase := NewBatchShortenURLUseCase(suite.shortener)
+	body := "{\"longUrl\": \"https://www.example.com/1\"}\n{not json\n\n{\"longUrl\": \"https://www.example.com/2\"}\n"
+
+	//When
+	results := suite.execute(useCase, "application/x-ndjson", body)
+
+	//Then
+	if assert.Len(suite.T(), results, 3) {
+		assert.Nil(suite.T(), results[0].Err)
+		assert.Equal(suite.T(), ShortenURLDecoding, results[1].Err.Code())
+		assert.Nil(suite.T(), results[2].Err)
+		assert.Equal(suite.T(), 2, results[2].Index)
+	}
+}
+
+func (suite *BatchShortenURLUseCaseTestSuite) TestGivenInsertSize_WhenShortening_ThenRecordsSavedInChunks() {
+
+	//Given
+	useCase := NewBatchShortenURLUseCase(suite.shortener, WithBatchInsertSize(2), WithBatchConcurrency(3))
+	var lines []string
+	for i := 0; i < 5; i++ {
+		lines = append(lines, fmt.Sprintf(`{"longUrl": "https://www.example.com/%d"}`, i))
+	}
+
+	//When
+	results := suite.execute(useCase, "application/x-ndjson", strings.Join(lines, "\n"))
+
+	//Then
+	assert.Len(suite.T(), results, 5)
+	assert.Equal(suite.T(), []int{2, 2, 1}, *suite.repo.BatchSizes)
+	assert.Equal(suite.T(), 4, results[4].Index)
+}
+
+func (suite *BatchShortenURLUseCaseTestSuite) TestGivenTooManyItems_WhenShortening_ThenRemainingItemsRejected() {
+
+	//Given
+	useCase := NewBatchShortenURLUseCase(suite.shortener, WithMaxBatchItems(2))
+	body := `[{"longUrl": "https://www.example.com/1"}, {"longUrl": "https://www.example.com/2"}, {"longUrl": "https://www.example.com/3"}, {"longUrl": "https://www.example.com/4"}]`
+
+	//When
+	results := suite.execute(useCase, "application/json", body)
+
+	//Then
+	if assert.Len(suite.T(), results, 3) {
+		assert.Nil(suite.T(), results[1].Err)
+		assert.Equal(suite.T(), BatchShortenURLTooLarge, int(results[2].Err.Code()))
+	}
+}
+
+func (suite *BatchShortenURLUseCaseTestSuite) TestGivenShortIDsInUse_WhenShortening_ThenGeneratedShortIDsRetried() {
+
+	//Given
+	suite.repo.Taken["id1"] = true
+	suite.repo.Taken["custom"] = true
+	useCase := NewBatchShortenURLUseCase(suite.shortener)
+	body := `[{"longUrl": "https://www.example.com/1"}, {"longUrl": "https://www.example.com/2", "ShortId": "custom"}]`
+
+	//When
+	results := suite.execute(useCase, "application/json", body)
+
+	//Then
+	if assert.Len(suite.T(), results, 2) {
+		assert.Nil(suite.T(), results[0].Err)
+		assert.Equal(suite.T(), baseURLString+"id2", results[0].Response.ShortURL)
+		assert.Equal(suite.T(), ShortenURLShortIDInUse, int(results[1].Err.Code()))
+	}
+}
+
+func (suite *BatchShortenURLUseCaseTestSuite) TestGivenBodyIsNotAnArray_WhenCreatingRequest_ThenReturnError() {
+
+	//Given
+	req := httptest.NewRequest("POST", "/urlshortener/v1/urls:batch", strings.NewReader(`{"longUrl": "https://www.example.com"}`))
+
+	//When
+	_, err := NewBatchShortenURLRequest(req)
+
+	//Then
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), BatchShortenURLDecoding, int(err.Code()))
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+)
+
+type ClickStatsUseCase struct {
+	repo      u.URLRepository
+	domains   Domains
+	analytics u.AnalyticsRepository
+}
+
+func NewClickStatsUseCase(repo u.URLRepository, domains Domains, analytics u.AnalyticsRepository) *ClickStatsUseCase {
+	return &ClickStatsUseCase{
+		repo,
+		domains,
+		analytics,
+	}
+}
+
+func (s *ClickStatsUseCase) Execute(statsReq ClickStatsRequest) (ClickStatsResponse, domain.Err) {
+
+	record, err := findRecord(s.repo, s.domains, statsReq.Domain, statsReq.ShortID)
+	if err != nil {
+		return ClickStatsResponse{}, NewError(
+			ClickStatsNotFound,
+			fmt.Sprintf("No URL for %s", statsReq.ShortID),
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	counts, err := s.analytics.ClickCounts(record.Domain, record.ShortID)
+	if err != nil {
+		return ClickStatsResponse{}, NewError(
+			ClickStatsFailedToLoad,
+			fmt.Sprintf("Failed to load clicks on %s", record.ShortID),
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	response := ClickStatsResponse{
+		ShortID: record.ShortID,
+	}
+	for _, clicks := range counts {
+		response.Clicks += clicks
+	}
+
+	// Variants that have since been removed from the link are only included in the total
+	for _, destination := range record.Destinations {
+		response.Variants = append(response.Variants, VariantClicks{
+			Variant: destination.Variant,
+			LongURL: destination.LongURL,
+			Weight:  destination.Weight,
+			Clicks:  counts[destination.Variant],
+		})
+	}
+
+	return response, nil
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"github.com/w-k-s/short-url/domain"
+	"net/http"
+)
+
+type ClickStatsRequest struct {
+	ShortID string
+	// Domain is the host of the domain the shortId is on. The default domain is used if it is empty.
+	Domain string
+}
+
+// NewClickStatsRequest reads the domain of the shortId from the query string e.g. `?domain=brand.ly`
+func NewClickStatsRequest(shortID string, req *http.Request) (ClickStatsRequest, domain.Err) {
+	if len(shortID) == 0 {
+		return ClickStatsRequest{}, NewError(
+			ClickStatsValidation,
+			"`shortId` is required",
+			nil,
+		)
+	}
+	return ClickStatsRequest{ShortID: shortID, Domain: req.URL.Query().Get("domain")}, nil
+}
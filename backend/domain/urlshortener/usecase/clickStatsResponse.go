@@ -0,0 +1,15 @@
+package usecase
+
+type ClickStatsResponse struct {
+	ShortID string `json:"shortId" xml:"shortId"`
+	Clicks  int64  `json:"clicks" xml:"clicks"`
+	// Variants lists the clicks per destination, if the link is split across destinations
+	Variants []VariantClicks `json:"variants,omitempty" xml:"variants>item,omitempty"`
+}
+
+type VariantClicks struct {
+	Variant string `json:"variant" xml:"variant"`
+	LongURL string `json:"longUrl" xml:"longUrl"`
+	Weight  int    `json:"weight" xml:"weight"`
+	Clicks  int64  `json:"clicks" xml:"clicks"`
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"testing"
+	"time"
+)
+
+type ClickStatsUseCaseTestSuite struct {
+	suite.Suite
+	urlRepo   *MockURLRepository
+	analytics *MockAnalyticsRepository
+	record    *u.URLRecord
+	useCase   *ClickStatsUseCase
+}
+
+func (suite *ClickStatsUseCaseTestSuite) SetupTest() {
+	suite.record = &u.URLRecord{
+		LongURL:    savedLongURL,
+		ShortID:    savedShortID,
+		CreateTime: time.Now(),
+	}
+
+	suite.urlRepo = &MockURLRepository{}
+	suite.analytics = &MockAnalyticsRepository{}
+	suite.useCase = NewClickStatsUseCase(suite.urlRepo, NewDomains(nil, nil, nil), suite.analytics)
+}
+
+func TestClickStatsUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ClickStatsUseCaseTestSuite))
+}
+
+func (suite *ClickStatsUseCaseTestSuite) TestGivenSplitDestinations_WhenRetrievingStats_ThenClicksPerVariantReturned() {
+
+	//Given
+	suite.record.Destinations = []u.Destination{
+		{Variant: "A", LongURL: "https://example.com/a", Weight: 70},
+		{Variant: "B", LongURL: "https://example.com/b", Weight: 30},
+	}
+	suite.urlRepo.LongURLRecordResult = suite.record
+	suite.analytics.ClickCountsValue = u.ClickCounts{"": 5, "A": 7, "removed": 2}
+
+	//When
+	response, err := suite.useCase.Execute(ClickStatsRequest{ShortID: savedShortID})
+
+	//Then
+	assert.Nil(suite.T(), err, "ClickStats: Expected no error, got %v", err)
+	assert.Equal(suite.T(), int64(14), response.Clicks)
+	assert.Equal(suite.T(), []VariantClicks{
+		{Variant: "A", LongURL: "https://example.com/a", Weight: 70, Clicks: 7},
+		{Variant: "B", LongURL: "https://example.com/b", Weight: 30, Clicks: 0},
+	}, response.Variants)
+}
+
+func (suite *ClickStatsUseCaseTestSuite) TestGivenRecordDoesNotExist_WhenRetrievingStats_ThenReturnError() {
+
+	//Given
+	suite.urlRepo.ReturnError = true
+	suite.urlRepo.LongURLRecordError = errors.New("Not Found")
+
+	//When
+	_, err := suite.useCase.Execute(ClickStatsRequest{ShortID: "nil"})
+
+	//Then
+	expectation := ClickStatsNotFound
+	assert.NotNil(suite.T(), err, "ClickStats: Expected Error, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "ClickStats wrong error code. Expected '%d'. Got: %d", expectation, int(err.Code()))
+}
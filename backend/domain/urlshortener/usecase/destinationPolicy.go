@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DestinationPolicy restricts the long urls that can be shortened.
+// It protects visitors from script urls (e.g. `javascript:`) and protects the
+// network from links pointing at private or internal hosts.
+type DestinationPolicy struct {
+	// AllowedSchemes lists the schemes (e.g. http, https) a long url may use.
+	AllowedSchemes []string
+	// DeniedHosts lists hostnames that may not be linked to. Subdomains of a denied host are also denied.
+	DeniedHosts []string
+	// DeniedNetworks lists the networks that a long url may not point at.
+	DeniedNetworks []*net.IPNet
+	// MaxURLLength is the maximum length of a long url. Zero means no limit.
+	MaxURLLength int
+	// LookupIP resolves hostnames so that names pointing at denied networks are rejected.
+	// If nil, only literal IP addresses are checked against DeniedNetworks.
+	LookupIP func(host string) ([]net.IP, error)
+}
+
+// DefaultDeniedNetworks are the loopback, private, link-local and otherwise non-public networks.
+var DefaultDeniedNetworks = []string{
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+}
+
+func DefaultDestinationPolicy() DestinationPolicy {
+	networks, _ := ParseNetworks(DefaultDeniedNetworks)
+	return DestinationPolicy{
+		AllowedSchemes: []string{"http", "https"},
+		DeniedHosts:    []string{"localhost"},
+		DeniedNetworks: networks,
+		MaxURLLength:   2048,
+	}
+}
+
+// ParseNetworks parses CIDR notations (e.g. 10.0.0.0/8).
+// A plain IP address is treated as a network containing only that address.
+func ParseNetworks(cidrs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("'%s' is not a valid IP address", cidr)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			cidr = fmt.Sprintf("%s/%d", cidr, bits)
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// Check returns an error if the long url violates the policy.
+func (p DestinationPolicy) Check(longURL *url.URL) domain.Err {
+	rawURL := longURL.String()
+
+	if p.MaxURLLength > 0 && len(rawURL) > p.MaxURLLength {
+		return NewError(
+			ShortenURLTooLong,
+			fmt.Sprintf("The url is %d characters long. The maximum length is %d", len(rawURL), p.MaxURLLength),
+			map[string]string{"maxLength": strconv.Itoa(p.MaxURLLength)},
+		)
+	}
+
+	if !p.isSchemeAllowed(longURL.Scheme) {
+		return NewError(
+			ShortenURLSchemeNotAllowed,
+			fmt.Sprintf("The scheme '%s' is not allowed. Allowed schemes: %s", longURL.Scheme, strings.Join(p.AllowedSchemes, ", ")),
+			map[string]string{"scheme": longURL.Scheme},
+		)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(longURL.Hostname()), ".")
+	if len(host) == 0 {
+		return NewError(
+			ShortenURLHostNotAllowed,
+			fmt.Sprintf("'%s' does not have a host", rawURL),
+			nil,
+		)
+	}
+
+	if deniedHost, denied := p.deniedHost(host); denied {
+		return NewError(
+			ShortenURLHostNotAllowed,
+			fmt.Sprintf("Links to '%s' are not allowed", host),
+			map[string]string{"host": host, "deniedHost": deniedHost},
+		)
+	}
+
+	ips, err := p.resolve(host)
+	if err != nil {
+		return NewError(
+			ShortenURLHostNotAllowed,
+			fmt.Sprintf("Failed to resolve host '%s'", host),
+			map[string]string{"host": host, "error": err.Error()},
+		)
+	}
+
+	for _, ip := range ips {
+		if network, denied := p.deniedNetwork(ip); denied {
+			return NewError(
+				ShortenURLHostNotAllowed,
+				fmt.Sprintf("Links to '%s' are not allowed", host),
+				map[string]string{"host": host, "deniedNetwork": network.String()},
+			)
+		}
+	}
+
+	return nil
+}
+
+func (p DestinationPolicy) isSchemeAllowed(scheme string) bool {
+	for _, allowed := range p.AllowedSchemes {
+		if strings.EqualFold(allowed, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p DestinationPolicy) deniedHost(host string) (string, bool) {
+	for _, denied := range p.DeniedHosts {
+		denied = strings.TrimSuffix(strings.ToLower(denied), ".")
+		if host == denied || strings.HasSuffix(host, "."+denied) {
+			return denied, true
+		}
+	}
+	return "", false
+}
+
+func (p DestinationPolicy) deniedNetwork(ip net.IP) (*net.IPNet, bool) {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, network := range p.DeniedNetworks {
+		if network.Contains(ip) {
+			return network, true
+		}
+	}
+	return nil, false
+}
+
+func (p DestinationPolicy) resolve(host string) ([]net.IP, error) {
+	if ip := parseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	if p.LookupIP == nil || len(p.DeniedNetworks) == 0 {
+		return nil, nil
+	}
+	return p.LookupIP(host)
+}
+
+// parseIP parses IP addresses, including the shorthand, octal and hexadecimal
+// IPv4 forms (e.g. 127.1, 0177.0.0.1, 0x7f000001) that browsers accept.
+func parseIP(host string) net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip
+	}
+
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return nil
+	}
+
+	values := make([]uint64, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseUint(part, 0, 32)
+		if err != nil {
+			return nil
+		}
+		values[i] = value
+	}
+
+	// The last part fills the remaining bytes of the address
+	last := len(values) - 1
+	var address uint64
+	for i := 0; i < last; i++ {
+		if values[i] > 0xff {
+			return nil
+		}
+		address |= values[i] << uint(8*(3-i))
+	}
+	if values[last] >= 1<<uint(8*(4-last)) {
+		return nil
+	}
+	address |= values[last]
+
+	return net.IPv4(byte(address>>24), byte(address>>16), byte(address>>8), byte(address))
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDestinationPolicy(t *testing.T) {
+
+	policy := DefaultDestinationPolicy()
+
+	testCases := []struct {
+		longURL  string
+		expected int
+	}{
+		{"http://www.example.com", 0},
+		{"https://www.example.com/path?q=1", 0},
+		{"javascript:alert(1)", ShortenURLSchemeNotAllowed},
+		{"data:text/html,<script>alert(1)</script>", ShortenURLSchemeNotAllowed},
+		{"file:///etc/passwd", ShortenURLSchemeNotAllowed},
+		{"http://localhost:8080/admin", ShortenURLHostNotAllowed},
+		{"http://api.localhost/admin", ShortenURLHostNotAllowed},
+		{"http://127.0.0.1/", ShortenURLHostNotAllowed},
+		{"http://10.1.2.3/", ShortenURLHostNotAllowed},
+		{"http://192.168.0.1/", ShortenURLHostNotAllowed},
+		{"http://169.254.169.254/latest/meta-data", ShortenURLHostNotAllowed},
+		{"http://[::1]/", ShortenURLHostNotAllowed},
+		{"http://[::ffff:127.0.0.1]/", ShortenURLHostNotAllowed},
+		{"http://0x7f000001/", ShortenURLHostNotAllowed},
+		{"http://0177.0.0.1/", ShortenURLHostNotAllowed},
+		{"http://127.1/", ShortenURLHostNotAllowed},
+		{"http://8.8.8.8/", 0},
+		{"http://www.example.com/" + strings.Repeat("a", 2048), ShortenURLTooLong},
+	}
+
+	for _, testCase := range testCases {
+		longURL, _ := url.Parse(testCase.longURL)
+		err := policy.Check(longURL)
+		if testCase.expected == 0 {
+			assert.Nil(t, err, "Expected '%s' to be allowed. Got: %v", testCase.longURL, err)
+			continue
+		}
+		if assert.NotNil(t, err, "Expected '%s' to be denied", testCase.longURL) {
+			assert.Equal(t, testCase.expected, int(err.Code()), "Wrong error code for '%s'", testCase.longURL)
+		}
+	}
+}
+
+func TestDestinationPolicyResolvesHosts(t *testing.T) {
+
+	policy := DefaultDestinationPolicy()
+	policy.LookupIP = func(host string) ([]net.IP, error) {
+		switch host {
+		case "internal.example.com":
+			return []net.IP{net.ParseIP("10.0.0.5")}, nil
+		case "public.example.com":
+			return []net.IP{net.ParseIP("93.184.216.34")}, nil
+		default:
+			return nil, errors.New("no such host")
+		}
+	}
+
+	internalURL, _ := url.Parse("http://internal.example.com")
+	err := policy.Check(internalURL)
+	assert.NotNil(t, err, "Expected host resolving to a private network to be denied")
+
+	publicURL, _ := url.Parse("http://public.example.com")
+	err = policy.Check(publicURL)
+	assert.Nil(t, err, "Expected host resolving to a public network to be allowed. Got: %v", err)
+
+	unknownURL, _ := url.Parse("http://unknown.example.com")
+	err = policy.Check(unknownURL)
+	assert.NotNil(t, err, "Expected unresolvable host to be denied")
+}
+
+func TestParseNetworks(t *testing.T) {
+
+	networks, err := ParseNetworks([]string{"10.0.0.0/8", "203.0.113.7", "2001:db8::1"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(networks))
+	assert.True(t, networks[1].Contains(net.ParseIP("203.0.113.7")))
+	assert.False(t, networks[1].Contains(net.ParseIP("203.0.113.8")))
+
+	_, err = ParseNetworks([]string{"not-a-network"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"fmt"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"net/url"
+	"strings"
+)
+
+// Domains is the registry of the domains that short urls are served on, e.g. the host of BASE_URL and any branded domains.
+// ShortIds are unique per domain, so the same shortId can point at different long urls on different domains.
+type Domains struct {
+	defaultDomain *url.URL
+	// byHost maps the hosts that are served (in lowercase) to the base url of their domain
+	byHost map[string]*url.URL
+}
+
+// NewDomains registers the default domain, the branded domains, and the alias hosts of the default domain
+// (e.g. `www.small.ml`), whose short urls are those of the default domain.
+func NewDomains(defaultDomain *url.URL, brandedDomains []*url.URL, aliases []string) Domains {
+	domains := Domains{
+		defaultDomain: defaultDomain,
+		byHost:        map[string]*url.URL{},
+	}
+	if defaultDomain != nil {
+		domains.byHost[strings.ToLower(defaultDomain.Host)] = defaultDomain
+		for _, alias := range aliases {
+			domains.byHost[strings.ToLower(alias)] = defaultDomain
+		}
+	}
+	for _, branded := range brandedDomains {
+		domains.byHost[strings.ToLower(branded.Host)] = branded
+	}
+	return domains
+}
+
+// Default returns the base url of the domain that is used when no domain is requested
+func (d Domains) Default() *url.URL {
+	return d.defaultDomain
+}
+
+// Lookup returns the base url of the domain served on the host
+func (d Domains) Lookup(host string) (*url.URL, bool) {
+	baseURL, ok := d.byHost[strings.ToLower(host)]
+	return baseURL, ok
+}
+
+// DomainOf returns the domain that records are saved under for short urls on the host.
+// Hosts that are not registered are served as the default domain.
+func (d Domains) DomainOf(host string) string {
+	if baseURL, ok := d.Lookup(host); ok {
+		return domainKey(baseURL)
+	}
+	if d.defaultDomain != nil {
+		return domainKey(d.defaultDomain)
+	}
+	return strings.ToLower(host)
+}
+
+// RequestedDomain returns the domain that records are saved under for the requested host, or the default domain if no host is requested.
+// It returns false if the host is not served.
+func (d Domains) RequestedDomain(host string) (string, bool) {
+	if len(host) == 0 {
+		if d.defaultDomain == nil {
+			return "", true
+		}
+		return domainKey(d.defaultDomain), true
+	}
+	if !d.serves(host) {
+		return "", false
+	}
+	return d.DomainOf(host), true
+}
+
+// serves returns true if the host is registered. If no domains are registered, every host is served as its own domain.
+func (d Domains) serves(host string) bool {
+	_, ok := d.Lookup(host)
+	return ok || len(d.byHost) == 0
+}
+
+// BaseURL returns the base url of the domain that a record was saved under
+func (d Domains) BaseURL(domain string) *url.URL {
+	if baseURL, ok := d.Lookup(domain); ok {
+		return baseURL
+	}
+	if d.defaultDomain != nil && len(domain) == 0 {
+		return d.defaultDomain
+	}
+	return &url.URL{Scheme: "https", Host: domain}
+}
+
+// relativeURL returns the short url with the path of its domain's base url removed,
+// e.g. `https://corp.example/go/abc` is `https://corp.example/abc` if the base url is `https://corp.example/go/`.
+// It returns false if the short url is not under the path of the base url.
+func (d Domains) relativeURL(shortURL *url.URL) (*url.URL, bool) {
+	baseURL, ok := d.Lookup(shortURL.Host)
+	if !ok {
+		baseURL = d.defaultDomain
+	}
+	if baseURL == nil {
+		return shortURL, true
+	}
+
+	prefix := strings.TrimSuffix(baseURL.Path, "/")
+	if len(prefix) == 0 {
+		return shortURL, true
+	}
+	if shortURL.Path != prefix && !strings.HasPrefix(shortURL.Path, prefix+"/") {
+		return shortURL, false
+	}
+
+	relative := *shortURL
+	relative.Path = strings.TrimPrefix(shortURL.Path, prefix)
+	relative.RawPath = ""
+	return &relative, true
+}
+
+// domainKey is the domain that records on the base url are saved under
+func domainKey(baseURL *url.URL) string {
+	return strings.ToLower(baseURL.Host)
+}
+
+// findRecord finds the record of the shortId on the requested domain, or on the default domain if no domain is requested
+func findRecord(repo u.URLRepository, domains Domains, requestedDomain string, shortID string) (*u.URLRecord, error) {
+	domain, ok := domains.RequestedDomain(requestedDomain)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not one of the domains that urls are shortened on", requestedDomain)
+	}
+	return repo.LongURL(domain, shortID)
+}
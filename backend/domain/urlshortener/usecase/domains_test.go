@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+func TestDomainsResolveHosts(t *testing.T) {
+	baseURL, _ := url.Parse("https://small.ml")
+	brandedURL, _ := url.Parse("https://Go.Brand.com")
+	domains := NewDomains(baseURL, []*url.URL{brandedURL}, []string{"www.small.ml"})
+
+	assert.Equal(t, "go.brand.com", domains.DomainOf("go.BRAND.com"))
+	assert.Equal(t, "small.ml", domains.DomainOf("www.small.ml"), "Expected alias to be served as the default domain")
+	assert.Equal(t, "small.ml", domains.DomainOf("localhost:8080"), "Expected unknown host to be served as the default domain")
+
+	domain, ok := domains.RequestedDomain("")
+	assert.True(t, ok)
+	assert.Equal(t, "small.ml", domain)
+
+	domain, ok = domains.RequestedDomain("go.brand.com")
+	assert.True(t, ok)
+	assert.Equal(t, "go.brand.com", domain)
+
+	_, ok = domains.RequestedDomain("go.unknown.com")
+	assert.False(t, ok)
+
+	assert.Equal(t, brandedURL, domains.BaseURL("go.brand.com"))
+	assert.Equal(t, baseURL, domains.BaseURL("small.ml"))
+}
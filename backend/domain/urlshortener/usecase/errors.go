@@ -7,19 +7,34 @@ import (
 
 const (
 	//Shortening URL
-	ShortenURLDecoding        domain.Code = 10200
-	ShortenURLValidation                  = 10300
-	ShortenURLFailedToSave                = 10400
-	ShortenURLTrackVisitError             = 10401
-	ShortenURLShortIDInUse                = 10402
-	ShortenURLUndocumented                = 10999
+	ShortenURLDecoding         domain.Code = 10200
+	ShortenURLValidation                   = 10300
+	ShortenURLSchemeNotAllowed             = 10301
+	ShortenURLHostNotAllowed               = 10302
+	ShortenURLTooLong                      = 10303
+	ShortenURLSelfLink                     = 10304
+	ShortenURLRedirectLoop                 = 10305
+	ShortenURLMalicious                    = 10306
+	ShortenURLDomainNotAllowed             = 10307
+	ShortenURLFailedToSave                 = 10400
+	ShortenURLTrackVisitError              = 10401
+	ShortenURLShortIDInUse                 = 10402
+	// ShortenURLIdempotencyKeyReused is returned if an idempotency key is sent with a different request than the one it was first sent with
+	ShortenURLIdempotencyKeyReused = 10403
+	// ShortenURLIdempotencyKeyInProgress is returned if the request that an idempotency key was first sent with has not completed yet
+	ShortenURLIdempotencyKeyInProgress = 10404
+	ShortenURLUndocumented             = 10999
 
 	//Retrieving Long Url
-	RetrieveFullURLDecoding     = 11200
-	RetrieveFullURLValidation   = 11300
-	RetrieveFullURLNotFound     = 11400
-	RetrieveFullURLParsing      = 11500
-	RetrieveFullURLUndocumented = 11999
+	RetrieveFullURLDecoding   = 11200
+	RetrieveFullURLValidation = 11300
+	// RetrieveFullURLDomainNotAllowed is returned if the short url is not on one of the domains that urls are shortened on
+	RetrieveFullURLDomainNotAllowed = 11301
+	RetrieveFullURLNotFound         = 11400
+	RetrieveFullURLRedirectLoop     = 11401
+	RetrieveFullURLDisabled         = 11402
+	RetrieveFullURLParsing          = 11500
+	RetrieveFullURLUndocumented     = 11999
 
 	//Redirectign to Long Url
 	RedirectionFullURLNotFound = 12100
@@ -27,6 +42,34 @@ const (
 
 	//URLResponse
 	URLResponseEncoding = 13000
+
+	//Updating URL
+	UpdateURLDecoding     = 14200
+	UpdateURLValidation   = 14300
+	UpdateURLNotFound     = 14400
+	UpdateURLFailedToSave = 14500
+
+	//Authorization
+	Unauthorized = 15100
+
+	//Click Statistics
+	ClickStatsValidation   = 16300
+	ClickStatsNotFound     = 16400
+	ClickStatsFailedToLoad = 16500
+
+	//QR Codes
+	QRCodeValidation = 17300
+	QRCodeNotFound   = 17400
+	QRCodeDisabled   = 17401
+	QRCodeEncoding   = 17500
+
+	//Batch Shortening
+	BatchShortenURLDecoding = 18200
+	BatchShortenURLTooLarge = 18300
+
+	//Listing URLs
+	ListURLsValidation   = 19300
+	ListURLsFailedToLoad = 19500
 )
 
 func domainString(e domain.Code) string {
@@ -36,10 +79,28 @@ func domainString(e domain.Code) string {
 		return "shortenUrl.decoding"
 	case ShortenURLValidation:
 		return "shortenUrl.validation"
+	case ShortenURLSchemeNotAllowed:
+		return "shortenUrl.schemeNotAllowed"
+	case ShortenURLHostNotAllowed:
+		return "shortenUrl.hostNotAllowed"
+	case ShortenURLTooLong:
+		return "shortenUrl.tooLong"
+	case ShortenURLSelfLink:
+		return "shortenUrl.selfLink"
+	case ShortenURLRedirectLoop:
+		return "shortenUrl.redirectLoop"
+	case ShortenURLMalicious:
+		return "shortenUrl.malicious"
+	case ShortenURLDomainNotAllowed:
+		return "shortenUrl.domainNotAllowed"
 	case ShortenURLFailedToSave:
 		return "shortenUrl.failedToSave"
 	case ShortenURLShortIDInUse:
 		return "shortenUrl.shortIdInUse"
+	case ShortenURLIdempotencyKeyReused:
+		return "shortenUrl.idempotencyKeyReused"
+	case ShortenURLIdempotencyKeyInProgress:
+		return "shortenUrl.idempotencyKeyInProgress"
 	case ShortenURLUndocumented:
 		return "shortenUrl.undocumented"
 
@@ -48,8 +109,14 @@ func domainString(e domain.Code) string {
 		return "retrieveFullURL.decoding"
 	case RetrieveFullURLValidation:
 		return "retrieveFullURL.validation"
+	case RetrieveFullURLDomainNotAllowed:
+		return "retrieveFullURL.domainNotAllowed"
 	case RetrieveFullURLNotFound:
 		return "retrieveFullURL.urlNotFound"
+	case RetrieveFullURLRedirectLoop:
+		return "retrieveFullURL.redirectLoop"
+	case RetrieveFullURLDisabled:
+		return "retrieveFullURL.disabled"
 	case RetrieveFullURLParsing:
 		return "retrieveFullURL.urlParsing"
 	case RetrieveFullURLUndocumented:
@@ -65,6 +132,50 @@ func domainString(e domain.Code) string {
 	case URLResponseEncoding:
 		return "urlResponse.encoding"
 
+	//Updating URL
+	case UpdateURLDecoding:
+		return "updateUrl.decoding"
+	case UpdateURLValidation:
+		return "updateUrl.validation"
+	case UpdateURLNotFound:
+		return "updateUrl.urlNotFound"
+	case UpdateURLFailedToSave:
+		return "updateUrl.failedToSave"
+
+	//Authorization
+	case Unauthorized:
+		return "authorization.unauthorized"
+
+	//Click Statistics
+	case ClickStatsValidation:
+		return "clickStats.validation"
+	case ClickStatsNotFound:
+		return "clickStats.urlNotFound"
+	case ClickStatsFailedToLoad:
+		return "clickStats.failedToLoad"
+
+	//QR Codes
+	case QRCodeValidation:
+		return "qrCode.validation"
+	case QRCodeNotFound:
+		return "qrCode.urlNotFound"
+	case QRCodeDisabled:
+		return "qrCode.disabled"
+	case QRCodeEncoding:
+		return "qrCode.encoding"
+
+	//Batch Shortening
+	case BatchShortenURLDecoding:
+		return "batchShortenUrl.decoding"
+	case BatchShortenURLTooLarge:
+		return "batchShortenUrl.tooLarge"
+
+	//Listing URLs
+	case ListURLsValidation:
+		return "listUrls.validation"
+	case ListURLsFailedToLoad:
+		return "listUrls.failedToLoad"
+
 	default:
 		panic(fmt.Sprintf("Unknown Domain (%d)", e))
 	}
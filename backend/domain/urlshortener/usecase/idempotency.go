@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"github.com/w-k-s/short-url/log"
+	"time"
+)
+
+// idempotency replays the response to the first request that was sent with an idempotency key to the requests that are sent with it again,
+// e.g. retries of a request whose response was lost.
+// Only successful responses are saved; the key is released if the request fails so that it can be retried.
+// Keys are scoped by the owner of the request, so requests of different owners never share a key.
+type idempotency struct {
+	repo u.IdempotencyRepository
+	ttl  time.Duration
+}
+
+func (i *idempotency) execute(shortReq ShortenURLRequest, shorten func(ShortenURLRequest) (ShortenURLResponse, domain.Err)) (ShortenURLResponse, domain.Err) {
+	owner, key := shortReq.Owner, shortReq.IdempotencyKey
+	fingerprint, err := fingerprintOf(shortReq)
+	if err != nil {
+		return ShortenURLResponse{}, NewError(
+			ShortenURLFailedToSave,
+			fmt.Sprintf("Failed to fingerprint the request with idempotency key '%s'", key),
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	now := time.Now()
+	reserved, err := i.repo.ReserveKey(&u.IdempotencyRecord{
+		Owner:       owner,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreateTime:  now,
+		ExpireTime:  now.Add(i.ttl),
+	})
+	if err != nil {
+		return ShortenURLResponse{}, NewError(
+			ShortenURLFailedToSave,
+			fmt.Sprintf("Failed to save idempotency key '%s'", key),
+			map[string]string{"error": err.Error()},
+		)
+	}
+	if !reserved {
+		return i.replay(owner, key, fingerprint)
+	}
+
+	response, shortenErr := shorten(shortReq)
+	if shortenErr != nil {
+		i.release(owner, key)
+		return response, shortenErr
+	}
+
+	encoded, err := json.Marshal(response)
+	if err == nil {
+		err = i.repo.SaveResponse(owner, key, encoded)
+	}
+	if err != nil {
+		// The url was shortened, so the response is returned; a retry shortens it again rather than waiting for the key to expire
+		log.Printf("Failed to save the response for idempotency key '%s': %s", key, err)
+		i.release(owner, key)
+	}
+	return response, nil
+}
+
+// replay returns the saved response of the key if it was saved for the same request
+func (i *idempotency) replay(owner string, key string, fingerprint string) (ShortenURLResponse, domain.Err) {
+	record, err := i.repo.FindKey(owner, key)
+	if err != nil {
+		return ShortenURLResponse{}, NewError(
+			ShortenURLFailedToSave,
+			fmt.Sprintf("Failed to load idempotency key '%s'", key),
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	if record != nil && record.Fingerprint != fingerprint {
+		return ShortenURLResponse{}, NewError(
+			ShortenURLIdempotencyKeyReused,
+			fmt.Sprintf("Idempotency key '%s' was sent with a different request. Use a new key for each request", key),
+			nil,
+		)
+	}
+
+	// The key is released if the first request fails, so a missing record is a request that is about to be retried
+	if record == nil || record.Response == nil {
+		return ShortenURLResponse{}, NewError(
+			ShortenURLIdempotencyKeyInProgress,
+			fmt.Sprintf("The request with idempotency key '%s' is in progress. Retry later", key),
+			nil,
+		)
+	}
+
+	var response ShortenURLResponse
+	if err := json.Unmarshal(record.Response, &response); err != nil {
+		return ShortenURLResponse{}, NewError(
+			ShortenURLUndocumented,
+			fmt.Sprintf("Failed to decode the saved response for idempotency key '%s'", key),
+			map[string]string{"error": err.Error()},
+		)
+	}
+	response.Replayed = true
+	return response, nil
+}
+
+func (i *idempotency) release(owner string, key string) {
+	if err := i.repo.ReleaseKey(owner, key); err != nil {
+		log.Printf("Failed to release idempotency key '%s': %s", key, err)
+	}
+}
+
+// fingerprintOf hashes the fields of the request, so that the same request in any format has the same fingerprint
+func fingerprintOf(shortReq ShortenURLRequest) (string, error) {
+	encoded, err := json.Marshal(shortReq)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(encoded)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"github.com/w-k-s/short-url/log"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+//-- MockIdempotencyRepository
+
+type MockIdempotencyRepository struct {
+	Records map[string]*u.IdempotencyRecord
+}
+
+func (m *MockIdempotencyRepository) ReserveKey(record *u.IdempotencyRecord) (bool, error) {
+	if existing, ok := m.Records[record.Owner+"/"+record.Key]; ok && existing.ExpireTime.After(record.CreateTime) {
+		return false, nil
+	}
+	saved := *record
+	m.Records[record.Owner+"/"+record.Key] = &saved
+	return true, nil
+}
+
+func (m *MockIdempotencyRepository) FindKey(owner string, key string) (*u.IdempotencyRecord, error) {
+	return m.Records[owner+"/"+key], nil
+}
+
+func (m *MockIdempotencyRepository) SaveResponse(owner string, key string, response []byte) error {
+	m.Records[owner+"/"+key].Response = response
+	return nil
+}
+
+func (m *MockIdempotencyRepository) ReleaseKey(owner string, key string) error {
+	delete(m.Records, owner+"/"+key)
+	return nil
+}
+
+//-----
+
+type IdempotencyTestSuite struct {
+	suite.Suite
+	generator       *MockShortIDGenerator
+	idempotencyRepo *MockIdempotencyRepository
+	useCase         *ShortenURLUseCase
+}
+
+func TestIdempotencyTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyTestSuite))
+}
+
+func (suite *IdempotencyTestSuite) SetupTest() {
+	log.Init()
+
+	baseURL, _ := url.Parse(baseURLString)
+	suite.generator = &MockShortIDGenerator{ShortID: "alpha"}
+	suite.idempotencyRepo = &MockIdempotencyRepository{Records: map[string]*u.IdempotencyRecord{}}
+	suite.useCase = NewShortenURLUseCase(
+		SavingURLRepository{},
+		baseURL,
+		suite.generator,
+		WithIdempotency(suite.idempotencyRepo, time.Hour),
+	)
+}
+
+// failingUseCase shares the idempotency keys of the suite, but fails to save records
+func (suite *IdempotencyTestSuite) failingUseCase() *ShortenURLUseCase {
+	baseURL, _ := url.Parse(baseURLString)
+	return NewShortenURLUseCase(
+		MockURLRepository{ReturnError: true, SaveURLRecordError: errors.New("duplicate key value violates unique constraint")},
+		baseURL,
+		suite.generator,
+		WithIdempotency(suite.idempotencyRepo, time.Hour),
+	)
+}
+
+func (suite *IdempotencyTestSuite) request(longURL string, key string) ShortenURLRequest {
+	request, err := ValidateShortenURLRequest(ShortenURLRequest{
+		LongURL:        longURL,
+		ShortID:        "custom",
+		IdempotencyKey: key,
+	})
+	assert.Nil(suite.T(), err)
+	return request
+}
+
+func (suite *IdempotencyTestSuite) TestGivenShortenedRequest_WhenRequestIsRetriedWithSameKey_ThenResponseIsReplayed() {
+
+	//Given
+	first, err := suite.useCase.Execute(suite.request(savedLongURL, "key"))
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), first.Replayed)
+
+	//When
+	retry, err := suite.failingUseCase().Execute(suite.request(savedLongURL, "key"))
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), retry.Replayed)
+	assert.Equal(suite.T(), baseURLString+"custom", retry.ShortURL)
+	assert.Equal(suite.T(), first.LongURL, retry.LongURL)
+}
+
+func (suite *IdempotencyTestSuite) TestGivenShortenedRequest_WhenDifferentRequestIsSentWithSameKey_ThenRequestIsRejected() {
+
+	//Given
+	_, err := suite.useCase.Execute(suite.request(savedLongURL, "key"))
+	assert.Nil(suite.T(), err)
+
+	//When
+	_, err = suite.useCase.Execute(suite.request("http://www.example.com/other", "key"))
+
+	//Then
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), ShortenURLIdempotencyKeyReused, int(err.Code()))
+}
+
+func (suite *IdempotencyTestSuite) TestGivenShortenedRequest_WhenOtherOwnerSendsSameKey_ThenRequestIsShortened() {
+
+	//Given
+	request := suite.request(savedLongURL, "key")
+	request.Owner = "alice"
+	_, err := suite.useCase.Execute(request)
+	assert.Nil(suite.T(), err)
+
+	//When
+	other := suite.request("http://www.example.com/other", "key")
+	other.Owner = "bob"
+	response, err := suite.useCase.Execute(other)
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), response.Replayed)
+	assert.Equal(suite.T(), "http://www.example.com/other", response.LongURL)
+	assert.Len(suite.T(), suite.idempotencyRepo.Records, 2)
+}
+
+func (suite *IdempotencyTestSuite) TestGivenRequestInProgress_WhenRequestIsRetriedWithSameKey_ThenRequestIsRejected() {
+
+	//Given
+	request := suite.request(savedLongURL, "key")
+	fingerprint, _ := fingerprintOf(request)
+	suite.idempotencyRepo.ReserveKey(&u.IdempotencyRecord{
+		Key:         "key",
+		Fingerprint: fingerprint,
+		CreateTime:  time.Now(),
+		ExpireTime:  time.Now().Add(time.Hour),
+	})
+
+	//When
+	_, err := suite.useCase.Execute(request)
+
+	//Then
+	assert.NotNil(suite.T(), err)
+	assert.Equal(suite.T(), ShortenURLIdempotencyKeyInProgress, int(err.Code()))
+}
+
+func (suite *IdempotencyTestSuite) TestGivenFailedRequest_WhenRequestIsRetriedWithSameKey_ThenRequestIsShortened() {
+
+	//Given
+	_, err := suite.failingUseCase().Execute(suite.request(savedLongURL, "key"))
+	assert.NotNil(suite.T(), err)
+	assert.Empty(suite.T(), suite.idempotencyRepo.Records, "Expected the key to be released when the request fails")
+
+	//When
+	response, err := suite.useCase.Execute(suite.request(savedLongURL, "key"))
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), response.Replayed)
+	assert.Equal(suite.T(), baseURLString+"custom", response.ShortURL)
+}
+
+func (suite *IdempotencyTestSuite) TestGivenNoKey_WhenShorteningURL_ThenNoResponseIsSaved() {
+
+	//When
+	_, err := suite.useCase.Execute(suite.request(savedLongURL, ""))
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), suite.idempotencyRepo.Records)
+}
+
+func TestGivenKeyTooLong_WhenValidatingRequest_ThenRequestIsRejected(t *testing.T) {
+	key := strings.Repeat("k", maxIdempotencyKeyLength+1)
+
+	_, err := ValidateShortenURLRequest(ShortenURLRequest{LongURL: savedLongURL, IdempotencyKey: key})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, ShortenURLValidation, int(err.Code()))
+}
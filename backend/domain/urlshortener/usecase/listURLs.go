@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+)
+
+type ListURLsUseCase struct {
+	repo    u.URLRepository
+	domains Domains
+}
+
+func NewListURLsUseCase(repo u.URLRepository, domains Domains) *ListURLsUseCase {
+	return &ListURLsUseCase{
+		repo,
+		domains,
+	}
+}
+
+func (s *ListURLsUseCase) Execute(listReq ListURLsRequest) (ListURLsResponse, domain.Err) {
+
+	query := listReq.recordQuery()
+	if len(listReq.Domain) > 0 {
+		domain, ok := s.domains.RequestedDomain(listReq.Domain)
+		if !ok {
+			return ListURLsResponse{}, NewError(
+				ListURLsValidation,
+				fmt.Sprintf("'%s' is not one of the domains that urls are shortened on", listReq.Domain),
+				nil,
+			)
+		}
+		query.Domain = domain
+	}
+
+	records, err := s.repo.ListRecords(query)
+	if err != nil {
+		return ListURLsResponse{}, NewError(
+			ListURLsFailedToLoad,
+			"Failed to load urls",
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	response := ListURLsResponse{URLs: []URLSummary{}}
+	if len(records) > listReq.Limit {
+		records = records[:listReq.Limit]
+		response.NextCursor = encodeListCursor(listReq.Sort, u.PositionOf(records[len(records)-1]))
+	}
+
+	for _, record := range records {
+		response.URLs = append(response.URLs, URLSummary{
+			ShortID:        record.ShortID,
+			Domain:         record.Domain,
+			ShortURL:       shortURLFor(s.domains.BaseURL(record.Domain), record.ShortID).String(),
+			LongURL:        record.LongURL,
+			Owner:          record.Owner,
+			CreateTime:     record.CreateTime,
+			Disabled:       record.Disabled,
+			DisabledReason: record.DisabledReason,
+			Tags:           record.Tags,
+			Metadata:       record.Metadata,
+		})
+	}
+
+	return response, nil
+}
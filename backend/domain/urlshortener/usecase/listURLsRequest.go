@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultListURLsLimit = 50
+	MaxListURLsLimit     = 500
+	// DefaultListURLsSort lists the newest urls first
+	DefaultListURLsSort = "-" + string(u.SortByCreateTime)
+)
+
+type ListURLsRequest struct {
+	// Domain is the host of the domain to list urls on. Urls on every domain are listed if it is empty.
+	Domain        string
+	Owner         string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Host          string
+	Search        string
+	// Tags are the tags that every listed url must have
+	Tags []string
+	// Sort is the field that urls are ordered by, prefixed with '-' for descending order e.g. `-createTime`
+	Sort   string
+	Limit  int
+	After  *u.RecordPosition
+	sortBy u.SortField
+	desc   bool
+}
+
+// listCursor is the position of the last url of a page. It is sent to clients base64 encoded so that they treat it as opaque.
+type listCursor struct {
+	Sort       string    `json:"s"`
+	CreateTime time.Time `json:"t"`
+	LongURL    string    `json:"l,omitempty"`
+	ShortID    string    `json:"i"`
+	Domain     string    `json:"d,omitempty"`
+}
+
+// NewListURLsRequest reads the filters from the query string e.g.
+// `?owner=alice&host=example.com&q=sale&tag=summer&tag=email&createdAfter=2020-01-01T00:00:00Z&sort=-createTime&limit=100&cursor=...`
+func NewListURLsRequest(req *http.Request) (ListURLsRequest, domain.Err) {
+	query := req.URL.Query()
+
+	listReq := ListURLsRequest{
+		Domain: strings.ToLower(strings.TrimSpace(query.Get("domain"))),
+		Owner:  query.Get("owner"),
+		Host:   strings.ToLower(strings.TrimSpace(query.Get("host"))),
+		Search: strings.TrimSpace(query.Get("q")),
+		Sort:   DefaultListURLsSort,
+		Limit:  DefaultListURLsLimit,
+	}
+
+	var err domain.Err
+	if tags := query["tag"]; len(tags) > 0 {
+		if listReq.Tags, err = normalizeTags(ListURLsValidation, tags); err != nil {
+			return ListURLsRequest{}, err
+		}
+	}
+	if listReq.CreatedAfter, err = timeParameter(query.Get("createdAfter"), "createdAfter"); err != nil {
+		return ListURLsRequest{}, err
+	}
+	if listReq.CreatedBefore, err = timeParameter(query.Get("createdBefore"), "createdBefore"); err != nil {
+		return ListURLsRequest{}, err
+	}
+	if !listReq.CreatedAfter.IsZero() && !listReq.CreatedBefore.IsZero() && !listReq.CreatedAfter.Before(listReq.CreatedBefore) {
+		return ListURLsRequest{}, NewError(
+			ListURLsValidation,
+			"`createdAfter` must be before `createdBefore`",
+			nil,
+		)
+	}
+
+	if sort := query.Get("sort"); len(sort) > 0 {
+		listReq.Sort = sort
+	}
+	listReq.desc = strings.HasPrefix(listReq.Sort, "-")
+	listReq.sortBy = u.SortField(strings.TrimPrefix(listReq.Sort, "-"))
+	switch listReq.sortBy {
+	case u.SortByCreateTime, u.SortByLongURL, u.SortByShortID:
+	default:
+		return ListURLsRequest{}, NewError(
+			ListURLsValidation,
+			fmt.Sprintf("'%s' is not a valid sort. Expected one of '%s', '%s' or '%s', optionally prefixed with '-' for descending order", listReq.Sort, u.SortByCreateTime, u.SortByLongURL, u.SortByShortID),
+			nil,
+		)
+	}
+
+	if limit := query.Get("limit"); len(limit) > 0 {
+		number, parseErr := strconv.Atoi(limit)
+		if parseErr != nil || number < 1 || number > MaxListURLsLimit {
+			return ListURLsRequest{}, NewError(
+				ListURLsValidation,
+				fmt.Sprintf("`limit` must be a number between 1 and %d", MaxListURLsLimit),
+				nil,
+			)
+		}
+		listReq.Limit = number
+	}
+
+	if cursor := query.Get("cursor"); len(cursor) > 0 {
+		position, err := decodeListCursor(cursor, listReq.Sort)
+		if err != nil {
+			return ListURLsRequest{}, err
+		}
+		listReq.After = &position
+	}
+
+	return listReq, nil
+}
+
+func (l ListURLsRequest) recordQuery() u.RecordQuery {
+	return u.RecordQuery{
+		Owner:         l.Owner,
+		CreatedAfter:  l.CreatedAfter,
+		CreatedBefore: l.CreatedBefore,
+		Host:          l.Host,
+		Search:        l.Search,
+		Tags:          l.Tags,
+		SortBy:        l.sortBy,
+		Descending:    l.desc,
+		After:         l.After,
+		// One more record than the page is loaded to find out if there is a next page
+		Limit: l.Limit + 1,
+	}
+}
+
+func encodeListCursor(sort string, position u.RecordPosition) string {
+	cursor := listCursor{
+		Sort:       sort,
+		CreateTime: position.CreateTime,
+		ShortID:    position.ShortID,
+		Domain:     position.Domain,
+	}
+	if strings.TrimPrefix(sort, "-") == string(u.SortByLongURL) {
+		cursor.LongURL = position.LongURL
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeListCursor(value string, sort string) (u.RecordPosition, domain.Err) {
+	var cursor listCursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err == nil {
+		err = json.Unmarshal(data, &cursor)
+	}
+	if err != nil || len(cursor.ShortID) == 0 {
+		return u.RecordPosition{}, NewError(
+			ListURLsValidation,
+			"`cursor` is not valid. Use the `nextCursor` of the previous page",
+			nil,
+		)
+	}
+	if cursor.Sort != sort {
+		return u.RecordPosition{}, NewError(
+			ListURLsValidation,
+			fmt.Sprintf("`cursor` is for urls sorted by '%s'. The sort can not change between pages", cursor.Sort),
+			nil,
+		)
+	}
+	return u.RecordPosition{
+		CreateTime: cursor.CreateTime,
+		LongURL:    cursor.LongURL,
+		ShortID:    cursor.ShortID,
+		Domain:     cursor.Domain,
+	}, nil
+}
+
+// timeParameter parses an RFC 3339 time e.g. `2020-01-31T09:00:00Z`
+func timeParameter(value string, name string) (time.Time, domain.Err) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, NewError(
+			ListURLsValidation,
+			fmt.Sprintf("'%s' is not a valid time for `%s`. Expected an RFC 3339 time e.g. '2020-01-31T09:00:00Z'", value, name),
+			nil,
+		)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"github.com/w-k-s/short-url/codec"
+	"time"
+)
+
+type ListURLsResponse struct {
+	URLs []URLSummary `json:"urls" xml:"urls>item"`
+	// NextCursor is passed as `cursor` to load the next page. It is omitted on the last page.
+	NextCursor string `json:"nextCursor,omitempty" xml:"nextCursor,omitempty"`
+}
+
+type URLSummary struct {
+	ShortID        string          `json:"shortId" xml:"shortId"`
+	Domain         string          `json:"domain" xml:"domain"`
+	ShortURL       string          `json:"shortUrl" xml:"shortUrl"`
+	LongURL        string          `json:"longUrl" xml:"longUrl"`
+	Owner          string          `json:"owner,omitempty" xml:"owner,omitempty"`
+	CreateTime     time.Time       `json:"createTime" xml:"createTime"`
+	Disabled       bool            `json:"disabled" xml:"disabled"`
+	DisabledReason string          `json:"disabledReason,omitempty" xml:"disabledReason,omitempty"`
+	Tags           []string        `json:"tags,omitempty" xml:"tags>item,omitempty"`
+	Metadata       codec.StringMap `json:"metadata,omitempty" xml:"metadata,omitempty"`
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type ListURLsUseCaseTestSuite struct {
+	suite.Suite
+	urlRepo *MockURLRepository
+	query   *u.RecordQuery
+	useCase *ListURLsUseCase
+}
+
+func (suite *ListURLsUseCaseTestSuite) SetupTest() {
+	baseURL, _ := url.Parse(baseURLString)
+
+	suite.query = &u.RecordQuery{}
+	suite.urlRepo = &MockURLRepository{ListRecordsQuery: suite.query}
+	suite.useCase = NewListURLsUseCase(suite.urlRepo, NewDomains(baseURL, nil, nil))
+}
+
+func TestListURLsUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ListURLsUseCaseTestSuite))
+}
+
+func (suite *ListURLsUseCaseTestSuite) listRequest(query string) ListURLsRequest {
+	listReq, err := NewListURLsRequest(httptest.NewRequest("GET", "/urlshortener/v1/urls?"+query, nil))
+	if err != nil {
+		panic(err)
+	}
+	return listReq
+}
+
+func (suite *ListURLsUseCaseTestSuite) TestGivenMoreRecordsThanLimit_WhenListing_ThenCursorLoadsNextPage() {
+
+	//Given
+	createTime := time.Date(2020, 1, 31, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		suite.urlRepo.ListRecordsResult = append(suite.urlRepo.ListRecordsResult, &u.URLRecord{
+			LongURL:    fmt.Sprintf("https://www.example.com/%d", i),
+			ShortID:    fmt.Sprintf("id%d", i),
+			CreateTime: createTime,
+		})
+	}
+
+	//When
+	response, err := suite.useCase.Execute(suite.listRequest("limit=2"))
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 3, suite.query.Limit, "Expected one more record than the limit to be loaded")
+	assert.Equal(suite.T(), u.SortByCreateTime, suite.query.SortBy)
+	assert.True(suite.T(), suite.query.Descending)
+	if assert.Len(suite.T(), response.URLs, 2) {
+		assert.Equal(suite.T(), baseURLString+"id1", response.URLs[1].ShortURL)
+	}
+
+	//When
+	nextReq := suite.listRequest("limit=2&cursor=" + response.NextCursor)
+
+	//Then
+	assert.Equal(suite.T(), &u.RecordPosition{CreateTime: createTime, ShortID: "id1"}, nextReq.After)
+}
+
+func (suite *ListURLsUseCaseTestSuite) TestGivenLastPage_WhenListing_ThenNoCursor() {
+
+	//Given
+	suite.urlRepo.ListRecordsResult = []*u.URLRecord{{LongURL: savedLongURL, ShortID: savedShortID}}
+
+	//When
+	response, err := suite.useCase.Execute(suite.listRequest("owner=alice&host=WWW.Example.com&q=%20sale%20&tag=Summer&tag=email&sort=-longUrl&createdAfter=2020-01-01T00:00:00Z"))
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), response.NextCursor)
+	assert.Equal(suite.T(), u.RecordQuery{
+		Owner:        "alice",
+		CreatedAfter: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Host:         "www.example.com",
+		Search:       "sale",
+		Tags:         []string{"email", "summer"},
+		SortBy:       u.SortByLongURL,
+		Descending:   true,
+		Limit:        DefaultListURLsLimit + 1,
+	}, *suite.query)
+}
+
+func TestListURLsRequestValidation(t *testing.T) {
+	invalid := []string{
+		"sort=owner",
+		"tag=not%20a%20tag",
+		"limit=0",
+		fmt.Sprintf("limit=%d", MaxListURLsLimit+1),
+		"createdAfter=yesterday",
+		"createdAfter=2020-02-01T00:00:00Z&createdBefore=2020-01-01T00:00:00Z",
+		"cursor=not-a-cursor",
+		"sort=shortId&cursor=" + encodeListCursor("-createTime", u.RecordPosition{ShortID: "abc"}),
+	}
+	for _, query := range invalid {
+		_, err := NewListURLsRequest(httptest.NewRequest("GET", "/urlshortener/v1/urls?"+query, nil))
+		if assert.NotNil(t, err, "Expected '%s' to be rejected", query) {
+			assert.Equal(t, ListURLsValidation, int(err.Code()))
+		}
+	}
+}
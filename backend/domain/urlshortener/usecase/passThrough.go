@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"net/url"
+	"strings"
+)
+
+// QueryMerge determines how the query string of a short url is forwarded to the long url
+type QueryMerge string
+
+const (
+	// QueryMergeNone drops the query string of the short url
+	QueryMergeNone QueryMerge = ""
+	// QueryMergeKeep adds query parameters that the long url does not already have
+	QueryMergeKeep QueryMerge = "keep"
+	// QueryMergeReplace adds query parameters, replacing the long url's values for the same parameters
+	QueryMergeReplace QueryMerge = "replace"
+	// QueryMergeAppend adds query parameters, keeping the long url's values for the same parameters
+	QueryMergeAppend QueryMerge = "append"
+)
+
+func validateQueryMerge(code domain.Code, mode QueryMerge) domain.Err {
+	switch mode {
+	case QueryMergeNone, QueryMergeKeep, QueryMergeReplace, QueryMergeAppend:
+		return nil
+	default:
+		return NewError(
+			code,
+			fmt.Sprintf("'%s' is not a valid query merge mode. Expected one of '%s', '%s' or '%s'", mode, QueryMergeKeep, QueryMergeReplace, QueryMergeAppend),
+			nil,
+		)
+	}
+}
+
+// splitShortURLPath splits the path of a short url into the shortId and the path suffix
+// e.g. "/abc/extra/path" is split into "abc" and "extra/path"
+func splitShortURLPath(path string) (shortID string, suffix string) {
+	path = strings.TrimPrefix(path, "/")
+	if index := strings.Index(path, "/"); index >= 0 {
+		return path[:index], path[index+1:]
+	}
+	return path, ""
+}
+
+// destinationURL builds the url that a visitor of the short url is sent to,
+// forwarding the query string and path suffix of the short url to the long url if the record allows it.
+func destinationURL(record *u.URLRecord, rawLongURL string, shortURL *url.URL) (*url.URL, error) {
+	longURL, err := url.Parse(rawLongURL)
+	if err != nil {
+		return nil, err
+	}
+
+	_, suffix := splitShortURLPath(shortURL.Path)
+	if len(suffix) > 0 {
+		if !record.Wildcard {
+			return nil, fmt.Errorf("'%s' does not accept a path suffix", record.ShortID)
+		}
+		joinedPath := strings.TrimSuffix(longURL.EscapedPath(), "/") + "/" + (&url.URL{Path: suffix}).EscapedPath()
+		if longURL.Path, err = url.PathUnescape(joinedPath); err != nil {
+			return nil, err
+		}
+		longURL.RawPath = joinedPath
+	}
+
+	incoming := shortURL.Query()
+	if record.QueryMerge != string(QueryMergeNone) && len(incoming) > 0 {
+		longURL.RawQuery = mergeQuery(longURL.Query(), incoming, QueryMerge(record.QueryMerge)).Encode()
+	}
+
+	return longURL, nil
+}
+
+func mergeQuery(destination url.Values, incoming url.Values, mode QueryMerge) url.Values {
+	for key, values := range incoming {
+		_, conflict := destination[key]
+		switch {
+		case !conflict || mode == QueryMergeReplace:
+			destination[key] = values
+		case mode == QueryMergeAppend:
+			destination[key] = append(destination[key], values...)
+		}
+	}
+	return destination
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"github.com/stretchr/testify/assert"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"net/url"
+	"testing"
+)
+
+func TestSplitShortURLPath(t *testing.T) {
+
+	shortID, suffix := splitShortURLPath("/abc")
+	assert.Equal(t, "abc", shortID)
+	assert.Equal(t, "", suffix)
+
+	shortID, suffix = splitShortURLPath("/abc/extra/path/")
+	assert.Equal(t, "abc", shortID)
+	assert.Equal(t, "extra/path/", suffix)
+}
+
+func TestDestinationURL(t *testing.T) {
+
+	testCases := []struct {
+		name       string
+		longURL    string
+		queryMerge QueryMerge
+		wildcard   bool
+		shortURL   string
+		expected   string
+	}{
+		{"query dropped by default", "https://example.com/a?x=1", QueryMergeNone, false, "https://small.ml/abc?ref=mail", "https://example.com/a?x=1"},
+		{"query added", "https://example.com/a?x=1", QueryMergeKeep, false, "https://small.ml/abc?ref=mail", "https://example.com/a?ref=mail&x=1"},
+		{"conflict keeps destination", "https://example.com/a?x=1", QueryMergeKeep, false, "https://small.ml/abc?x=2", "https://example.com/a?x=1"},
+		{"conflict replaces destination", "https://example.com/a?x=1", QueryMergeReplace, false, "https://small.ml/abc?x=2", "https://example.com/a?x=2"},
+		{"conflict appends", "https://example.com/a?x=1", QueryMergeAppend, false, "https://small.ml/abc?x=2", "https://example.com/a?x=1&x=2"},
+		{"no incoming query", "https://example.com/a?b=2&a=1", QueryMergeKeep, false, "https://small.ml/abc", "https://example.com/a?b=2&a=1"},
+		{"suffix appended", "https://example.com/docs", QueryMergeNone, true, "https://small.ml/abc/extra/path", "https://example.com/docs/extra/path"},
+		{"suffix appended after trailing slash", "https://example.com/docs/", QueryMergeNone, true, "https://small.ml/abc/extra", "https://example.com/docs/extra"},
+		{"suffix appended to root", "https://example.com", QueryMergeKeep, true, "https://small.ml/abc/extra?ref=mail", "https://example.com/extra?ref=mail"},
+		{"suffix escaped", "https://example.com/docs", QueryMergeNone, true, "https://small.ml/abc/a%20b", "https://example.com/docs/a%20b"},
+	}
+
+	for _, testCase := range testCases {
+		record := &u.URLRecord{
+			ShortID:    "abc",
+			LongURL:    testCase.longURL,
+			QueryMerge: string(testCase.queryMerge),
+			Wildcard:   testCase.wildcard,
+		}
+		shortURL, _ := url.Parse(testCase.shortURL)
+
+		destination, err := destinationURL(record, record.LongURL, shortURL)
+
+		if assert.Nil(t, err, testCase.name) {
+			assert.Equal(t, testCase.expected, destination.String(), testCase.name)
+		}
+	}
+}
+
+func TestDestinationURLRejectsSuffixForNonWildcardLinks(t *testing.T) {
+
+	record := &u.URLRecord{ShortID: "abc", LongURL: "https://example.com"}
+	shortURL, _ := url.Parse("https://small.ml/abc/extra")
+
+	_, err := destinationURL(record, record.LongURL, shortURL)
+
+	assert.NotNil(t, err)
+}
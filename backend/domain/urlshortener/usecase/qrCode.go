@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"net/url"
+)
+
+// QRCodeTrackingParameter is added to short urls encoded in qr codes that are tracked (e.g. `?src=qr`)
+const QRCodeTrackingParameter = "src"
+
+// QRCodeRenderer draws a qr code encoding the text
+type QRCodeRenderer interface {
+	Render(text string, options QRCodeOptions) ([]byte, error)
+}
+
+type QRCodeUseCase struct {
+	repo     u.URLRepository
+	domains  Domains
+	renderer QRCodeRenderer
+}
+
+func NewQRCodeUseCase(repo u.URLRepository, domains Domains, renderer QRCodeRenderer) *QRCodeUseCase {
+	return &QRCodeUseCase{
+		repo,
+		domains,
+		renderer,
+	}
+}
+
+func (s *QRCodeUseCase) Execute(qrReq QRCodeRequest) (QRCodeResponse, domain.Err) {
+
+	record, err := findRecord(s.repo, s.domains, qrReq.Domain, qrReq.ShortID)
+	if err != nil {
+		return QRCodeResponse{}, NewError(
+			QRCodeNotFound,
+			fmt.Sprintf("No URL for %s", qrReq.ShortID),
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	if record.Disabled {
+		return QRCodeResponse{}, NewError(
+			QRCodeDisabled,
+			fmt.Sprintf("The link '%s' has been disabled", record.ShortID),
+			map[string]string{"reason": record.DisabledReason},
+		)
+	}
+
+	shortURL := shortURLFor(s.domains.BaseURL(record.Domain), record.ShortID)
+	if qrReq.Track {
+		shortURL.RawQuery = url.Values{QRCodeTrackingParameter: []string{"qr"}}.Encode()
+	}
+
+	image, err := s.renderer.Render(shortURL.String(), qrReq.Options)
+	if err != nil {
+		return QRCodeResponse{}, NewError(
+			QRCodeEncoding,
+			fmt.Sprintf("Failed to encode %s as a qr code", shortURL),
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	contentType := "image/png"
+	if qrReq.Options.Format == QRCodeSVG {
+		contentType = "image/svg+xml"
+	}
+
+	return QRCodeResponse{
+		ShortURL:    shortURL.String(),
+		ContentType: contentType,
+		Image:       image,
+		CreateTime:  record.CreateTime,
+	}, nil
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	"image/color"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// QRCodeFormat is the image format of a qr code
+type QRCodeFormat string
+
+const (
+	QRCodePNG QRCodeFormat = "png"
+	QRCodeSVG QRCodeFormat = "svg"
+)
+
+// QRCodeLevel is the error correction level of a qr code.
+// Higher levels can be read when more of the code is damaged or covered, but produce denser codes.
+type QRCodeLevel string
+
+const (
+	QRCodeLevelLow      QRCodeLevel = "L"
+	QRCodeLevelMedium   QRCodeLevel = "M"
+	QRCodeLevelQuartile QRCodeLevel = "Q"
+	QRCodeLevelHigh     QRCodeLevel = "H"
+)
+
+const (
+	DefaultQRCodeSize   = 256
+	MinQRCodeSize       = 64
+	MaxQRCodeSize       = 2048
+	DefaultQRCodeMargin = 4
+	MaxQRCodeMargin     = 16
+)
+
+// QRCodeOptions determine how a qr code is rendered
+type QRCodeOptions struct {
+	Format QRCodeFormat
+	// Size is the width and height of the image in pixels
+	Size  int
+	Level QRCodeLevel
+	// Margin is the width of the quiet zone around the code, in modules (the squares of the code)
+	Margin     int
+	Foreground color.RGBA
+	Background color.RGBA
+}
+
+type QRCodeRequest struct {
+	ShortID string
+	// Domain is the host of the domain the shortId is on. The default domain is used if it is empty.
+	Domain string
+	// Track adds the QRCodeTrackingParameter to the encoded short url so that scans can be told apart from clicks
+	Track   bool
+	Options QRCodeOptions
+}
+
+// NewQRCodeRequest reads the options from the query string e.g.
+// `?format=svg&size=512&level=H&margin=2&fg=1a1a1a&bg=ffffff&track=true&domain=brand.ly`
+func NewQRCodeRequest(shortID string, req *http.Request) (QRCodeRequest, domain.Err) {
+	query := req.URL.Query()
+
+	qrReq := QRCodeRequest{
+		ShortID: shortID,
+		Domain:  query.Get("domain"),
+		Options: QRCodeOptions{
+			Format:     QRCodePNG,
+			Size:       DefaultQRCodeSize,
+			Level:      QRCodeLevelMedium,
+			Margin:     DefaultQRCodeMargin,
+			Foreground: color.RGBA{A: 0xff},
+			Background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		},
+	}
+
+	if len(shortID) == 0 {
+		return QRCodeRequest{}, NewError(
+			QRCodeValidation,
+			"`shortId` is required",
+			nil,
+		)
+	}
+
+	if format := query.Get("format"); len(format) > 0 {
+		qrReq.Options.Format = QRCodeFormat(strings.ToLower(format))
+		if qrReq.Options.Format != QRCodePNG && qrReq.Options.Format != QRCodeSVG {
+			return QRCodeRequest{}, NewError(
+				QRCodeValidation,
+				fmt.Sprintf("'%s' is not a valid format. Expected '%s' or '%s'", format, QRCodePNG, QRCodeSVG),
+				nil,
+			)
+		}
+	}
+
+	if level := query.Get("level"); len(level) > 0 {
+		qrReq.Options.Level = QRCodeLevel(strings.ToUpper(level))
+		switch qrReq.Options.Level {
+		case QRCodeLevelLow, QRCodeLevelMedium, QRCodeLevelQuartile, QRCodeLevelHigh:
+		default:
+			return QRCodeRequest{}, NewError(
+				QRCodeValidation,
+				fmt.Sprintf("'%s' is not a valid error correction level. Expected one of 'L', 'M', 'Q' or 'H'", level),
+				nil,
+			)
+		}
+	}
+
+	var err domain.Err
+	if qrReq.Options.Size, err = intParameter(query.Get("size"), "size", DefaultQRCodeSize, MinQRCodeSize, MaxQRCodeSize); err != nil {
+		return QRCodeRequest{}, err
+	}
+	if qrReq.Options.Margin, err = intParameter(query.Get("margin"), "margin", DefaultQRCodeMargin, 0, MaxQRCodeMargin); err != nil {
+		return QRCodeRequest{}, err
+	}
+	if qrReq.Options.Foreground, err = colorParameter(query.Get("fg"), "fg", qrReq.Options.Foreground); err != nil {
+		return QRCodeRequest{}, err
+	}
+	if qrReq.Options.Background, err = colorParameter(query.Get("bg"), "bg", qrReq.Options.Background); err != nil {
+		return QRCodeRequest{}, err
+	}
+
+	if track := query.Get("track"); len(track) > 0 {
+		var parseErr error
+		if qrReq.Track, parseErr = strconv.ParseBool(track); parseErr != nil {
+			return QRCodeRequest{}, NewError(
+				QRCodeValidation,
+				fmt.Sprintf("'%s' is not a valid value for `track`. Expected 'true' or 'false'", track),
+				nil,
+			)
+		}
+	}
+
+	return qrReq, nil
+}
+
+func intParameter(value string, name string, defaultValue int, min int, max int) (int, domain.Err) {
+	if len(value) == 0 {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < min || number > max {
+		return 0, NewError(
+			QRCodeValidation,
+			fmt.Sprintf("`%s` must be a number between %d and %d", name, min, max),
+			nil,
+		)
+	}
+	return number, nil
+}
+
+// colorParameter parses a hex color e.g. `1a1a1a` or `#1a1a1a`
+func colorParameter(value string, name string, defaultValue color.RGBA) (color.RGBA, domain.Err) {
+	if len(value) == 0 {
+		return defaultValue, nil
+	}
+	hex := strings.TrimPrefix(value, "#")
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return color.RGBA{}, NewError(
+			QRCodeValidation,
+			fmt.Sprintf("'%s' is not a valid color for `%s`. Expected a hex color e.g. '1a1a1a'", value, name),
+			nil,
+		)
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil
+}
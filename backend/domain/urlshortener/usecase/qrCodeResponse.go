@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"time"
+)
+
+type QRCodeResponse struct {
+	// ShortURL is the url encoded in the qr code
+	ShortURL    string
+	ContentType string
+	Image       []byte
+	// CreateTime is when the link was created. The qr code of a link never changes.
+	CreateTime time.Time
+}
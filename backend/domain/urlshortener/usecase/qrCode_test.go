@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"image/color"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+//-- MockQRCodeRenderer
+
+type MockQRCodeRenderer struct {
+	Text    string
+	Options QRCodeOptions
+}
+
+func (m *MockQRCodeRenderer) Render(text string, options QRCodeOptions) ([]byte, error) {
+	m.Text = text
+	m.Options = options
+	return []byte("qr"), nil
+}
+
+//-----
+
+type QRCodeUseCaseTestSuite struct {
+	suite.Suite
+	urlRepo  *MockURLRepository
+	renderer *MockQRCodeRenderer
+	record   *u.URLRecord
+	useCase  *QRCodeUseCase
+}
+
+func (suite *QRCodeUseCaseTestSuite) SetupTest() {
+	suite.record = &u.URLRecord{
+		LongURL:    savedLongURL,
+		ShortID:    savedShortID,
+		CreateTime: time.Now(),
+	}
+
+	baseURL, _ := url.Parse(baseURLString)
+	suite.urlRepo = &MockURLRepository{}
+	suite.renderer = &MockQRCodeRenderer{}
+	suite.useCase = NewQRCodeUseCase(suite.urlRepo, NewDomains(baseURL, nil, nil), suite.renderer)
+}
+
+func TestQRCodeUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(QRCodeUseCaseTestSuite))
+}
+
+func (suite *QRCodeUseCaseTestSuite) TestGivenTrackedQRCode_WhenRendering_ThenTrackingParameterEncoded() {
+
+	//Given
+	suite.urlRepo.LongURLRecordResult = suite.record
+	req := httptest.NewRequest("GET", "/urlshortener/v1/url/"+savedShortID+"/qr?format=svg&track=true", nil)
+	qrReq, _ := NewQRCodeRequest(savedShortID, req)
+
+	//When
+	response, err := suite.useCase.Execute(qrReq)
+
+	//Then
+	assert.Nil(suite.T(), err, "QRCode: Expected no error, got %v", err)
+	assert.Equal(suite.T(), savedShortURL+"?src=qr", suite.renderer.Text)
+	assert.Equal(suite.T(), "image/svg+xml", response.ContentType)
+	assert.Equal(suite.T(), []byte("qr"), response.Image)
+}
+
+func (suite *QRCodeUseCaseTestSuite) TestGivenRecordDisabled_WhenRendering_ThenReturnDisabledError() {
+
+	//Given
+	suite.record.Disabled = true
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	_, err := suite.useCase.Execute(QRCodeRequest{ShortID: savedShortID})
+
+	//Then
+	expectation := QRCodeDisabled
+	assert.NotNil(suite.T(), err, "QRCode: Expected Error, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "QRCode wrong error code. Expected '%d'. Got: %d", expectation, int(err.Code()))
+}
+
+func (suite *QRCodeUseCaseTestSuite) TestGivenRecordDoesNotExist_WhenRendering_ThenReturnError() {
+
+	//Given
+	suite.urlRepo.ReturnError = true
+	suite.urlRepo.LongURLRecordError = errors.New("Not Found")
+
+	//When
+	_, err := suite.useCase.Execute(QRCodeRequest{ShortID: "nil"})
+
+	//Then
+	expectation := QRCodeNotFound
+	assert.NotNil(suite.T(), err, "QRCode: Expected Error, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "QRCode wrong error code. Expected '%d'. Got: %d", expectation, int(err.Code()))
+}
+
+func TestNewQRCodeRequest(t *testing.T) {
+
+	req := httptest.NewRequest("GET", "/urlshortener/v1/url/shrt/qr", nil)
+	qrReq, err := NewQRCodeRequest("shrt", req)
+	assert.Nil(t, err)
+	assert.Equal(t, QRCodeOptions{
+		Format:     QRCodePNG,
+		Size:       DefaultQRCodeSize,
+		Level:      QRCodeLevelMedium,
+		Margin:     DefaultQRCodeMargin,
+		Foreground: color.RGBA{A: 0xff},
+		Background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}, qrReq.Options)
+
+	req = httptest.NewRequest("GET", "/urlshortener/v1/url/shrt/qr?size=512&level=h&margin=0&fg=%231a2b3c&bg=fafafa", nil)
+	qrReq, err = NewQRCodeRequest("shrt", req)
+	assert.Nil(t, err)
+	assert.Equal(t, 512, qrReq.Options.Size)
+	assert.Equal(t, QRCodeLevelHigh, qrReq.Options.Level)
+	assert.Equal(t, 0, qrReq.Options.Margin)
+	assert.Equal(t, color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff}, qrReq.Options.Foreground)
+	assert.Equal(t, color.RGBA{R: 0xfa, G: 0xfa, B: 0xfa, A: 0xff}, qrReq.Options.Background)
+
+	for _, query := range []string{"format=gif", "size=10", "size=big", "level=X", "margin=-1", "fg=red", "bg=fff", "track=maybe"} {
+		req = httptest.NewRequest("GET", "/urlshortener/v1/url/shrt/qr?"+query, nil)
+		_, err = NewQRCodeRequest("shrt", req)
+		if assert.NotNil(t, err, "Expected '%s' to be invalid", query) {
+			assert.Equal(t, QRCodeValidation, int(err.Code()))
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	"net/http"
+)
+
+// DefaultRedirectStatus is used for links that do not specify a redirect status
+const DefaultRedirectStatus = http.StatusSeeOther
+
+// IsRedirectStatus returns true if status can be used to redirect to a long url
+func IsRedirectStatus(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently,
+		http.StatusFound,
+		http.StatusSeeOther,
+		http.StatusTemporaryRedirect,
+		http.StatusPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsPermanentRedirectStatus returns true for redirects that clients may cache indefinitely
+func IsPermanentRedirectStatus(status int) bool {
+	return status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect
+}
+
+func validateRedirectStatus(code domain.Code, status int) domain.Err {
+	if status == 0 || IsRedirectStatus(status) {
+		return nil
+	}
+	return NewError(
+		code,
+		fmt.Sprintf("%d is not a valid redirect status. Expected one of 301, 302, 303, 307 or 308", status),
+		nil,
+	)
+}
@@ -4,22 +4,92 @@ import (
 	"fmt"
 	"github.com/w-k-s/short-url/domain"
 	u "github.com/w-k-s/short-url/domain/urlshortener"
-	"net/url"
+	"github.com/w-k-s/short-url/log"
+	"time"
 )
 
 type RetrieveOriginalURLUseCase struct {
-	repo u.URLRepository
+	repo           u.URLRepository
+	selfLinks      SelfLinks
+	screener       URLScreener
+	redirectStatus int
+	countryHeader  string
+	analytics      u.AnalyticsRepository
+	domains        Domains
 }
 
-func NewRetrieveOriginalURLUseCase(repo u.URLRepository) *RetrieveOriginalURLUseCase {
-	return &RetrieveOriginalURLUseCase{
-		repo,
+// RetrieveOriginalURLOption configures optional behaviour of the RetrieveOriginalURLUseCase
+type RetrieveOriginalURLOption func(*RetrieveOriginalURLUseCase)
+
+// WithSelfLinkDetection follows long urls that point back at the shortener
+// so that redirect chains are collapsed and redirect loops are detected.
+func WithSelfLinkDetection(selfLinks SelfLinks) RetrieveOriginalURLOption {
+	return func(s *RetrieveOriginalURLUseCase) {
+		s.selfLinks = selfLinks
+	}
+}
+
+// WithRedirectScreening screens long urls again before redirecting visitors to them.
+// Links that have been reported as malicious since they were shortened are treated as disabled.
+func WithRedirectScreening(screener URLScreener) RetrieveOriginalURLOption {
+	return func(s *RetrieveOriginalURLUseCase) {
+		s.screener = screener
+	}
+}
+
+// WithDefaultRedirectStatus sets the redirect status for links that do not specify one.
+func WithDefaultRedirectStatus(status int) RetrieveOriginalURLOption {
+	return func(s *RetrieveOriginalURLUseCase) {
+		s.redirectStatus = status
+	}
+}
+
+// WithCountryHeader sets the request header that holds the visitor's country code (e.g. CloudFront-Viewer-Country).
+// Targeting rules by country never match if no header is set.
+func WithCountryHeader(header string) RetrieveOriginalURLOption {
+	return func(s *RetrieveOriginalURLUseCase) {
+		s.countryHeader = header
 	}
 }
 
+// WithClickTracking records a click for every redirect
+func WithClickTracking(analytics u.AnalyticsRepository) RetrieveOriginalURLOption {
+	return func(s *RetrieveOriginalURLUseCase) {
+		s.analytics = analytics
+	}
+}
+
+// WithDomainLookup looks up short urls on the domain they are served on, and rejects the hosts of other domains.
+// Without it, short urls are looked up on the domain of their host.
+func WithDomainLookup(domains Domains) RetrieveOriginalURLOption {
+	return func(s *RetrieveOriginalURLUseCase) {
+		s.domains = domains
+	}
+}
+
+func NewRetrieveOriginalURLUseCase(repo u.URLRepository, options ...RetrieveOriginalURLOption) *RetrieveOriginalURLUseCase {
+	useCase := &RetrieveOriginalURLUseCase{
+		repo:           repo,
+		redirectStatus: DefaultRedirectStatus,
+	}
+	for _, option := range options {
+		option(useCase)
+	}
+	return useCase
+}
+
 func (s *RetrieveOriginalURLUseCase) Execute(retrieveRequest RetrieveOriginalURLRequest) (RetrieveOriginalURLResponse, domain.Err) {
 
-	var shortID string
+	if len(retrieveRequest.shortID) > 0 {
+		domain, ok := s.domains.RequestedDomain(retrieveRequest.domain)
+		if !ok {
+			return RetrieveOriginalURLResponse{}, domainNotAllowedError(retrieveRequest.domain)
+		}
+		retrieveRequest.shortURL = shortURLFor(s.domains.BaseURL(domain), retrieveRequest.shortID)
+	} else if retrieveRequest.knownDomain && !s.domains.serves(retrieveRequest.ShortURL().Host) {
+		return RetrieveOriginalURLResponse{}, domainNotAllowedError(retrieveRequest.ShortURL().Host)
+	}
+
 	path := retrieveRequest.ShortURL().Path
 	if len(path) == 0 {
 		return RetrieveOriginalURLResponse{}, NewError(
@@ -29,11 +99,19 @@ func (s *RetrieveOriginalURLUseCase) Execute(retrieveRequest RetrieveOriginalURL
 		)
 	}
 
-	if path[0] == '/' {
-		shortID = path[1:]
+	shortURL, ok := s.domains.relativeURL(retrieveRequest.ShortURL())
+	if !ok {
+		return RetrieveOriginalURLResponse{}, NewError(
+			RetrieveFullURLNotFound,
+			fmt.Sprintf("'%s' is not a short url", retrieveRequest.ShortURL().String()),
+			nil,
+		)
 	}
 
-	record, err := s.repo.LongURL(shortID)
+	shortID, suffix := splitShortURLPath(shortURL.Path)
+	domain := s.domains.DomainOf(shortURL.Host)
+
+	record, err := s.repo.LongURL(domain, shortID)
 	if err != nil {
 		return RetrieveOriginalURLResponse{}, NewError(
 			RetrieveFullURLNotFound,
@@ -42,17 +120,133 @@ func (s *RetrieveOriginalURLUseCase) Execute(retrieveRequest RetrieveOriginalURL
 		)
 	}
 
-	longURL, err := url.Parse(record.LongURL)
+	if record.Disabled {
+		return RetrieveOriginalURLResponse{}, disabledError(shortID, record.DisabledReason)
+	}
+
+	if len(suffix) > 0 && !record.Wildcard {
+		return RetrieveOriginalURLResponse{}, NewError(
+			RetrieveFullURLNotFound,
+			fmt.Sprintf("No URL for %s/%s", shortID, suffix),
+			nil,
+		)
+	}
+
+	visitor := newVisitor(retrieveRequest.header, retrieveRequest.remoteAddr, s.countryHeader)
+	rawLongURL, variant := selectLongURL(record, visitor)
+
+	longURL, err := destinationURL(record, rawLongURL, shortURL)
 	if err != nil {
 		return RetrieveOriginalURLResponse{}, NewError(
 			RetrieveFullURLParsing,
-			fmt.Sprintf("Failed to parse %s", record.LongURL),
+			fmt.Sprintf("Failed to parse %s", rawLongURL),
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	longURL, err = s.selfLinks.Resolve(s.repo, longURL, visitor)
+	if err == errRedirectLoop {
+		return RetrieveOriginalURLResponse{}, NewError(
+			RetrieveFullURLRedirectLoop,
+			fmt.Sprintf("%s redirects more than %d times or loops", retrieveRequest.ShortURL(), s.selfLinks.MaxDepth),
+			nil,
+		)
+	}
+	if err != nil {
+		return RetrieveOriginalURLResponse{}, NewError(
+			RetrieveFullURLNotFound,
+			fmt.Sprintf("%s points at a short url that does not exist", retrieveRequest.ShortURL()),
 			map[string]string{"error": err.Error()},
 		)
 	}
 
-	return RetrieveOriginalURLResponse{
-		LongURL:  longURL.String(),
-		ShortURL: retrieveRequest.ShortURL().String(),
-	}, nil
+	if (retrieveRequest.IsRedirect() || retrieveRequest.IsPreview()) && s.screener != nil {
+		if verdict, err := s.screener.Screen(longURL); err != nil {
+			log.Printf("Failed to screen '%s': %s", longURL, err)
+		} else if verdict.Malicious {
+			return RetrieveOriginalURLResponse{}, disabledError(shortID, verdict.Reason)
+		}
+	}
+
+	// Bots unfurling the link in a chat or feed are not visitors
+	if retrieveRequest.IsRedirect() && !IsCrawler(visitor.UserAgent) {
+		s.trackClick(domain, shortID, variant)
+	}
+
+	redirectStatus := record.RedirectStatus
+	if redirectStatus == 0 {
+		redirectStatus = s.redirectStatus
+	}
+
+	response := RetrieveOriginalURLResponse{
+		LongURL:        longURL.String(),
+		ShortURL:       retrieveRequest.ShortURL().String(),
+		RedirectStatus: redirectStatus,
+		CreateTime:     record.CreateTime,
+		UpdateTime:     record.UpdateTime,
+		PerVisitor:     len(record.TargetingRules) > 0 || len(record.Destinations) > 0,
+		Variant:        variant,
+		Social:         fromSocialRecord(record.Social),
+		Tags:           record.Tags,
+		Metadata:       record.Metadata,
+	}
+	if len(variant) > 0 {
+		response.VisitorID = visitor.ID
+	}
+	if retrieveRequest.IsPreview() {
+		response.Clicks = s.countClicks(domain, shortID)
+	}
+	return response, nil
+}
+
+func (s *RetrieveOriginalURLUseCase) trackClick(domain string, shortID string, variant string) {
+	if s.analytics == nil {
+		return
+	}
+
+	err := s.analytics.SaveClick(&u.Click{
+		Domain:     domain,
+		ShortID:    shortID,
+		Variant:    variant,
+		CreateTime: time.Now(),
+	})
+	if err != nil {
+		// Visitors should still be redirected if the click could not be recorded
+		log.Printf("Failed to record click on '%s': %s", shortID, err)
+	}
+}
+
+// countClicks returns the total number of clicks, or -1 if clicks are not tracked or could not be counted
+func (s *RetrieveOriginalURLUseCase) countClicks(domain string, shortID string) int64 {
+	if s.analytics == nil {
+		return -1
+	}
+
+	counts, err := s.analytics.ClickCounts(domain, shortID)
+	if err != nil {
+		log.Printf("Failed to count clicks on '%s': %s", shortID, err)
+		return -1
+	}
+
+	var clicks int64
+	for _, count := range counts {
+		clicks += count
+	}
+	return clicks
+}
+
+func domainNotAllowedError(host string) domain.Err {
+	return NewError(
+		RetrieveFullURLDomainNotAllowed,
+		fmt.Sprintf("'%s' is not one of the domains that urls are shortened on", host),
+		nil,
+	)
+}
+
+func disabledError(shortID string, reason string) domain.Err {
+	return NewError(
+		RetrieveFullURLDisabled,
+		fmt.Sprintf("The link '%s' has been disabled", shortID),
+		map[string]string{"reason": reason},
+	)
 }
@@ -5,21 +5,90 @@ import (
 	"github.com/w-k-s/short-url/domain"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
 type RetrieveOriginalURLRequest struct {
 	shortURL *url.URL
+	// shortID and domain are set instead of the short url when a link is looked up by its shortId
+	shortID string
+	domain  string
+	// knownDomain requires the host of the short url to be one of the domains that urls are shortened on
+	knownDomain bool
+	redirect    bool
+	preview     bool
+	header      http.Header
+	remoteAddr  string
 }
 
 func RedirectShortURLRequest(shortURL *url.URL) RetrieveOriginalURLRequest {
 	return RetrieveOriginalURLRequest{
-		shortURL,
+		shortURL: shortURL,
+		redirect: true,
 	}
 }
 
+// NewRedirectRequest redirects the visitor making the request.
+// The request headers and address are used to evaluate the link's targeting rules and split destinations.
+func NewRedirectRequest(req *http.Request) RetrieveOriginalURLRequest {
+	return RetrieveOriginalURLRequest{
+		shortURL:   requestURL(req),
+		redirect:   true,
+		header:     req.Header,
+		remoteAddr: req.RemoteAddr,
+	}
+}
+
+// PreviewSuffix is appended to a short url (e.g. /abc+) to preview its destination instead of being redirected
+const PreviewSuffix = "+"
+
+// IsPreviewRequest returns true if the visitor asked to preview the destination of the short url,
+// either with the PreviewSuffix or with `?preview=1`
+func IsPreviewRequest(req *http.Request) bool {
+	if strings.HasSuffix(req.URL.Path, PreviewSuffix) {
+		return true
+	}
+	preview, _ := strconv.ParseBool(req.URL.Query().Get("preview"))
+	return preview
+}
+
+// NewPreviewRequest looks up the destination the visitor making the request would be redirected to,
+// without counting a click.
+func NewPreviewRequest(req *http.Request) RetrieveOriginalURLRequest {
+	shortURL := *requestURL(req)
+	shortURL.Path = strings.TrimSuffix(shortURL.Path, PreviewSuffix)
+	shortURL.RawPath = ""
+
+	query := shortURL.Query()
+	if _, ok := query["preview"]; ok {
+		query.Del("preview")
+		shortURL.RawQuery = query.Encode()
+	}
+
+	return RetrieveOriginalURLRequest{
+		shortURL:   &shortURL,
+		preview:    true,
+		header:     req.Header,
+		remoteAddr: req.RemoteAddr,
+	}
+}
+
+// requestURL returns the url that was requested, including the Host header that the short url is looked up on
+func requestURL(req *http.Request) *url.URL {
+	requested := *req.URL
+	if len(req.Host) > 0 {
+		requested.Host = req.Host
+	}
+	return &requested
+}
+
 func NewRetrieveOriginalURLRequest(req *http.Request) (RetrieveOriginalURLRequest, domain.Err) {
+	return RetrieveShortURLRequest(req.FormValue("shortUrl"))
+}
 
-	shortURLReq := req.FormValue("shortUrl")
+// RetrieveShortURLRequest looks up a short url, which must be on one of the domains that urls are shortened on
+func RetrieveShortURLRequest(shortURLReq string) (RetrieveOriginalURLRequest, domain.Err) {
 
 	if len(shortURLReq) == 0 {
 		return RetrieveOriginalURLRequest{}, NewError(
@@ -46,9 +115,41 @@ func NewRetrieveOriginalURLRequest(req *http.Request) (RetrieveOriginalURLReques
 		)
 	}
 
-	return RetrieveOriginalURLRequest{shortURL}, nil
+	return RetrieveOriginalURLRequest{shortURL: shortURL, knownDomain: true}, nil
+}
+
+// NewRetrieveShortIDRequest looks up the link with the shortId on the domain in the query string (e.g. `?domain=brand.ly`),
+// or on the domain of BASE_URL if no domain is given.
+func NewRetrieveShortIDRequest(shortID string, req *http.Request) (RetrieveOriginalURLRequest, domain.Err) {
+	return RetrieveShortIDRequest(shortID, req.URL.Query().Get("domain"))
+}
+
+// RetrieveShortIDRequest looks up the link with the shortId on the domain, or on the domain of BASE_URL if the domain is empty
+func RetrieveShortIDRequest(shortID string, domainHost string) (RetrieveOriginalURLRequest, domain.Err) {
+	if len(shortID) == 0 {
+		return RetrieveOriginalURLRequest{}, NewError(
+			RetrieveFullURLValidation,
+			"`shortId` is required",
+			nil,
+		)
+	}
+
+	return RetrieveOriginalURLRequest{
+		shortID: shortID,
+		domain:  strings.ToLower(strings.TrimSpace(domainHost)),
+	}, nil
 }
 
 func (r RetrieveOriginalURLRequest) ShortURL() *url.URL {
 	return r.shortURL
 }
+
+// IsRedirect returns true if the visitor is being redirected to the long url
+func (r RetrieveOriginalURLRequest) IsRedirect() bool {
+	return r.redirect
+}
+
+// IsPreview returns true if the visitor is shown the long url instead of being redirected to it
+func (r RetrieveOriginalURLRequest) IsPreview() bool {
+	return r.preview
+}
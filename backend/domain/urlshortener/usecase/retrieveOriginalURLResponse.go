@@ -1,6 +1,28 @@
 package usecase
 
+import (
+	"github.com/w-k-s/short-url/codec"
+	"time"
+)
+
 type RetrieveOriginalURLResponse struct {
-	LongURL  string `json:"longUrl"`
-	ShortURL string `json:"shortUrl"`
+	LongURL        string    `json:"longUrl" xml:"longUrl"`
+	ShortURL       string    `json:"shortUrl" xml:"shortUrl"`
+	RedirectStatus int       `json:"redirectStatus" xml:"redirectStatus"`
+	CreateTime     time.Time `json:"createTime" xml:"createTime"`
+	// UpdateTime is when the link was last changed
+	UpdateTime time.Time `json:"-" xml:"-"`
+	// PerVisitor is true if the long url depends on the visitor, i.e. the link has targeting rules or split destinations
+	PerVisitor bool `json:"-" xml:"-"`
+	// Variant is the destination the visitor was assigned to, if the link is split across destinations
+	Variant string `json:"variant,omitempty" xml:"variant,omitempty"`
+	// VisitorID should be remembered in the VisitorCookie so that the visitor is assigned the same variant next time
+	VisitorID string `json:"-" xml:"-"`
+	// Clicks is the number of times the link was followed. It is only counted for previews; -1 if unknown.
+	Clicks int64 `json:"-" xml:"-"`
+	// Social is shown when the link is unfurled by chat apps and social networks
+	Social *SocialMetadata `json:"social,omitempty" xml:"social,omitempty"`
+	// Tags and Metadata organize the link e.g. by campaign or team
+	Tags     []string        `json:"tags,omitempty" xml:"tags>item,omitempty"`
+	Metadata codec.StringMap `json:"metadata,omitempty" xml:"metadata,omitempty"`
 }
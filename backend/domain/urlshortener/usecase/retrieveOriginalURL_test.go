@@ -5,7 +5,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 )
@@ -19,9 +22,9 @@ type RetrieveOriginalURLUseCaseTestSuite struct {
 
 func (suite *RetrieveOriginalURLUseCaseTestSuite) SetupTest() {
 	suite.record = &u.URLRecord{
-		savedLongURL,
-		savedShortID,
-		time.Now(),
+		LongURL:    savedLongURL,
+		ShortID:    savedShortID,
+		CreateTime: time.Now(),
 	}
 
 	suite.urlRepo = &MockURLRepository{}
@@ -79,3 +82,270 @@ func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenShortURL_WhenRecordEx
 
 	assert.Equal(suite.T(), savedLongURL, resp.LongURL, "GetLongURL returned wrong original url. Expected %s, Got: %s", savedLongURL, resp.LongURL)
 }
+
+func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenNoDomainLookup_WhenRetrievingOnKnownDomain_ThenReturnOriginalURL() {
+
+	//Given
+	testURL, _ := url.Parse(savedShortURL)
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	byShortURL, err := suite.useCase.Execute(RetrieveOriginalURLRequest{shortURL: testURL, knownDomain: true})
+	assert.Nil(suite.T(), err, "GetLongURL: Expected no error, got %v", err)
+	byShortID, err := suite.useCase.Execute(RetrieveOriginalURLRequest{shortID: savedShortID, domain: testURL.Host})
+	assert.Nil(suite.T(), err, "GetLongURL: Expected no error, got %v", err)
+
+	//Then
+	assert.Equal(suite.T(), savedLongURL, byShortURL.LongURL)
+	assert.Equal(suite.T(), savedLongURL, byShortID.LongURL)
+}
+
+func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenBrandedDomain_WhenRetrieving_ThenRecordOnHostDomainReturned() {
+
+	//Given
+	repo := DomainURLRepository{Records: map[string]*u.URLRecord{
+		"small.ml/" + savedShortID:     {LongURL: "https://example.com/default", Domain: "small.ml", ShortID: savedShortID},
+		"go.brand.com/" + savedShortID: {LongURL: "https://example.com/brand", Domain: "go.brand.com", ShortID: savedShortID},
+	}}
+	baseURL, _ := url.Parse("https://small.ml")
+	brandedURL, _ := url.Parse("https://go.brand.com")
+	useCase := NewRetrieveOriginalURLUseCase(repo, WithDomainLookup(NewDomains(baseURL, []*url.URL{brandedURL}, []string{"www.small.ml"})))
+
+	for host, expectation := range map[string]string{
+		"GO.brand.com": "https://example.com/brand",
+		"www.small.ml": "https://example.com/default",
+		"small.ml":     "https://example.com/default",
+	} {
+		//When
+		req := httptest.NewRequest(http.MethodGet, "/"+savedShortID, nil)
+		req.Host = host
+		retrieveReq := NewRedirectRequest(req)
+		resp, err := useCase.Execute(retrieveReq)
+
+		//Then
+		assert.Nil(suite.T(), err, "GetLongURL on '%s': Expected no error, got %v", host, err)
+		assert.Equal(suite.T(), expectation, resp.LongURL, "GetLongURL on '%s' returned wrong original url", host)
+	}
+}
+
+func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenBaseURLWithPath_WhenRetrieving_ThenShortIDAfterPathUsed() {
+
+	//Given
+	repo := DomainURLRepository{Records: map[string]*u.URLRecord{
+		"corp.example/" + savedShortID: {LongURL: "https://example.com/docs", Domain: "corp.example", ShortID: savedShortID, Wildcard: true},
+	}}
+	baseURL, _ := url.Parse("https://corp.example/go/")
+	useCase := NewRetrieveOriginalURLUseCase(repo, WithDomainLookup(NewDomains(baseURL, nil, nil)))
+
+	//When
+	req := httptest.NewRequest(http.MethodGet, "https://corp.example/go/"+savedShortID+"/guide", nil)
+	resp, err := useCase.Execute(NewRedirectRequest(req))
+
+	//Then
+	assert.Nil(suite.T(), err, "GetLongURL: Expected no error, got %v", err)
+	assert.Equal(suite.T(), "https://example.com/docs/guide", resp.LongURL)
+
+	//When
+	req = httptest.NewRequest(http.MethodGet, "https://corp.example/"+savedShortID, nil)
+	_, err = useCase.Execute(NewRedirectRequest(req))
+
+	//Then
+	expectation := RetrieveFullURLNotFound
+	assert.NotNil(suite.T(), err, "GetLongURL. Expected err, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "GetLongURL wrong error code. Expected '%d'. Got: %d", expectation, int(err.Code()))
+}
+
+func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenShortURL_WhenRecordDisabled_ThenReturnDisabledError() {
+
+	//Given
+	testURL, _ := url.Parse(savedShortURL)
+	suite.record.Disabled = true
+	suite.record.DisabledReason = "phishing"
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	_, err := suite.useCase.Execute(RedirectShortURLRequest(testURL))
+
+	//Then
+	expectation := RetrieveFullURLDisabled
+	assert.NotNil(suite.T(), err, "GetLongURL. Expected err, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "GetLongURL wrong error code. Expected '%d'. Got: %d", expectation, int(err.Code()))
+	assert.Equal(suite.T(), "phishing", err.Fields()["reason"])
+}
+
+func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenRedirectScreening_WhenLongURLMalicious_ThenReturnDisabledError() {
+
+	//Given
+	testURL, _ := url.Parse(savedShortURL)
+	suite.urlRepo.LongURLRecordResult = suite.record
+	screener := MockURLScreener{Verdict: Verdict{Malicious: true, Reason: "malware"}}
+	useCase := NewRetrieveOriginalURLUseCase(suite.urlRepo, WithRedirectScreening(screener))
+
+	//When
+	_, lookupErr := useCase.Execute(RetrieveOriginalURLRequest{shortURL: testURL})
+	_, redirectErr := useCase.Execute(RedirectShortURLRequest(testURL))
+
+	//Then
+	expectation := RetrieveFullURLDisabled
+	assert.Nil(suite.T(), lookupErr, "Expected lookups not to be screened. Got: %v", lookupErr)
+	assert.NotNil(suite.T(), redirectErr, "GetLongURL. Expected err, got nil")
+	assert.Equal(suite.T(), expectation, int(redirectErr.Code()), "GetLongURL wrong error code. Expected '%d'. Got: %d", expectation, int(redirectErr.Code()))
+}
+
+func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenRecordWithoutRedirectStatus_WhenRetrieving_ThenDefaultRedirectStatusReturned() {
+
+	//Given
+	testURL, _ := url.Parse(savedShortURL)
+	suite.urlRepo.LongURLRecordResult = suite.record
+	useCase := NewRetrieveOriginalURLUseCase(suite.urlRepo, WithDefaultRedirectStatus(302))
+
+	//When
+	resp, _ := useCase.Execute(RedirectShortURLRequest(testURL))
+
+	//Then
+	assert.Equal(suite.T(), 302, resp.RedirectStatus)
+
+	//Given
+	suite.record.RedirectStatus = 301
+
+	//When
+	resp, _ = useCase.Execute(RedirectShortURLRequest(testURL))
+
+	//Then
+	assert.Equal(suite.T(), 301, resp.RedirectStatus)
+}
+
+func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenPathSuffix_WhenRecordIsNotWildcard_ThenReturnError() {
+
+	//Given
+	testURL, _ := url.Parse(savedShortURL + "/extra")
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	_, err := suite.useCase.Execute(RedirectShortURLRequest(testURL))
+
+	//Then
+	expectation := RetrieveFullURLNotFound
+	assert.NotNil(suite.T(), err, "GetLongURL. Expected err, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "GetLongURL wrong error code. Expected '%d'. Got: %d", expectation, int(err.Code()))
+}
+
+func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenTargetingRules_WhenRedirecting_ThenVisitorSentToMatchingLongURL() {
+
+	//Given
+	suite.record.TargetingRules = []u.TargetingRule{
+		{Platform: "android", LongURL: "https://play.google.com/store/apps/details?id=com.example"},
+		{Country: "AE", LongURL: "https://example.ae"},
+	}
+	suite.urlRepo.LongURLRecordResult = suite.record
+	useCase := NewRetrieveOriginalURLUseCase(suite.urlRepo, WithCountryHeader("CloudFront-Viewer-Country"))
+
+	req := httptest.NewRequest("GET", "/"+savedShortID, nil)
+	req.Header.Set("CloudFront-Viewer-Country", "AE")
+
+	//When
+	resp, err := useCase.Execute(NewRedirectRequest(req))
+
+	//Then
+	assert.Nil(suite.T(), err, "GetLongURL. Expected no error, got %v", err)
+	assert.Equal(suite.T(), "https://example.ae", resp.LongURL)
+
+	//Given
+	req.Header.Set("User-Agent", androidUserAgent)
+
+	//When
+	resp, _ = useCase.Execute(NewRedirectRequest(req))
+
+	//Then
+	assert.Equal(suite.T(), "https://play.google.com/store/apps/details?id=com.example", resp.LongURL)
+}
+
+func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenSplitDestinations_WhenRedirecting_ThenVariantClickRecorded() {
+
+	//Given
+	suite.record.Destinations = []u.Destination{
+		{Variant: "A", LongURL: "https://example.com/a", Weight: 1},
+		{Variant: "B", LongURL: "https://example.com/b", Weight: 1},
+	}
+	suite.urlRepo.LongURLRecordResult = suite.record
+	analytics := &MockAnalyticsRepository{}
+	useCase := NewRetrieveOriginalURLUseCase(suite.urlRepo, WithClickTracking(analytics))
+
+	req := httptest.NewRequest("GET", "/"+savedShortID, nil)
+	req.AddCookie(&http.Cookie{Name: VisitorCookie, Value: "returning-visitor"})
+
+	//When
+	resp, err := useCase.Execute(NewRedirectRequest(req))
+	lookupURL, _ := url.Parse(savedShortURL)
+	lookup, _ := useCase.Execute(RetrieveOriginalURLRequest{shortURL: lookupURL})
+
+	//Then
+	assert.Nil(suite.T(), err, "GetLongURL. Expected no error, got %v", err)
+	assert.Contains(suite.T(), []string{"A", "B"}, resp.Variant)
+	assert.Equal(suite.T(), "https://example.com/"+strings.ToLower(resp.Variant), resp.LongURL)
+	assert.Equal(suite.T(), "returning-visitor", resp.VisitorID)
+	assert.Equal(suite.T(), savedLongURL, lookup.LongURL, "Expected lookups to return the link's long url")
+	if assert.Len(suite.T(), analytics.SavedClicks, 1, "Expected only redirects to be counted") {
+		assert.Equal(suite.T(), resp.Variant, analytics.SavedClicks[0].Variant)
+	}
+}
+
+func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenPreviewRequest_WhenRetrieving_ThenClicksCountedButNotRecorded() {
+
+	//Given
+	suite.record.QueryMerge = string(QueryMergeKeep)
+	suite.urlRepo.LongURLRecordResult = suite.record
+	analytics := &MockAnalyticsRepository{ClickCountsValue: u.ClickCounts{"": 4, "A": 1}}
+	useCase := NewRetrieveOriginalURLUseCase(suite.urlRepo, WithClickTracking(analytics))
+
+	//When
+	resp, err := useCase.Execute(NewPreviewRequest(httptest.NewRequest("GET", "/"+savedShortID+"+?ref=mail&preview=1", nil)))
+
+	//Then
+	assert.Nil(suite.T(), err, "GetLongURL. Expected no error, got %v", err)
+	assert.Equal(suite.T(), savedLongURL+"?ref=mail", resp.LongURL)
+	assert.Equal(suite.T(), int64(5), resp.Clicks)
+	assert.Empty(suite.T(), analytics.SavedClicks, "Expected previews not to be counted as clicks")
+}
+
+func (suite *RetrieveOriginalURLUseCaseTestSuite) TestGivenCrawler_WhenRedirecting_ThenSocialMetadataReturnedAndClickNotRecorded() {
+
+	//Given
+	suite.record.Social = u.SocialMetadata{Title: "Example", ImageURL: "https://www.example.com/card.png"}
+	suite.urlRepo.LongURLRecordResult = suite.record
+	analytics := &MockAnalyticsRepository{}
+	useCase := NewRetrieveOriginalURLUseCase(suite.urlRepo, WithClickTracking(analytics))
+	req := httptest.NewRequest("GET", "/"+savedShortID, nil)
+	req.Header.Set("User-Agent", "Twitterbot/1.0")
+
+	//When
+	resp, err := useCase.Execute(NewRedirectRequest(req))
+
+	//Then
+	assert.Nil(suite.T(), err, "GetLongURL. Expected no error, got %v", err)
+	assert.Equal(suite.T(), &SocialMetadata{Title: "Example", Image: "https://www.example.com/card.png"}, resp.Social)
+	assert.Empty(suite.T(), analytics.SavedClicks, "Expected crawlers not to be counted as clicks")
+}
+
+func TestIsPreviewRequest(t *testing.T) {
+	assert.True(t, IsPreviewRequest(httptest.NewRequest("GET", "/abc+", nil)))
+	assert.True(t, IsPreviewRequest(httptest.NewRequest("GET", "/abc?preview=1", nil)))
+	assert.False(t, IsPreviewRequest(httptest.NewRequest("GET", "/abc?preview=0", nil)))
+	assert.False(t, IsPreviewRequest(httptest.NewRequest("GET", "/abc", nil)))
+}
+
+//-- DomainURLRepository
+
+// DomainURLRepository finds records by their domain and shortId
+type DomainURLRepository struct {
+	MockURLRepository
+	Records map[string]*u.URLRecord
+}
+
+func (m DomainURLRepository) LongURL(domain string, shortID string) (*u.URLRecord, error) {
+	record, ok := m.Records[domain+"/"+shortID]
+	if !ok {
+		return nil, errors.New("Not found")
+	}
+	return record, nil
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"net/url"
+)
+
+// Verdict is the outcome of screening a url
+type Verdict struct {
+	Malicious bool
+	Reason    string
+}
+
+// URLScreener checks urls against known malicious urls (e.g. phishing and malware links)
+type URLScreener interface {
+	Screen(longURL *url.URL) (Verdict, error)
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"net/url"
+	"strings"
+)
+
+// SelfLinkPolicy determines how long urls pointing at one of the shortener's own domains are handled.
+type SelfLinkPolicy string
+
+const (
+	// SelfLinkResolve replaces the long url with the destination of the short url it points at.
+	SelfLinkResolve SelfLinkPolicy = "resolve"
+	// SelfLinkReject refuses to shorten long urls that point at the shortener.
+	SelfLinkReject SelfLinkPolicy = "reject"
+)
+
+var errRedirectLoop = errors.New("redirect loop")
+
+// SelfLinks detects long urls that point back at the shortener, which would otherwise
+// produce redirect chains or infinite redirect loops.
+type SelfLinks struct {
+	// Domains are the hosts the shortener is served on (e.g. the host of BASE_URL and any alias domains).
+	Domains []string
+	Policy  SelfLinkPolicy
+	// MaxDepth is the maximum number of short urls that are followed before giving up.
+	MaxDepth int
+	// Registry maps the host of a self-link to the domain that its shortId is saved under
+	Registry Domains
+}
+
+func DefaultSelfLinks(baseURL *url.URL) SelfLinks {
+	var domains []string
+	if baseURL != nil {
+		domains = append(domains, baseURL.Host)
+	}
+	return SelfLinks{
+		Domains:  domains,
+		Policy:   SelfLinkResolve,
+		MaxDepth: 5,
+	}
+}
+
+// IsSelfLink returns true if the url is hosted on one of the shortener's domains
+func (l SelfLinks) IsSelfLink(link *url.URL) bool {
+	for _, domain := range l.Domains {
+		if strings.EqualFold(link.Host, domain) || strings.EqualFold(link.Hostname(), domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve follows short urls pointing at the shortener until a url hosted elsewhere is reached.
+// Links to the shortener's root (e.g. the home page) are returned as-is.
+// errRedirectLoop is returned if a short url is visited twice or MaxDepth is exceeded.
+// The visitor is used to follow the targeting rules of each short url.
+func (l SelfLinks) Resolve(repo u.URLRepository, link *url.URL, visitor Visitor) (*url.URL, error) {
+	visited := map[string]bool{}
+
+	for depth := 0; l.IsSelfLink(link); depth++ {
+		shortURL, ok := l.Registry.relativeURL(link)
+		shortID, _ := splitShortURLPath(shortURL.Path)
+		if !ok || len(shortID) == 0 {
+			return link, nil
+		}
+
+		domain := l.Registry.DomainOf(link.Host)
+		if visited[domain+"/"+shortID] || depth >= l.MaxDepth {
+			return nil, errRedirectLoop
+		}
+		visited[domain+"/"+shortID] = true
+
+		record, err := repo.LongURL(domain, shortID)
+		if err != nil {
+			return nil, fmt.Errorf("no url for '%s': %s", shortID, err)
+		}
+
+		longURL, _ := selectLongURL(record, visitor)
+		if link, err = destinationURL(record, longURL, shortURL); err != nil {
+			return nil, err
+		}
+	}
+
+	return link, nil
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"github.com/w-k-s/short-url/log"
+	"net/url"
+	"testing"
+)
+
+//-- InMemoryURLRepository
+
+// InMemoryURLRepository looks up records by shortId so that redirect chains can be tested
+type InMemoryURLRepository struct {
+	MockURLRepository
+	Records map[string]*u.URLRecord
+}
+
+func (m InMemoryURLRepository) LongURL(domain string, shortID string) (*u.URLRecord, error) {
+	record, ok := m.Records[shortID]
+	if !ok {
+		return nil, errors.New("Not Found")
+	}
+	return record, nil
+}
+
+func newSelfLinkRepository(links map[string]string) InMemoryURLRepository {
+	records := map[string]*u.URLRecord{}
+	for shortID, longURL := range links {
+		records[shortID] = &u.URLRecord{LongURL: longURL, ShortID: shortID}
+	}
+	return InMemoryURLRepository{Records: records}
+}
+
+func TestSelfLinksDetectsOwnDomains(t *testing.T) {
+
+	selfLinks := SelfLinks{Domains: []string{"small.ml", "sml.ml"}, Policy: SelfLinkResolve, MaxDepth: 5}
+
+	ownURL, _ := url.Parse("https://SMALL.ml/abc")
+	aliasURL, _ := url.Parse("http://sml.ml:8080/abc")
+	otherURL, _ := url.Parse("https://www.example.com/abc")
+
+	assert.True(t, selfLinks.IsSelfLink(ownURL))
+	assert.True(t, selfLinks.IsSelfLink(aliasURL))
+	assert.False(t, selfLinks.IsSelfLink(otherURL))
+}
+
+func TestSelfLinksResolvesChain(t *testing.T) {
+
+	selfLinks := SelfLinks{Domains: []string{"small.ml"}, Policy: SelfLinkResolve, MaxDepth: 5}
+	repo := newSelfLinkRepository(map[string]string{
+		"a": "https://small.ml/b",
+		"b": "https://small.ml/c",
+		"c": "https://www.example.com",
+	})
+
+	link, _ := url.Parse("https://small.ml/a")
+	resolved, err := selfLinks.Resolve(repo, link, Visitor{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://www.example.com", resolved.String())
+}
+
+func TestSelfLinksDetectsCycle(t *testing.T) {
+
+	selfLinks := SelfLinks{Domains: []string{"small.ml"}, Policy: SelfLinkResolve, MaxDepth: 5}
+	repo := newSelfLinkRepository(map[string]string{
+		"a": "https://small.ml/b",
+		"b": "https://small.ml/a",
+	})
+
+	link, _ := url.Parse("https://small.ml/a")
+	_, err := selfLinks.Resolve(repo, link, Visitor{})
+
+	assert.Equal(t, errRedirectLoop, err)
+}
+
+func TestSelfLinksStopsAtMaxDepth(t *testing.T) {
+
+	selfLinks := SelfLinks{Domains: []string{"small.ml"}, Policy: SelfLinkResolve, MaxDepth: 1}
+	repo := newSelfLinkRepository(map[string]string{
+		"a": "https://small.ml/b",
+		"b": "https://www.example.com",
+	})
+
+	link, _ := url.Parse("https://small.ml/a")
+	_, err := selfLinks.Resolve(repo, link, Visitor{})
+
+	assert.Equal(t, errRedirectLoop, err)
+}
+
+func TestGivenSelfLinkRejected_WhenShorteningURL_ThenReturnError(t *testing.T) {
+
+	baseURL, _ := url.Parse(baseURLString)
+	selfLinks := DefaultSelfLinks(baseURL)
+	selfLinks.Policy = SelfLinkReject
+	useCase := NewShortenURLUseCase(&MockURLRepository{}, baseURL, MockShortIDGenerator{}, WithSelfLinks(selfLinks))
+
+	testURL, _ := url.Parse(savedShortURL)
+	_, err := useCase.Execute(ShortenURLRequest{
+		LongURL:   savedShortURL,
+		parsedURL: testURL,
+	})
+
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ShortenURLSelfLink, int(err.Code()))
+	}
+}
+
+func TestGivenSelfLinkResolved_WhenShorteningURL_ThenFinalDestinationReturned(t *testing.T) {
+
+	log.Init()
+
+	baseURL, _ := url.Parse(baseURLString)
+	repo := newSelfLinkRepository(map[string]string{
+		savedShortID: savedLongURL,
+	})
+	repo.SaveURLRecordResult = &u.URLRecord{LongURL: savedLongURL, ShortID: "new"}
+	useCase := NewShortenURLUseCase(repo, baseURL, MockShortIDGenerator{ShortID: "new"})
+
+	testURL, _ := url.Parse(savedShortURL)
+	response, err := useCase.Execute(ShortenURLRequest{
+		LongURL:   savedShortURL,
+		parsedURL: testURL,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, savedLongURL, response.LongURL)
+}
+
+func TestGivenRedirectLoop_WhenRetrievingOriginalURL_ThenReturnError(t *testing.T) {
+
+	baseURL, _ := url.Parse(baseURLString)
+	repo := newSelfLinkRepository(map[string]string{
+		"a": "https://small.ml/b",
+		"b": "https://small.ml/a",
+	})
+	useCase := NewRetrieveOriginalURLUseCase(repo, WithSelfLinkDetection(DefaultSelfLinks(baseURL)))
+
+	shortURL, _ := url.Parse("https://small.ml/a")
+	_, err := useCase.Execute(RetrieveOriginalURLRequest{shortURL: shortURL})
+
+	if assert.NotNil(t, err) {
+		assert.Equal(t, RetrieveFullURLRedirectLoop, int(err.Code()))
+	}
+}
@@ -6,38 +6,174 @@ import (
 	u "github.com/w-k-s/short-url/domain/urlshortener"
 	"github.com/w-k-s/short-url/log"
 	"net/url"
+	"strings"
 	"time"
 )
 
 type ShortenURLUseCase struct {
 	repo      u.URLRepository
-	baseURL   *url.URL
+	domains   Domains
 	generator ShortIDGenerator
+	policy    DestinationPolicy
+	selfLinks SelfLinks
+	screener  URLScreener
+	fetcher   MetadataFetcher
+	// idempotency remembers the responses to requests with an idempotency key, if it is set
+	idempotency *idempotency
 }
 
-func NewShortenURLUseCase(repo u.URLRepository, baseURL *url.URL, generator ShortIDGenerator) *ShortenURLUseCase {
-	return &ShortenURLUseCase{
-		repo,
-		baseURL,
-		generator,
+// ShortenURLOption configures optional behaviour of the ShortenURLUseCase
+type ShortenURLOption func(*ShortenURLUseCase)
+
+// WithDestinationPolicy replaces the DefaultDestinationPolicy
+func WithDestinationPolicy(policy DestinationPolicy) ShortenURLOption {
+	return func(s *ShortenURLUseCase) {
+		s.policy = policy
+	}
+}
+
+// WithSelfLinks replaces the DefaultSelfLinks
+func WithSelfLinks(selfLinks SelfLinks) ShortenURLOption {
+	return func(s *ShortenURLUseCase) {
+		s.selfLinks = selfLinks
+	}
+}
+
+// WithDomains registers the branded domains that urls can be shortened on, in addition to the base url
+func WithDomains(domains Domains) ShortenURLOption {
+	return func(s *ShortenURLUseCase) {
+		s.domains = domains
+	}
+}
+
+// WithURLScreener rejects long urls that the screener considers malicious
+func WithURLScreener(screener URLScreener) ShortenURLOption {
+	return func(s *ShortenURLUseCase) {
+		s.screener = screener
 	}
 }
 
+// WithMetadataFetcher fetches the title, description and image of long urls so that short urls unfurl like the page they point at
+func WithMetadataFetcher(fetcher MetadataFetcher) ShortenURLOption {
+	return func(s *ShortenURLUseCase) {
+		s.fetcher = fetcher
+	}
+}
+
+// WithIdempotency saves the response to a request with an idempotency key for the ttl,
+// so that retries of the request get the same response instead of shortening the url again
+func WithIdempotency(repo u.IdempotencyRepository, ttl time.Duration) ShortenURLOption {
+	return func(s *ShortenURLUseCase) {
+		s.idempotency = &idempotency{repo: repo, ttl: ttl}
+	}
+}
+
+func NewShortenURLUseCase(repo u.URLRepository, baseURL *url.URL, generator ShortIDGenerator, options ...ShortenURLOption) *ShortenURLUseCase {
+	useCase := &ShortenURLUseCase{
+		repo:      repo,
+		domains:   NewDomains(baseURL, nil, nil),
+		generator: generator,
+		policy:    DefaultDestinationPolicy(),
+		selfLinks: DefaultSelfLinks(baseURL),
+	}
+	for _, option := range options {
+		option(useCase)
+	}
+	return useCase
+}
+
 func (s *ShortenURLUseCase) Execute(shortReq ShortenURLRequest) (ShortenURLResponse, domain.Err) {
-	longURL := shortReq.parsedURL
-	existingRecord, _ := s.repo.ShortURL(longURL.String())
+	if s.idempotency != nil && len(shortReq.IdempotencyKey) > 0 {
+		return s.idempotency.execute(shortReq, s.execute)
+	}
+	return s.execute(shortReq)
+}
+
+func (s *ShortenURLUseCase) execute(shortReq ShortenURLRequest) (ShortenURLResponse, domain.Err) {
+	shortReq, existingRecord, err := s.prepare(shortReq)
+	if err != nil {
+		return ShortenURLResponse{}, err
+	}
 
 	if existingRecord != nil {
-		log.Printf("Record found. Long Url: %s, shortURL: %s", longURL, existingRecord.ShortID)
 		return s.buildShortenedURLResponse(shortReq, existingRecord), nil
 	}
 
+	return s.save(shortReq, s.newRecord(shortReq))
+}
+
+// prepare checks the long url and returns the request for the url that will be shortened,
+// along with the existing record for the url if it should be reused.
+func (s *ShortenURLUseCase) prepare(shortReq ShortenURLRequest) (ShortenURLRequest, *u.URLRecord, domain.Err) {
+	if len(shortReq.Domain) == 0 {
+		shortReq.baseURL = s.domains.Default()
+	} else if baseURL, ok := s.domains.Lookup(shortReq.Domain); ok {
+		shortReq.baseURL = baseURL
+	} else {
+		return shortReq, nil, NewError(
+			ShortenURLDomainNotAllowed,
+			fmt.Sprintf("'%s' is not one of the domains that urls can be shortened on", shortReq.Domain),
+			nil,
+		)
+	}
+
+	if shortReq.UTM != nil {
+		shortReq.parsedURL = shortReq.UTM.Apply(shortReq.parsedURL)
+		shortReq.LongURL = shortReq.parsedURL.String()
+	}
+
+	longURL, err := s.resolveSelfLink(shortReq.parsedURL)
+	if err != nil {
+		return shortReq, nil, err
+	}
+	if err := s.policy.Check(longURL); err != nil {
+		return shortReq, nil, err
+	}
+	if err := s.screen(longURL); err != nil {
+		return shortReq, nil, err
+	}
+	if err := checkAlternativeLongURLs(ShortenURLValidation, shortReq.Targeting, shortReq.Destinations, s.policy, s.screener); err != nil {
+		return shortReq, nil, err
+	}
+	if longURL != shortReq.parsedURL {
+		shortReq.LongURL = longURL.String()
+		shortReq.parsedURL = longURL
+	}
+
+	existingRecord := s.findExistingRecord(shortReq)
+	if existingRecord != nil {
+		log.Printf("Record found. Long Url: %s, shortURL: %s", longURL, existingRecord.ShortID)
+	}
+	return shortReq, existingRecord, nil
+}
+
+// newRecord returns the record for a prepared request.
+// Its shortId is empty unless the user specified one; save generates one.
+func (s *ShortenURLUseCase) newRecord(shortReq ShortenURLRequest) *u.URLRecord {
+	now := time.Now()
+	return &u.URLRecord{
+		LongURL:        shortReq.parsedURL.String(),
+		Domain:         domainKey(shortReq.baseURL),
+		ShortID:        shortReq.ShortID,
+		Owner:          shortReq.Owner,
+		CreateTime:     now,
+		UpdateTime:     now,
+		RedirectStatus: shortReq.RedirectStatus,
+		QueryMerge:     string(shortReq.QueryMerge),
+		Wildcard:       shortReq.Wildcard,
+		TargetingRules: toTargetingRecords(shortReq.Targeting),
+		Destinations:   toDestinationRecords(shortReq.Destinations),
+		Social:         toSocialRecord(socialMetadataFor(s.fetcher, shortReq.parsedURL, shortReq.Social)),
+		Tags:           shortReq.Tags,
+		Metadata:       shortReq.Metadata,
+	}
+}
+
+// save saves the record under the shortId the user specified.
+// Otherwise, a shortId is generated; longer shortIds are tried if generated shortIds are in use.
+func (s *ShortenURLUseCase) save(shortReq ShortenURLRequest, record *u.URLRecord) (ShortenURLResponse, domain.Err) {
 	if shortReq.UserDidSpecifyShortId() {
-		newRecord, err := s.repo.SaveRecord(&u.URLRecord{
-			LongURL:    longURL.String(),
-			ShortID:    shortReq.ShortID,
-			CreateTime: time.Now(),
-		})
+		newRecord, err := s.repo.SaveRecord(record)
 		if err != nil {
 			return ShortenURLResponse{}, NewError(
 				ShortenURLShortIDInUse,
@@ -51,41 +187,163 @@ func (s *ShortenURLUseCase) Execute(shortReq ShortenURLRequest) (ShortenURLRespo
 	shortIDLengths := []ShortIDLength{VeryShort, Short, Medium, VeryLong}
 	inserted := false
 	var newRecord *u.URLRecord
-	var err error
+	var saveErr error
 
 	for try := 0; !inserted && try < len(shortIDLengths); try++ {
-		shortID := s.generator.Generate(shortIDLengths[try])
-		newRecord, err = s.repo.SaveRecord(&u.URLRecord{
-			LongURL:    longURL.String(),
-			ShortID:    shortID,
-			CreateTime: time.Now(),
-		})
+		record.ShortID = s.generator.Generate(shortIDLengths[try])
+		newRecord, saveErr = s.repo.SaveRecord(record)
 
-		log.Printf("longURL '%s' (Attempt %d): Using shortId '%s'.\n\t-- Error: %v\n\n", longURL, try, shortID, err)
-		inserted = err == nil
+		log.Printf("longURL '%s' (Attempt %d): Using shortId '%s'.\n\t-- Error: %v\n\n", record.LongURL, try, record.ShortID, saveErr)
+		inserted = saveErr == nil
 	}
 
 	if !inserted {
 		return ShortenURLResponse{}, NewError(
 			ShortenURLFailedToSave,
 			fmt.Sprintf("Failed to find a shortId after %d attempts", len(shortIDLengths)),
-			map[string]string{"error": err.Error()},
+			map[string]string{"error": saveErr.Error()},
 		)
 	}
 
 	return s.buildShortenedURLResponse(shortReq, newRecord), nil
 }
 
-func (s *ShortenURLUseCase) buildShortenedURLResponse(shortReq ShortenURLRequest, urlRecord *u.URLRecord) ShortenURLResponse {
+func (s *ShortenURLUseCase) screen(longURL *url.URL) domain.Err {
+	return screenURL(s.screener, longURL)
+}
+
+// screenURL rejects long urls that the screener considers malicious
+func screenURL(screener URLScreener, longURL *url.URL) domain.Err {
+	if screener == nil {
+		return nil
+	}
+
+	verdict, err := screener.Screen(longURL)
+	if err != nil {
+		// Screening is best-effort; an unavailable screener should not stop urls from being shortened
+		log.Printf("Failed to screen '%s': %s", longURL, err)
+		return nil
+	}
+
+	if verdict.Malicious {
+		return NewError(
+			ShortenURLMalicious,
+			fmt.Sprintf("'%s' has been reported as malicious", longURL),
+			map[string]string{"reason": verdict.Reason},
+		)
+	}
+	return nil
+}
+
+func (s *ShortenURLUseCase) resolveSelfLink(longURL *url.URL) (*url.URL, domain.Err) {
+	if !s.selfLinks.IsSelfLink(longURL) {
+		return longURL, nil
+	}
+
+	if s.selfLinks.Policy == SelfLinkReject {
+		return nil, NewError(
+			ShortenURLSelfLink,
+			fmt.Sprintf("'%s' points at this url shortener. Shorten the original url instead", longURL),
+			nil,
+		)
+	}
+
+	resolvedURL, err := s.selfLinks.Resolve(s.repo, longURL, Visitor{})
+	if err == errRedirectLoop {
+		return nil, NewError(
+			ShortenURLRedirectLoop,
+			fmt.Sprintf("'%s' redirects more than %d times or loops", longURL, s.selfLinks.MaxDepth),
+			nil,
+		)
+	}
+	if err != nil {
+		return nil, NewError(
+			ShortenURLSelfLink,
+			fmt.Sprintf("'%s' points at this url shortener but could not be resolved", longURL),
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	log.Printf("Resolved self-link '%s' to '%s'", longURL, resolvedURL)
+	return resolvedURL, nil
+}
+
+func (s *ShortenURLUseCase) findExistingRecord(shortReq ShortenURLRequest) *u.URLRecord {
+	scope, ok := dedupeScopeOf(shortReq)
+	if !ok {
+		return nil
+	}
+
+	var record *u.URLRecord
+	if scope.perOwner {
+		record, _ = s.repo.ShortURLForOwner(scope.domain, scope.longURL, scope.owner)
+	} else {
+		record, _ = s.repo.ShortURL(scope.domain, scope.longURL)
+	}
+	return record
+}
+
+// dedupeScope identifies the records that a request can share: records for the same long url on the same domain,
+// that belong to the same owner if the request is deduplicated per owner
+type dedupeScope struct {
+	domain   string
+	longURL  string
+	owner    string
+	perOwner bool
+}
+
+// key identifies the scope, e.g. to find the requests of a batch that share a record
+func (d dedupeScope) key() string {
+	if d.perOwner {
+		return d.owner + " " + d.domain + " " + d.longURL
+	}
+	return d.domain + " " + d.longURL
+}
 
-	shortURL := &url.URL{
-		Scheme: s.baseURL.Scheme,
-		Host:   s.baseURL.Host,
-		Path:   urlRecord.ShortID,
+// dedupeScopeOf returns the records that the request can share, or returns false if the request needs a record of its own
+func dedupeScopeOf(shortReq ShortenURLRequest) (dedupeScope, bool) {
+	// A link with targeting rules or split destinations sends visitors elsewhere than an existing link for the same long url.
+	// A link with its own social metadata unfurls differently, and a tagged link belongs to its own campaign.
+	if len(shortReq.Targeting) > 0 || len(shortReq.Destinations) > 0 || shortReq.Social != nil || len(shortReq.Tags) > 0 || len(shortReq.Metadata) > 0 {
+		return dedupeScope{}, false
+	}
+	// A custom shortId is honored, rather than being swapped for the shortId of an existing link
+	if shortReq.UserDidSpecifyShortId() || shortReq.DedupeMode() == DedupeNever {
+		return dedupeScope{}, false
 	}
 
+	// Links on different domains are never shared
+	return dedupeScope{
+		domain:   domainKey(shortReq.baseURL),
+		longURL:  shortReq.parsedURL.String(),
+		owner:    shortReq.Owner,
+		perOwner: shortReq.DedupeMode() == DedupePerOwner,
+	}, true
+}
+
+// shortURLFor returns the short url of the shortId, keeping the path of the base url
+// e.g. `https://corp.example/go/abc` for the base url `https://corp.example/go/`
+func shortURLFor(baseURL *url.URL, shortID string) *url.URL {
+	return &url.URL{
+		Scheme: baseURL.Scheme,
+		Host:   baseURL.Host,
+		Path:   strings.TrimSuffix(baseURL.Path, "/") + "/" + shortID,
+	}
+}
+
+func (s *ShortenURLUseCase) buildShortenedURLResponse(shortReq ShortenURLRequest, urlRecord *u.URLRecord) ShortenURLResponse {
+
+	shortURL := shortURLFor(shortReq.baseURL, urlRecord.ShortID)
+
 	return ShortenURLResponse{
-		LongURL:  shortReq.LongURL,
-		ShortURL: shortURL.String(),
+		LongURL:        shortReq.LongURL,
+		ShortURL:       shortURL.String(),
+		RedirectStatus: urlRecord.RedirectStatus,
+		UTM:            shortReq.UTM,
+		Targeting:      fromTargetingRecords(urlRecord.TargetingRules),
+		Destinations:   fromDestinationRecords(urlRecord.Destinations),
+		Social:         fromSocialRecord(urlRecord.Social),
+		Tags:           urlRecord.Tags,
+		Metadata:       urlRecord.Metadata,
 	}
 }
@@ -1,33 +1,74 @@
 package usecase
 
 import (
-	"encoding/json"
 	"fmt"
+	"github.com/w-k-s/short-url/codec"
 	"github.com/w-k-s/short-url/domain"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
+// DedupeMode determines whether an existing record for the same long url
+// is returned instead of creating a new short id.
+type DedupeMode string
+
+const (
+	// DedupeGlobal returns any existing record for the long url.
+	DedupeGlobal DedupeMode = "global"
+	// DedupePerOwner returns an existing record only if it belongs to the same owner.
+	DedupePerOwner DedupeMode = "per-owner"
+	// DedupeNever always creates a new record.
+	DedupeNever DedupeMode = "never"
+)
+
+// IdempotencyKeyHeader is the header that clients send a unique key in, so that retries of a request are only shortened once
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength is the length of the key column of idempotency_keys
+const maxIdempotencyKeyLength = 255
+
 type ShortenURLRequest struct {
-	LongURL   string `json:"longUrl"`
-	ShortID   string `json:"ShortId"`
-	parsedURL *url.URL
+	LongURL        string          `json:"longUrl" xml:"longUrl"`
+	ShortID        string          `json:"ShortId" xml:"shortId" msgpack:"shortId"`
+	Owner          string          `json:"owner" xml:"owner"`
+	Dedupe         DedupeMode      `json:"dedupe" xml:"dedupe"`
+	RedirectStatus int             `json:"redirectStatus" xml:"redirectStatus"`
+	QueryMerge     QueryMerge      `json:"queryMerge" xml:"queryMerge"`
+	Wildcard       bool            `json:"wildcard" xml:"wildcard"`
+	UTM            *UTMParameters  `json:"utm" xml:"utm"`
+	Targeting      []TargetingRule `json:"targeting" xml:"targeting>item"`
+	Destinations   []Destination   `json:"destinations" xml:"destinations>item"`
+	Social         *SocialMetadata `json:"social" xml:"social"`
+	Tags           []string        `json:"tags" xml:"tags>item"`
+	Metadata       codec.StringMap `json:"metadata" xml:"metadata"`
+	// Domain is the host of the branded domain to shorten the url on. The domain of the base url is used if it is empty.
+	Domain string `json:"domain" xml:"domain"`
+	// IdempotencyKey is sent in the Idempotency-Key header rather than in the body
+	IdempotencyKey string `json:"-" xml:"-"`
+	parsedURL      *url.URL
+	// baseURL is the base url of the domain, once the request is prepared
+	baseURL *url.URL
 }
 
 func NewShortenURLRequest(req *http.Request) (ShortenURLRequest, domain.Err) {
 
-	decoder := json.NewDecoder(req.Body)
-
 	var shortenReq ShortenURLRequest
-	err := decoder.Decode(&shortenReq)
+	err := codec.DecodeBody(req, &shortenReq)
 	if err != nil {
 		return ShortenURLRequest{}, NewError(
 			ShortenURLDecoding,
-			"JSON Body must include `longUrl`",
+			"Body must include `longUrl`",
 			map[string]string{"error": err.Error()},
 		)
 	}
+	shortenReq.IdempotencyKey = req.Header.Get(IdempotencyKeyHeader)
+
+	return ValidateShortenURLRequest(shortenReq)
+}
 
+// ValidateShortenURLRequest validates a request that was decoded by the caller (e.g. from gRPC) and parses its long url
+func ValidateShortenURLRequest(shortenReq ShortenURLRequest) (ShortenURLRequest, domain.Err) {
 	rawURL, err := url.Parse(shortenReq.LongURL)
 	if err != nil {
 		return ShortenURLRequest{}, NewError(
@@ -45,10 +86,87 @@ func NewShortenURLRequest(req *http.Request) (ShortenURLRequest, domain.Err) {
 		)
 	}
 
+	switch shortenReq.Dedupe {
+	case "", DedupeGlobal, DedupeNever:
+	case DedupePerOwner:
+		if len(shortenReq.Owner) == 0 {
+			return ShortenURLRequest{}, NewError(
+				ShortenURLValidation,
+				fmt.Sprintf("`owner` is required when `dedupe` is '%s'", DedupePerOwner),
+				nil,
+			)
+		}
+	default:
+		return ShortenURLRequest{}, NewError(
+			ShortenURLValidation,
+			fmt.Sprintf("'%s' is not a valid dedupe mode. Expected one of '%s', '%s' or '%s'", shortenReq.Dedupe, DedupeGlobal, DedupePerOwner, DedupeNever),
+			nil,
+		)
+	}
+
+	if err := validateRedirectStatus(ShortenURLValidation, shortenReq.RedirectStatus); err != nil {
+		return ShortenURLRequest{}, err
+	}
+
+	if err := validateQueryMerge(ShortenURLValidation, shortenReq.QueryMerge); err != nil {
+		return ShortenURLRequest{}, err
+	}
+
+	if shortenReq.UTM != nil {
+		if err := shortenReq.UTM.Validate(); err != nil {
+			return ShortenURLRequest{}, err
+		}
+	}
+
+	if err := validateTargetingRules(ShortenURLValidation, shortenReq.Targeting); err != nil {
+		return ShortenURLRequest{}, err
+	}
+
+	if err := validateDestinations(ShortenURLValidation, shortenReq.Destinations); err != nil {
+		return ShortenURLRequest{}, err
+	}
+
+	if shortenReq.Social != nil {
+		if err := shortenReq.Social.Validate(ShortenURLValidation); err != nil {
+			return ShortenURLRequest{}, err
+		}
+	}
+
+	tags, tagsErr := normalizeTags(ShortenURLValidation, shortenReq.Tags)
+	if tagsErr != nil {
+		return ShortenURLRequest{}, tagsErr
+	}
+
+	if err := validateMetadata(ShortenURLValidation, shortenReq.Metadata); err != nil {
+		return ShortenURLRequest{}, err
+	}
+
+	idempotencyKey := strings.TrimSpace(shortenReq.IdempotencyKey)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return ShortenURLRequest{}, NewError(
+			ShortenURLValidation,
+			fmt.Sprintf("`%s` must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength),
+			nil,
+		)
+	}
+
 	return ShortenURLRequest{
-		LongURL:   shortenReq.LongURL,
-		ShortID:   shortenReq.ShortID,
-		parsedURL: rawURL,
+		LongURL:        shortenReq.LongURL,
+		ShortID:        shortenReq.ShortID,
+		Owner:          shortenReq.Owner,
+		Dedupe:         shortenReq.Dedupe,
+		RedirectStatus: shortenReq.RedirectStatus,
+		QueryMerge:     shortenReq.QueryMerge,
+		Wildcard:       shortenReq.Wildcard,
+		UTM:            shortenReq.UTM,
+		Targeting:      shortenReq.Targeting,
+		Destinations:   shortenReq.Destinations,
+		Social:         shortenReq.Social,
+		Tags:           tags,
+		Metadata:       shortenReq.Metadata,
+		Domain:         strings.ToLower(strings.TrimSpace(shortenReq.Domain)),
+		IdempotencyKey: idempotencyKey,
+		parsedURL:      rawURL,
 	}, nil
 }
 
@@ -56,6 +174,14 @@ func (s ShortenURLRequest) UserDidSpecifyShortId() bool {
 	return len(s.ShortID) > 0
 }
 
+// DedupeMode returns the requested dedupe mode, defaulting to DedupeGlobal.
+func (s ShortenURLRequest) DedupeMode() DedupeMode {
+	if len(s.Dedupe) == 0 {
+		return DedupeGlobal
+	}
+	return s.Dedupe
+}
+
 func (s ShortenURLRequest) ParsedURL() *url.URL {
 	return s.parsedURL
 }
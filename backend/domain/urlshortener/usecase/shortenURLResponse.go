@@ -1,6 +1,17 @@
 package usecase
 
+import "github.com/w-k-s/short-url/codec"
+
 type ShortenURLResponse struct {
-	LongURL  string `json:"longUrl"`
-	ShortURL string `json:"shortUrl"`
+	LongURL        string          `json:"longUrl" xml:"longUrl"`
+	ShortURL       string          `json:"shortUrl" xml:"shortUrl"`
+	RedirectStatus int             `json:"redirectStatus,omitempty" xml:"redirectStatus,omitempty"`
+	UTM            *UTMParameters  `json:"utm,omitempty" xml:"utm,omitempty"`
+	Targeting      []TargetingRule `json:"targeting,omitempty" xml:"targeting>item,omitempty"`
+	Destinations   []Destination   `json:"destinations,omitempty" xml:"destinations>item,omitempty"`
+	Social         *SocialMetadata `json:"social,omitempty" xml:"social,omitempty"`
+	Tags           []string        `json:"tags,omitempty" xml:"tags>item,omitempty"`
+	Metadata       codec.StringMap `json:"metadata,omitempty" xml:"metadata,omitempty"`
+	// Replayed is true if the response was saved for the idempotency key of an earlier request
+	Replayed bool `json:"-" xml:"-"`
 }
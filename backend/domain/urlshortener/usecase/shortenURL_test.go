@@ -34,6 +34,16 @@ type MockURLRepository struct {
 
 	ShortURLRecordResult *u.URLRecord
 	ShortURLRecordError  error
+
+	ShortURLForOwnerRecordResult *u.URLRecord
+	ShortURLForOwnerRecordError  error
+
+	UpdateRecordError error
+
+	ListRecordsResult []*u.URLRecord
+	ListRecordsError  error
+	// ListRecordsQuery, if set, receives the query that records were listed with
+	ListRecordsQuery *u.RecordQuery
 }
 
 func (m MockURLRepository) SaveRecord(record *u.URLRecord) (*u.URLRecord, error) {
@@ -43,20 +53,77 @@ func (m MockURLRepository) SaveRecord(record *u.URLRecord) (*u.URLRecord, error)
 	return m.SaveURLRecordResult, nil
 }
 
-func (m MockURLRepository) LongURL(shortID string) (*u.URLRecord, error) {
+func (m MockURLRepository) SaveRecords(records []*u.URLRecord) ([]*u.URLRecord, error) {
+	if m.ReturnError {
+		return nil, m.SaveURLRecordError
+	}
+	return records, nil
+}
+
+func (m MockURLRepository) ListRecords(query u.RecordQuery) ([]*u.URLRecord, error) {
+	if m.ListRecordsQuery != nil {
+		*m.ListRecordsQuery = query
+	}
+	if m.ReturnError {
+		return nil, m.ListRecordsError
+	}
+	return m.ListRecordsResult, nil
+}
+
+func (m MockURLRepository) LongURL(domain string, shortID string) (*u.URLRecord, error) {
 	if m.ReturnError {
 		return nil, m.LongURLRecordError
 	}
 	return m.LongURLRecordResult, nil
 }
 
-func (m MockURLRepository) ShortURL(longURL string) (*u.URLRecord, error) {
+func (m MockURLRepository) ShortURL(domain string, longURL string) (*u.URLRecord, error) {
 	if m.ReturnError {
 		return nil, m.ShortURLRecordError
 	}
 	return m.ShortURLRecordResult, nil
 }
 
+func (m MockURLRepository) ShortURLForOwner(domain string, longURL string, owner string) (*u.URLRecord, error) {
+	if m.ReturnError {
+		return nil, m.ShortURLForOwnerRecordError
+	}
+	return m.ShortURLForOwnerRecordResult, nil
+}
+
+func (m MockURLRepository) UpdateRecord(record *u.URLRecord) error {
+	if m.ReturnError {
+		return m.UpdateRecordError
+	}
+	return nil
+}
+
+//-- MockAnalyticsRepository
+
+type MockAnalyticsRepository struct {
+	ReturnError bool
+
+	SavedClicks      []u.Click
+	SaveClickError   error
+	ClickCountsValue u.ClickCounts
+	ClickCountsError error
+}
+
+func (m *MockAnalyticsRepository) SaveClick(click *u.Click) error {
+	if m.ReturnError {
+		return m.SaveClickError
+	}
+	m.SavedClicks = append(m.SavedClicks, *click)
+	return nil
+}
+
+func (m *MockAnalyticsRepository) ClickCounts(domain string, shortID string) (u.ClickCounts, error) {
+	if m.ReturnError {
+		return nil, m.ClickCountsError
+	}
+	return m.ClickCountsValue, nil
+}
+
 //-----
 
 const savedShortID = "shrt"
@@ -131,6 +198,75 @@ func (suite *ShortenURLUseCaseTestSuite) TestGivenRecordDoesNotExists_WhenShorte
 	assert.Equal(suite.T(), expectation, response.ShortURL, "ShortenURL generates wrong url. Expected '%s'. Got: %s", expectation, response.ShortURL)
 }
 
+func (suite *ShortenURLUseCaseTestSuite) TestGivenBrandedDomain_WhenShorteningURL_ThenShortURLOnBrandedDomain() {
+
+	//Given
+	suite.generator.ShortID = "alpha"
+	testURL, _ := url.Parse("http://www.1.com")
+	baseURL, _ := url.Parse(baseURLString)
+	brandedURL, _ := url.Parse("https://go.brand.com")
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{
+		LongURL:    "http://www.1.com",
+		Domain:     "go.brand.com",
+		ShortID:    suite.generator.ShortID,
+		CreateTime: time.Now(),
+	}
+	useCase := NewShortenURLUseCase(suite.urlRepo, baseURL, suite.generator, WithDomains(NewDomains(baseURL, []*url.URL{brandedURL}, nil)))
+
+	//When
+	response, err := useCase.Execute(ShortenURLRequest{
+		LongURL:   "http://www.1.com",
+		Domain:    "go.brand.com",
+		parsedURL: testURL,
+	})
+
+	//Then
+	assert.Nil(suite.T(), err, "ShortenURL: Expected no error, got %v", err)
+	assert.Equal(suite.T(), "https://go.brand.com/alpha", response.ShortURL)
+}
+
+func (suite *ShortenURLUseCaseTestSuite) TestGivenBaseURLWithPath_WhenShorteningURL_ThenShortURLKeepsPath() {
+
+	//Given
+	suite.generator.ShortID = "alpha"
+	testURL, _ := url.Parse("http://www.1.com")
+	baseURL, _ := url.Parse("https://corp.example/go/")
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{
+		LongURL:    "http://www.1.com",
+		ShortID:    suite.generator.ShortID,
+		CreateTime: time.Now(),
+	}
+	useCase := NewShortenURLUseCase(suite.urlRepo, baseURL, suite.generator)
+
+	//When
+	response, err := useCase.Execute(ShortenURLRequest{
+		LongURL:   "http://www.1.com",
+		parsedURL: testURL,
+	})
+
+	//Then
+	assert.Nil(suite.T(), err, "ShortenURL: Expected no error, got %v", err)
+	assert.Equal(suite.T(), "https://corp.example/go/alpha", response.ShortURL)
+}
+
+func (suite *ShortenURLUseCaseTestSuite) TestGivenUnknownDomain_WhenShorteningURL_ThenReturnError() {
+
+	//Given
+	testURL, _ := url.Parse("http://www.1.com")
+
+	//When
+	_, err := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   "http://www.1.com",
+		Domain:    "go.unknown.com",
+		parsedURL: testURL,
+	})
+
+	//Then
+	expectation := ShortenURLDomainNotAllowed
+	assert.NotNil(suite.T(), err, "ShortenURL: Expected error, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "ShortenURL wrong error code. Expected '%d'. Got: %d", expectation, int(err.Code()))
+}
+
 func (suite *ShortenURLUseCaseTestSuite) TestGivenShortIDProvided_WhenShortIDNotInUse_ThenProvidedShortIDUsed() {
 
 	//Given
@@ -159,20 +295,46 @@ func (suite *ShortenURLUseCaseTestSuite) TestGivenShortIDProvided_WhenShortIDInU
 
 	//Given
 	suite.generator.ShortID = "NotUsed"
-	testURL, _ := url.Parse("http://www.1.com")
+	testURL, _ := url.Parse(savedLongURL)
+	suite.urlRepo.ReturnError = true
+	suite.urlRepo.SaveURLRecordError = errors.New("short id exists")
+
+	//When
+	response, err := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		ShortID:   "InUse",
+		parsedURL: testURL,
+	})
+
+	//Then
+	expectation := ShortenURLShortIDInUse
+	assert.NotNil(suite.T(), err, "ShortenURL: Expected Error, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "ShortenURL generates wrong error code. Expected '%v'. Got: %v", expectation, err.Code())
+	assert.Empty(suite.T(), response.ShortURL, "ShortenURL returned the existing short url instead of rejecting the provided shortId")
+}
+
+func (suite *ShortenURLUseCaseTestSuite) TestGivenShortIDProvided_WhenLongURLAlreadyShortened_ThenProvidedShortIDUsed() {
+
+	//Given
+	testURL, _ := url.Parse(savedLongURL)
 	suite.urlRepo.ShortURLRecordResult = suite.record
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{
+		LongURL:    savedLongURL,
+		ShortID:    "campaign",
+		CreateTime: time.Now(),
+	}
 
 	//When
-	response, _ := suite.useCase.Execute(ShortenURLRequest{
-		LongURL:   "http://www.1.ml",
-		ShortID:   "NotUsed",
+	response, err := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		ShortID:   "campaign",
 		parsedURL: testURL,
 	})
 
 	//Then
-	expectation := savedShortURL
+	expectation := baseURLString + "campaign"
+	assert.Nil(suite.T(), err, "ShortenURL: Expected no error, got %v", err)
 	assert.Equal(suite.T(), expectation, response.ShortURL, "ShortenURL generates wrong url. Expected '%s'. Got: %s", expectation, response.ShortURL)
-
 }
 
 func (suite *ShortenURLUseCaseTestSuite) TestGivenShortIDProvided_WhenShortIDNotUnique_ThenErrorReturned() {
@@ -215,3 +377,191 @@ func (suite *ShortenURLUseCaseTestSuite) TestGivenShortIDIsGenerated_WhenShortID
 	assert.NotNil(suite.T(), err, "ShortenURL: Expected Error, got nil")
 	assert.Equal(suite.T(), expectation, int(err.Code()), "ShortenURL wrong error code. Expected '%d'. Got: %d", expectation, err)
 }
+
+func (suite *ShortenURLUseCaseTestSuite) TestGivenDedupeNever_WhenRecordExists_ThenProvidedShortIDUsed() {
+
+	//Given
+	testURL, _ := url.Parse(savedLongURL)
+	suite.urlRepo.ShortURLRecordResult = suite.record
+	suite.urlRepo.ShortURLForOwnerRecordResult = suite.record
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{
+		LongURL:    savedLongURL,
+		ShortID:    "campaign",
+		CreateTime: time.Now(),
+	}
+
+	//When
+	response, _ := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		ShortID:   "campaign",
+		Dedupe:    DedupeNever,
+		parsedURL: testURL,
+	})
+
+	//Then
+	expectation := baseURLString + "campaign"
+	assert.Equal(suite.T(), expectation, response.ShortURL, "ShortenURL generates wrong url. Expected '%s'. Got: %s", expectation, response.ShortURL)
+}
+
+func (suite *ShortenURLUseCaseTestSuite) TestGivenDedupePerOwner_WhenOwnerHasRecord_ThenExistingRecordReturned() {
+
+	//Given
+	testURL, _ := url.Parse(savedLongURL)
+	suite.urlRepo.ShortURLForOwnerRecordResult = suite.record
+
+	//When
+	response, _ := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		Owner:     "marketing",
+		Dedupe:    DedupePerOwner,
+		parsedURL: testURL,
+	})
+
+	//Then
+	expectation := savedShortURL
+	assert.Equal(suite.T(), expectation, response.ShortURL, "ShortenURL generates wrong url. Expected '%s'. Got: %s", expectation, response.ShortURL)
+}
+
+func (suite *ShortenURLUseCaseTestSuite) TestGivenDedupePerOwner_WhenOnlyAnotherOwnerHasRecord_ThenRecordCreated() {
+
+	//Given
+	suite.generator.ShortID = "alpha"
+	testURL, _ := url.Parse(savedLongURL)
+	suite.urlRepo.ShortURLRecordResult = suite.record
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{
+		LongURL:    savedLongURL,
+		ShortID:    suite.generator.ShortID,
+		Owner:      "marketing",
+		CreateTime: time.Now(),
+	}
+
+	//When
+	response, _ := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		Owner:     "marketing",
+		Dedupe:    DedupePerOwner,
+		parsedURL: testURL,
+	})
+
+	//Then
+	expectation := baseURLString + suite.generator.ShortID
+	assert.Equal(suite.T(), expectation, response.ShortURL, "ShortenURL generates wrong url. Expected '%s'. Got: %s", expectation, response.ShortURL)
+}
+
+func (suite *ShortenURLUseCaseTestSuite) TestGivenDeniedScheme_WhenShorteningURL_ThenReturnError() {
+
+	//Given
+	testURL, _ := url.Parse("javascript:alert(document.cookie)")
+
+	//When
+	_, err := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   testURL.String(),
+		parsedURL: testURL,
+	})
+
+	//Then
+	expectation := ShortenURLSchemeNotAllowed
+	assert.NotNil(suite.T(), err, "ShortenURL: Expected Error, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "ShortenURL wrong error code. Expected '%d'. Got: %d", expectation, err)
+}
+
+//-- MockURLScreener
+
+type MockURLScreener struct {
+	Verdict Verdict
+	Error   error
+}
+
+func (m MockURLScreener) Screen(longURL *url.URL) (Verdict, error) {
+	return m.Verdict, m.Error
+}
+
+func (suite *ShortenURLUseCaseTestSuite) TestGivenMaliciousURL_WhenShorteningURL_ThenReturnError() {
+
+	//Given
+	baseURL, _ := url.Parse(baseURLString)
+	screener := MockURLScreener{Verdict: Verdict{Malicious: true, Reason: "phishing"}}
+	useCase := NewShortenURLUseCase(suite.urlRepo, baseURL, suite.generator, WithURLScreener(screener))
+	testURL, _ := url.Parse("http://phishing.example.com")
+
+	//When
+	_, err := useCase.Execute(ShortenURLRequest{
+		LongURL:   testURL.String(),
+		parsedURL: testURL,
+	})
+
+	//Then
+	expectation := ShortenURLMalicious
+	assert.NotNil(suite.T(), err, "ShortenURL: Expected Error, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "ShortenURL wrong error code. Expected '%d'. Got: %d", expectation, err)
+	assert.Equal(suite.T(), "phishing", err.Fields()["reason"])
+}
+
+func (suite *ShortenURLUseCaseTestSuite) TestGivenScreenerUnavailable_WhenShorteningURL_ThenRecordCreated() {
+
+	//Given
+	baseURL, _ := url.Parse(baseURLString)
+	screener := MockURLScreener{Error: errors.New("unavailable")}
+	useCase := NewShortenURLUseCase(suite.urlRepo, baseURL, suite.generator, WithURLScreener(screener))
+	suite.generator.ShortID = "alpha"
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{
+		LongURL:    savedLongURL,
+		ShortID:    suite.generator.ShortID,
+		CreateTime: time.Now(),
+	}
+	testURL, _ := url.Parse(savedLongURL)
+
+	//When
+	response, err := useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		parsedURL: testURL,
+	})
+
+	//Then
+	expectation := baseURLString + suite.generator.ShortID
+	assert.Nil(suite.T(), err, "ShortenURL: Expected no error, got %v", err)
+	assert.Equal(suite.T(), expectation, response.ShortURL, "ShortenURL generates wrong url. Expected '%s'. Got: %s", expectation, response.ShortURL)
+}
+
+func (suite *ShortenURLUseCaseTestSuite) TestGivenRedirectStatus_WhenShorteningURL_ThenRedirectStatusSaved() {
+
+	//Given
+	suite.generator.ShortID = "alpha"
+	testURL, _ := url.Parse(savedLongURL)
+	suite.urlRepo.SaveURLRecordResult = &u.URLRecord{
+		LongURL:        savedLongURL,
+		ShortID:        suite.generator.ShortID,
+		CreateTime:     time.Now(),
+		RedirectStatus: 308,
+	}
+
+	//When
+	response, _ := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:        savedLongURL,
+		RedirectStatus: 308,
+		parsedURL:      testURL,
+	})
+
+	//Then
+	assert.Equal(suite.T(), 308, response.RedirectStatus)
+}
+
+func (suite *ShortenURLUseCaseTestSuite) TestGivenUTMParameters_WhenShorteningURL_ThenParametersAddedToLongURL() {
+
+	//Given
+	testURL, _ := url.Parse(savedLongURL)
+	suite.urlRepo.ShortURLRecordResult = suite.record
+	utm := &UTMParameters{Source: "newsletter", Medium: "email", Campaign: "spring"}
+
+	//When
+	response, _ := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		UTM:       utm,
+		parsedURL: testURL,
+	})
+
+	//Then
+	expectation := savedLongURL + "?utm_campaign=spring&utm_medium=email&utm_source=newsletter"
+	assert.Equal(suite.T(), expectation, response.LongURL)
+	assert.Equal(suite.T(), utm, response.UTM)
+}
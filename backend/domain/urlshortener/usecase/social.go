@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"github.com/w-k-s/short-url/log"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxSocialTitleLength       = 300
+	MaxSocialDescriptionLength = 1000
+)
+
+// SocialMetadata is the title, description and image shown when a short url is unfurled in chat apps and social networks
+type SocialMetadata struct {
+	Title       string `json:"title,omitempty" xml:"title,omitempty"`
+	Description string `json:"description,omitempty" xml:"description,omitempty"`
+	Image       string `json:"image,omitempty" xml:"image,omitempty"`
+}
+
+// MetadataFetcher reads the OpenGraph and Twitter card metadata of a web page
+type MetadataFetcher interface {
+	Fetch(longURL *url.URL) (SocialMetadata, error)
+}
+
+// Validate checks the lengths of the title and description and that the image is an absolute http(s) url
+func (m SocialMetadata) Validate(code domain.Code) domain.Err {
+	if utf8.RuneCountInString(m.Title) > MaxSocialTitleLength {
+		return NewError(
+			code,
+			fmt.Sprintf("The social `title` can be at most %d characters long", MaxSocialTitleLength),
+			nil,
+		)
+	}
+	if utf8.RuneCountInString(m.Description) > MaxSocialDescriptionLength {
+		return NewError(
+			code,
+			fmt.Sprintf("The social `description` can be at most %d characters long", MaxSocialDescriptionLength),
+			nil,
+		)
+	}
+	if len(m.Image) > 0 {
+		image, err := url.Parse(m.Image)
+		if err != nil || !image.IsAbs() || (image.Scheme != "http" && image.Scheme != "https") {
+			return NewError(
+				code,
+				fmt.Sprintf("The social `image` '%s' is not an absolute http(s) url", m.Image),
+				nil,
+			)
+		}
+	}
+	return nil
+}
+
+func (m SocialMetadata) isEmpty() bool {
+	return len(m.Title) == 0 && len(m.Description) == 0 && len(m.Image) == 0
+}
+
+func (m SocialMetadata) isComplete() bool {
+	return len(m.Title) > 0 && len(m.Description) > 0 && len(m.Image) > 0
+}
+
+// orElse fills the fields that are empty with those of the fallback
+func (m SocialMetadata) orElse(fallback SocialMetadata) SocialMetadata {
+	if len(m.Title) == 0 {
+		m.Title = fallback.Title
+	}
+	if len(m.Description) == 0 {
+		m.Description = fallback.Description
+	}
+	if len(m.Image) == 0 {
+		m.Image = fallback.Image
+	}
+	return m
+}
+
+// socialMetadataFor returns the overrides, completed with the metadata fetched from the long url.
+// Fetching is best-effort; a page that can not be fetched is shared without the missing metadata.
+func socialMetadataFor(fetcher MetadataFetcher, longURL *url.URL, overrides *SocialMetadata) SocialMetadata {
+	var metadata SocialMetadata
+	if overrides != nil {
+		metadata = *overrides
+	}
+	if fetcher == nil || metadata.isComplete() {
+		return metadata
+	}
+
+	fetched, err := fetcher.Fetch(longURL)
+	if err != nil {
+		log.Printf("Failed to fetch social metadata of '%s': %s", longURL, err)
+		return metadata
+	}
+
+	// Pages are not trusted to respect the limits that apply to overrides
+	fetched.Title = truncate(fetched.Title, MaxSocialTitleLength)
+	fetched.Description = truncate(fetched.Description, MaxSocialDescriptionLength)
+	if fetched.Validate(ShortenURLValidation) != nil {
+		fetched.Image = ""
+	}
+	return metadata.orElse(fetched)
+}
+
+func truncate(text string, maxLength int) string {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:maxLength-1])) + "…"
+}
+
+func toSocialRecord(metadata SocialMetadata) u.SocialMetadata {
+	return u.SocialMetadata{
+		Title:       metadata.Title,
+		Description: metadata.Description,
+		ImageURL:    metadata.Image,
+	}
+}
+
+func fromSocialRecord(record u.SocialMetadata) *SocialMetadata {
+	metadata := SocialMetadata{
+		Title:       record.Title,
+		Description: record.Description,
+		Image:       record.ImageURL,
+	}
+	if metadata.isEmpty() {
+		return nil
+	}
+	return &metadata
+}
+
+// crawlers are the User-Agent tokens of the bots that chat apps and social networks use to unfurl links
+var crawlers = []string{
+	"facebookexternalhit",
+	"facebookcatalog",
+	"facebot",
+	"twitterbot",
+	"linkedinbot",
+	"slackbot",
+	"slack-imgproxy",
+	"discordbot",
+	"telegrambot",
+	"whatsapp",
+	"skypeuripreview",
+	"pinterest",
+	"redditbot",
+	"embedly",
+	"iframely",
+	"vkshare",
+	"mattermost",
+	"google-pagerenderer",
+	"applebot",
+}
+
+// IsCrawler returns true if the User-Agent belongs to a bot that unfurls links for chat apps or social networks
+func IsCrawler(userAgent string) bool {
+	userAgent = strings.ToLower(userAgent)
+	for _, crawler := range crawlers {
+		if strings.Contains(userAgent, crawler) {
+			return true
+		}
+	}
+	return false
+}
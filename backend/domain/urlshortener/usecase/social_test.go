@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"github.com/w-k-s/short-url/log"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+//-- MockMetadataFetcher
+
+type MockMetadataFetcher struct {
+	Metadata SocialMetadata
+	Error    error
+	Fetched  []string
+}
+
+func (m *MockMetadataFetcher) Fetch(longURL *url.URL) (SocialMetadata, error) {
+	m.Fetched = append(m.Fetched, longURL.String())
+	return m.Metadata, m.Error
+}
+
+//-- SavingURLRepository
+
+// SavingURLRepository returns the record it is asked to save so that saved fields can be tested
+type SavingURLRepository struct {
+	MockURLRepository
+}
+
+func (m SavingURLRepository) SaveRecord(record *u.URLRecord) (*u.URLRecord, error) {
+	return record, nil
+}
+
+type SocialMetadataTestSuite struct {
+	suite.Suite
+	fetcher *MockMetadataFetcher
+	useCase *ShortenURLUseCase
+	longURL *url.URL
+}
+
+func TestSocialMetadataTestSuite(t *testing.T) {
+	suite.Run(t, new(SocialMetadataTestSuite))
+}
+
+func (suite *SocialMetadataTestSuite) SetupTest() {
+	log.Init()
+
+	baseURL, _ := url.Parse(baseURLString)
+	repo := SavingURLRepository{}
+	suite.fetcher = &MockMetadataFetcher{
+		Metadata: SocialMetadata{
+			Title:       "Fetched title",
+			Description: "Fetched description",
+			Image:       "https://www.example.com/card.png",
+		},
+	}
+	suite.useCase = NewShortenURLUseCase(repo, baseURL, MockShortIDGenerator{ShortID: "alpha"}, WithMetadataFetcher(suite.fetcher))
+	suite.longURL, _ = url.Parse(savedLongURL)
+}
+
+func (suite *SocialMetadataTestSuite) TestGivenOverrides_WhenShorteningURL_ThenOverridesCompletedWithFetchedMetadata() {
+
+	//When
+	response, err := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		Social:    &SocialMetadata{Title: "Our title"},
+		parsedURL: suite.longURL,
+	})
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []string{savedLongURL}, suite.fetcher.Fetched)
+	assert.Equal(suite.T(), &SocialMetadata{
+		Title:       "Our title",
+		Description: "Fetched description",
+		Image:       "https://www.example.com/card.png",
+	}, response.Social)
+}
+
+func (suite *SocialMetadataTestSuite) TestGivenCompleteOverrides_WhenShorteningURL_ThenPageNotFetched() {
+
+	//Given
+	overrides := &SocialMetadata{Title: "Title", Description: "Description", Image: "https://cdn.example.com/a.png"}
+
+	//When
+	response, err := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		Social:    overrides,
+		parsedURL: suite.longURL,
+	})
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), suite.fetcher.Fetched)
+	assert.Equal(suite.T(), overrides, response.Social)
+}
+
+func (suite *SocialMetadataTestSuite) TestGivenPageCanNotBeFetched_WhenShorteningURL_ThenRecordCreatedWithoutMetadata() {
+
+	//Given
+	suite.fetcher.Error = errors.New("timeout")
+
+	//When
+	response, err := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		parsedURL: suite.longURL,
+	})
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), baseURLString+"alpha", response.ShortURL)
+	assert.Nil(suite.T(), response.Social)
+}
+
+func (suite *SocialMetadataTestSuite) TestGivenFetchedMetadataExceedsLimits_WhenShorteningURL_ThenMetadataIsTruncated() {
+
+	//Given
+	suite.fetcher.Metadata = SocialMetadata{
+		Title: strings.Repeat("a", MaxSocialTitleLength+10),
+		Image: "javascript:alert(1)",
+	}
+
+	//When
+	response, err := suite.useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		parsedURL: suite.longURL,
+	})
+
+	//Then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), MaxSocialTitleLength, len([]rune(response.Social.Title)))
+	assert.True(suite.T(), strings.HasSuffix(response.Social.Title, "…"))
+	assert.Empty(suite.T(), response.Social.Image)
+}
+
+func TestSocialMetadataValidation(t *testing.T) {
+	assert.Nil(t, SocialMetadata{Title: "Title", Image: "https://www.example.com/a.png"}.Validate(ShortenURLValidation))
+	assert.NotNil(t, SocialMetadata{Image: "/a.png"}.Validate(ShortenURLValidation))
+	assert.NotNil(t, SocialMetadata{Image: "data:image/png;base64,AAAA"}.Validate(ShortenURLValidation))
+	assert.NotNil(t, SocialMetadata{Title: strings.Repeat("a", MaxSocialTitleLength+1)}.Validate(ShortenURLValidation))
+	assert.NotNil(t, SocialMetadata{Description: strings.Repeat("a", MaxSocialDescriptionLength+1)}.Validate(ShortenURLValidation))
+}
+
+func TestIsCrawler(t *testing.T) {
+	assert.True(t, IsCrawler("facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)"))
+	assert.True(t, IsCrawler("Twitterbot/1.0"))
+	assert.True(t, IsCrawler("Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)"))
+	assert.True(t, IsCrawler("WhatsApp/2.23.20.0 A"))
+	assert.True(t, IsCrawler("Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)"))
+	assert.False(t, IsCrawler("Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148"))
+	assert.False(t, IsCrawler(""))
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"hash/fnv"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// VisitorCookie remembers the visitor so that they are sent to the same destination on every visit
+const VisitorCookie = "surl_vid"
+
+// MaxDestinations is the maximum number of destinations a link can be split across
+const MaxDestinations = 10
+
+// MaxDestinationWeight is the maximum weight of a destination, so that the weights of a link add up to a small number
+const MaxDestinationWeight = 10000
+
+// Destination is one of several long urls that visitors are split across, in proportion to its weight.
+// e.g. destinations with weights 70 and 30 send 70% of visitors to the first long url.
+type Destination struct {
+	// Variant names the destination in click statistics. Defaults to "A", "B", "C", etc.
+	Variant string `json:"variant" xml:"variant"`
+	LongURL string `json:"longUrl" xml:"longUrl"`
+	Weight  int    `json:"weight" xml:"weight"`
+}
+
+// validateDestinations checks the destinations and names variants that were not named
+func validateDestinations(code domain.Code, destinations []Destination) domain.Err {
+	if len(destinations) == 0 {
+		return nil
+	}
+	if len(destinations) == 1 || len(destinations) > MaxDestinations {
+		return NewError(
+			code,
+			fmt.Sprintf("A link can be split across 2 to %d destinations", MaxDestinations),
+			nil,
+		)
+	}
+
+	variants := map[string]bool{}
+	for i := range destinations {
+		destination := &destinations[i]
+		if len(destination.Variant) == 0 {
+			destination.Variant = string(rune('A' + i))
+		}
+		if len(destination.Variant) > 64 {
+			return NewError(
+				code,
+				fmt.Sprintf("The variant '%s' is longer than 64 characters", destination.Variant),
+				nil,
+			)
+		}
+		if variants[destination.Variant] {
+			return NewError(
+				code,
+				fmt.Sprintf("The variant '%s' is used by more than one destination", destination.Variant),
+				nil,
+			)
+		}
+		variants[destination.Variant] = true
+
+		if destination.Weight < 1 || destination.Weight > MaxDestinationWeight {
+			return NewError(
+				code,
+				fmt.Sprintf("The weight of variant '%s' must be between 1 and %d", destination.Variant, MaxDestinationWeight),
+				nil,
+			)
+		}
+
+		longURL, err := url.Parse(destination.LongURL)
+		if err != nil || !longURL.IsAbs() {
+			return NewError(
+				code,
+				fmt.Sprintf("'%s' is not an absolute url", destination.LongURL),
+				nil,
+			)
+		}
+	}
+	return nil
+}
+
+func toDestinationRecords(destinations []Destination) []u.Destination {
+	if len(destinations) == 0 {
+		return nil
+	}
+	records := make([]u.Destination, 0, len(destinations))
+	for _, destination := range destinations {
+		records = append(records, u.Destination{
+			Variant: destination.Variant,
+			LongURL: destination.LongURL,
+			Weight:  destination.Weight,
+		})
+	}
+	return records
+}
+
+func fromDestinationRecords(records []u.Destination) []Destination {
+	if len(records) == 0 {
+		return nil
+	}
+	destinations := make([]Destination, 0, len(records))
+	for _, record := range records {
+		destinations = append(destinations, Destination{
+			Variant: record.Variant,
+			LongURL: record.LongURL,
+			Weight:  record.Weight,
+		})
+	}
+	return destinations
+}
+
+// pickDestination deterministically assigns the visitor to one of the record's destinations,
+// so that the same visitor is always sent to the same destination of a link.
+func pickDestination(record *u.URLRecord, visitorID string) (u.Destination, bool) {
+	totalWeight := 0
+	for _, destination := range record.Destinations {
+		totalWeight += destination.Weight
+	}
+	if totalWeight <= 0 || len(visitorID) == 0 {
+		return u.Destination{}, false
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(record.ShortID + ":" + visitorID))
+	bucket := int(hash.Sum32() % uint32(totalWeight))
+
+	for _, destination := range record.Destinations {
+		if bucket < destination.Weight {
+			return destination, true
+		}
+		bucket -= destination.Weight
+	}
+	return u.Destination{}, false
+}
+
+// visitorID identifies a visitor by the VisitorCookie or, on their first visit, by a hash of their IP address and user agent.
+func visitorID(cookie string, ipAddress string, userAgent string) string {
+	if len(cookie) > 0 {
+		return cookie
+	}
+	if len(ipAddress) == 0 && len(userAgent) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(ipAddress + "\n" + userAgent))
+	return hex.EncodeToString(sum[:16])
+}
+
+// clientIP returns the address of the visitor, preferring the first address in X-Forwarded-For
+// as the service is deployed behind a load balancer.
+func clientIP(forwardedFor string, remoteAddr string) string {
+	if ip := strings.TrimSpace(strings.Split(forwardedFor, ",")[0]); len(ip) > 0 {
+		return ip
+	}
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"testing"
+)
+
+func TestValidateDestinations(t *testing.T) {
+
+	destinations := []Destination{
+		{LongURL: "https://example.com/a", Weight: 70},
+		{Variant: "new-landing", LongURL: "https://example.com/b", Weight: 30},
+	}
+	assert.Nil(t, validateDestinations(ShortenURLValidation, destinations))
+	assert.Equal(t, "A", destinations[0].Variant, "Expected unnamed variants to be named by position")
+	assert.Equal(t, "new-landing", destinations[1].Variant)
+
+	invalid := [][]Destination{
+		{{LongURL: "https://example.com/a", Weight: 1}},
+		{{LongURL: "https://example.com/a", Weight: 1}, {LongURL: "https://example.com/b", Weight: 0}},
+		{{LongURL: "https://example.com/a", Weight: 1}, {LongURL: "https://example.com/b", Weight: MaxDestinationWeight + 1}},
+		{{Variant: "x", LongURL: "https://example.com/a", Weight: 1}, {Variant: "x", LongURL: "https://example.com/b", Weight: 1}},
+		{{LongURL: "https://example.com/a", Weight: 1}, {LongURL: "/relative", Weight: 1}},
+	}
+	for _, destinations := range invalid {
+		err := validateDestinations(ShortenURLValidation, destinations)
+		if assert.NotNil(t, err, "Expected %v to be invalid", destinations) {
+			assert.Equal(t, ShortenURLValidation, int(err.Code()))
+		}
+	}
+}
+
+func TestPickDestinationIsDeterministicAndWeighted(t *testing.T) {
+
+	record := &u.URLRecord{
+		ShortID: savedShortID,
+		Destinations: []u.Destination{
+			{Variant: "A", LongURL: "https://example.com/a", Weight: 70},
+			{Variant: "B", LongURL: "https://example.com/b", Weight: 30},
+		},
+	}
+
+	first, ok := pickDestination(record, "visitor")
+	again, _ := pickDestination(record, "visitor")
+	assert.True(t, ok)
+	assert.Equal(t, first, again, "Expected the same visitor to be sent to the same destination")
+
+	picks := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		destination, _ := pickDestination(record, fmt.Sprintf("visitor-%d", i))
+		picks[destination.Variant]++
+	}
+	assert.InDelta(t, 7000, picks["A"], 300)
+	assert.InDelta(t, 3000, picks["B"], 300)
+
+	_, ok = pickDestination(record, "")
+	assert.False(t, ok, "Expected unknown visitors not to be assigned a destination")
+}
+
+func TestVisitorID(t *testing.T) {
+	assert.Equal(t, "from-cookie", visitorID("from-cookie", "192.0.2.1", "curl/7.64.1"))
+	assert.Equal(t, visitorID("", "192.0.2.1", "curl/7.64.1"), visitorID("", "192.0.2.1", "curl/7.64.1"))
+	assert.NotEqual(t, visitorID("", "192.0.2.1", "curl/7.64.1"), visitorID("", "192.0.2.2", "curl/7.64.1"))
+	assert.Equal(t, "", visitorID("", "", ""))
+}
+
+func TestClientIP(t *testing.T) {
+	assert.Equal(t, "203.0.113.7", clientIP("203.0.113.7, 10.0.0.1", "10.0.0.1:4321"))
+	assert.Equal(t, "192.0.2.1", clientIP("", "192.0.2.1:1234"))
+	assert.Equal(t, "", clientIP("", ""))
+}
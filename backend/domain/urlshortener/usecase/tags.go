@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// MaxTags is the maximum number of tags a link can have
+	MaxTags = 20
+	// MaxMetadataEntries is the maximum number of metadata keys a link can have
+	MaxMetadataEntries     = 20
+	MaxMetadataValueLength = 512
+)
+
+var (
+	// Tags are compared in lowercase so that e.g. 'Summer-Sale' and 'summer-sale' are the same tag
+	tagPattern         = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:/-]{0,63}$`)
+	metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+)
+
+// normalizeTags trims and lowercases the tags, then sorts them and removes duplicates
+func normalizeTags(code domain.Code, tags []string) ([]string, domain.Err) {
+	seen := map[string]bool{}
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, NewError(
+				code,
+				fmt.Sprintf("'%s' is not a valid tag. Tags have up to 64 letters, digits or any of '_.:/-'", tag),
+				nil,
+			)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+
+	if len(normalized) > MaxTags {
+		return nil, NewError(
+			code,
+			fmt.Sprintf("A link can have at most %d tags", MaxTags),
+			nil,
+		)
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func validateMetadata(code domain.Code, metadata map[string]string) domain.Err {
+	if len(metadata) > MaxMetadataEntries {
+		return NewError(
+			code,
+			fmt.Sprintf("`metadata` can have at most %d keys", MaxMetadataEntries),
+			nil,
+		)
+	}
+
+	for key, value := range metadata {
+		if !metadataKeyPattern.MatchString(key) {
+			return NewError(
+				code,
+				fmt.Sprintf("'%s' is not a valid metadata key. Keys have up to 64 letters, digits or any of '_.-'", key),
+				nil,
+			)
+		}
+		if len(value) > MaxMetadataValueLength {
+			return NewError(
+				code,
+				fmt.Sprintf("The metadata value of '%s' must be at most %d characters", key, MaxMetadataValueLength),
+				nil,
+			)
+		}
+	}
+	return nil
+}
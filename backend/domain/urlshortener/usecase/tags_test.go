@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/w-k-s/short-url/codec"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"github.com/w-k-s/short-url/log"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestGivenTags_WhenShorteningURLThatExists_ThenTaggedRecordCreated(t *testing.T) {
+	log.Init()
+
+	//Given
+	baseURL, _ := url.Parse(baseURLString)
+	longURL, _ := url.Parse(savedLongURL)
+	repo := SavingURLRepository{MockURLRepository{ShortURLRecordResult: &u.URLRecord{LongURL: savedLongURL, ShortID: savedShortID}}}
+	useCase := NewShortenURLUseCase(repo, baseURL, MockShortIDGenerator{ShortID: "alpha"})
+
+	//When
+	response, err := useCase.Execute(ShortenURLRequest{
+		LongURL:   savedLongURL,
+		Tags:      []string{"summer"},
+		Metadata:  map[string]string{"team": "growth"},
+		parsedURL: longURL,
+	})
+
+	//Then
+	assert.Nil(t, err)
+	assert.Equal(t, baseURLString+"alpha", response.ShortURL, "Expected tagged links not to reuse an existing record")
+	assert.Equal(t, []string{"summer"}, response.Tags)
+	assert.Equal(t, codec.StringMap{"team": "growth"}, response.Metadata)
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := normalizeTags(ShortenURLValidation, []string{" Summer-Sale", "email", "summer-sale"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"email", "summer-sale"}, tags)
+
+	_, err = normalizeTags(ShortenURLValidation, []string{"summer sale"})
+	assert.NotNil(t, err)
+	_, err = normalizeTags(ShortenURLValidation, []string{""})
+	assert.NotNil(t, err)
+
+	var tooMany []string
+	for i := 0; i <= MaxTags; i++ {
+		tooMany = append(tooMany, fmt.Sprintf("tag%d", i))
+	}
+	_, err = normalizeTags(ShortenURLValidation, tooMany)
+	assert.NotNil(t, err)
+}
+
+func TestValidateMetadata(t *testing.T) {
+	assert.Nil(t, validateMetadata(ShortenURLValidation, map[string]string{"team": "growth", "cost.center": "42"}))
+	assert.NotNil(t, validateMetadata(ShortenURLValidation, map[string]string{"team name": "growth"}))
+	assert.NotNil(t, validateMetadata(ShortenURLValidation, map[string]string{"note": strings.Repeat("a", MaxMetadataValueLength+1)}))
+}
@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Platform is the kind of device a visitor is using, as detected from the User-Agent header
+type Platform string
+
+const (
+	PlatformIOS     Platform = "ios"
+	PlatformAndroid Platform = "android"
+	// PlatformMobile matches any mobile device, including iOS and Android devices
+	PlatformMobile  Platform = "mobile"
+	PlatformDesktop Platform = "desktop"
+)
+
+// MaxTargetingRules is the maximum number of targeting rules a link can have
+const MaxTargetingRules = 20
+
+var (
+	languageTagPattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{1,8})*$`)
+	countryPattern     = regexp.MustCompile(`^[A-Za-z]{2}$`)
+)
+
+// TargetingRule sends visitors that match all of its conditions to a different long url.
+// Conditions that are left empty match every visitor.
+type TargetingRule struct {
+	Platform Platform `json:"platform,omitempty" xml:"platform,omitempty"`
+	// Language is a language tag (e.g. "en" or "pt-BR") matched against the visitor's preferred language
+	Language string `json:"language,omitempty" xml:"language,omitempty"`
+	// Country is an ISO 3166-1 alpha-2 country code (e.g. "AE")
+	Country string `json:"country,omitempty" xml:"country,omitempty"`
+	LongURL string `json:"longUrl" xml:"longUrl"`
+}
+
+// Validate checks that the rule has at least one condition and an absolute long url
+func (r TargetingRule) Validate(code domain.Code) domain.Err {
+	if len(r.Platform) == 0 && len(r.Language) == 0 && len(r.Country) == 0 {
+		return NewError(
+			code,
+			fmt.Sprintf("The targeting rule for '%s' must have a `platform`, `language` or `country`", r.LongURL),
+			nil,
+		)
+	}
+
+	switch r.Platform {
+	case "", PlatformIOS, PlatformAndroid, PlatformMobile, PlatformDesktop:
+	default:
+		return NewError(
+			code,
+			fmt.Sprintf("'%s' is not a valid platform. Expected one of '%s', '%s', '%s' or '%s'", r.Platform, PlatformIOS, PlatformAndroid, PlatformMobile, PlatformDesktop),
+			nil,
+		)
+	}
+
+	if len(r.Language) > 0 && !languageTagPattern.MatchString(r.Language) {
+		return NewError(
+			code,
+			fmt.Sprintf("'%s' is not a valid language tag", r.Language),
+			nil,
+		)
+	}
+
+	if len(r.Country) > 0 && !countryPattern.MatchString(r.Country) {
+		return NewError(
+			code,
+			fmt.Sprintf("'%s' is not a valid country code. Expected a two-letter code e.g. 'AE'", r.Country),
+			nil,
+		)
+	}
+
+	longURL, err := url.Parse(r.LongURL)
+	if err != nil || !longURL.IsAbs() {
+		return NewError(
+			code,
+			fmt.Sprintf("'%s' is not an absolute url", r.LongURL),
+			nil,
+		)
+	}
+	return nil
+}
+
+func validateTargetingRules(code domain.Code, rules []TargetingRule) domain.Err {
+	if len(rules) > MaxTargetingRules {
+		return NewError(
+			code,
+			fmt.Sprintf("A link can have at most %d targeting rules", MaxTargetingRules),
+			nil,
+		)
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toTargetingRecords(rules []TargetingRule) []u.TargetingRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	records := make([]u.TargetingRule, 0, len(rules))
+	for _, rule := range rules {
+		records = append(records, u.TargetingRule{
+			Platform: string(rule.Platform),
+			Language: rule.Language,
+			Country:  strings.ToUpper(rule.Country),
+			LongURL:  rule.LongURL,
+		})
+	}
+	return records
+}
+
+func fromTargetingRecords(records []u.TargetingRule) []TargetingRule {
+	if len(records) == 0 {
+		return nil
+	}
+	rules := make([]TargetingRule, 0, len(records))
+	for _, record := range records {
+		rules = append(rules, TargetingRule{
+			Platform: Platform(record.Platform),
+			Language: record.Language,
+			Country:  record.Country,
+			LongURL:  record.LongURL,
+		})
+	}
+	return rules
+}
+
+// Visitor describes the person following a short url
+type Visitor struct {
+	// ID identifies the visitor across visits. It is empty if the visitor is unknown (e.g. for lookups through the api)
+	ID             string
+	UserAgent      string
+	AcceptLanguage string
+	// Country is the ISO 3166-1 alpha-2 country code of the visitor, if known (e.g. from a CDN header)
+	Country string
+}
+
+func newVisitor(header http.Header, remoteAddr string, countryHeader string) Visitor {
+	visitor := Visitor{
+		UserAgent:      header.Get("User-Agent"),
+		AcceptLanguage: header.Get("Accept-Language"),
+	}
+	if len(countryHeader) > 0 {
+		visitor.Country = strings.TrimSpace(header.Get(countryHeader))
+	}
+
+	var cookieValue string
+	if cookie, err := (&http.Request{Header: header}).Cookie(VisitorCookie); err == nil {
+		cookieValue = cookie.Value
+	}
+	ipAddress := clientIP(header.Get("X-Forwarded-For"), remoteAddr)
+	visitor.ID = visitorID(cookieValue, ipAddress, visitor.UserAgent)
+	return visitor
+}
+
+// Platforms returns the platforms the visitor's device belongs to, most specific first.
+// No platforms are returned if the visitor did not send a User-Agent.
+func (v Visitor) Platforms() []Platform {
+	userAgent := v.UserAgent
+	switch {
+	case len(userAgent) == 0:
+		return nil
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"), strings.Contains(userAgent, "iPod"):
+		return []Platform{PlatformIOS, PlatformMobile}
+	case strings.Contains(userAgent, "Android"):
+		return []Platform{PlatformAndroid, PlatformMobile}
+	case strings.Contains(userAgent, "Mobile"):
+		return []Platform{PlatformMobile}
+	default:
+		return []Platform{PlatformDesktop}
+	}
+}
+
+// Language returns the visitor's most preferred language from the Accept-Language header
+func (v Visitor) Language() string {
+	var preferred string
+	var preferredQuality float64
+
+	for _, part := range strings.Split(v.AcceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if len(tag) == 0 || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if quality > preferredQuality {
+			preferred, preferredQuality = tag, quality
+		}
+	}
+	return preferred
+}
+
+// Matches returns true if the visitor satisfies every condition of the rule
+func (v Visitor) Matches(rule u.TargetingRule) bool {
+	if len(rule.Platform) > 0 && !v.isOnPlatform(Platform(rule.Platform)) {
+		return false
+	}
+	if len(rule.Language) > 0 && !languageMatches(rule.Language, v.Language()) {
+		return false
+	}
+	if len(rule.Country) > 0 && !strings.EqualFold(rule.Country, v.Country) {
+		return false
+	}
+	return true
+}
+
+func (v Visitor) isOnPlatform(platform Platform) bool {
+	for _, candidate := range v.Platforms() {
+		if candidate == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// languageMatches returns true if the language is the rule's language or a more specific variant of it
+// e.g. a rule for "en" matches "en-GB", but a rule for "en-GB" does not match "en".
+func languageMatches(ruleLanguage string, language string) bool {
+	if strings.EqualFold(ruleLanguage, language) {
+		return true
+	}
+	return len(language) > len(ruleLanguage) &&
+		strings.EqualFold(language[:len(ruleLanguage)], ruleLanguage) &&
+		language[len(ruleLanguage)] == '-'
+}
+
+// selectLongURL returns the long url of the first targeting rule the visitor matches.
+// Otherwise, the visitor is assigned one of the record's destinations and its variant is returned.
+// The record's long url is used if the link has neither.
+func selectLongURL(record *u.URLRecord, visitor Visitor) (longURL string, variant string) {
+	for _, rule := range record.TargetingRules {
+		if visitor.Matches(rule) {
+			return rule.LongURL, ""
+		}
+	}
+	if destination, ok := pickDestination(record, visitor.ID); ok {
+		return destination.LongURL, destination.Variant
+	}
+	return record.LongURL, ""
+}
+
+// checkAlternativeLongURLs applies the same destination policy and screening to the long urls of
+// targeting rules and destinations as to the link's main long url.
+// The errors have the code of the calling use case, unless it is shortening a url.
+func checkAlternativeLongURLs(code domain.Code, rules []TargetingRule, destinations []Destination, policy DestinationPolicy, screener URLScreener) domain.Err {
+	var longURLs []string
+	for _, rule := range rules {
+		longURLs = append(longURLs, rule.LongURL)
+	}
+	for _, destination := range destinations {
+		longURLs = append(longURLs, destination.LongURL)
+	}
+
+	for _, rawURL := range longURLs {
+		longURL, err := url.Parse(rawURL)
+		if err != nil {
+			return NewError(
+				code,
+				fmt.Sprintf("'%s' is not a valid url", rawURL),
+				map[string]string{"error": err.Error()},
+			)
+		}
+		if err := policy.Check(longURL); err != nil {
+			return withCode(code, err)
+		}
+		if err := screenURL(screener, longURL); err != nil {
+			return withCode(code, err)
+		}
+	}
+	return nil
+}
+
+// withCode gives the errors of the destination policy and the screener, which have the codes of shortening a url,
+// the validation code of another use case
+func withCode(code domain.Code, err domain.Err) domain.Err {
+	if code == ShortenURLValidation {
+		return err
+	}
+	return NewError(code, err.Error(), err.Fields())
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"github.com/stretchr/testify/assert"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"net/http"
+	"testing"
+)
+
+const (
+	iPhoneUserAgent  = "Mozilla/5.0 (iPhone; CPU iPhone OS 13_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0.3 Mobile/15E148 Safari/604.1"
+	androidUserAgent = "Mozilla/5.0 (Linux; Android 10; Pixel 3) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/78.0.3904.108 Mobile Safari/537.36"
+	desktopUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/78.0.3904.108 Safari/537.36"
+)
+
+func TestVisitorPlatforms(t *testing.T) {
+	assert.Equal(t, []Platform{PlatformIOS, PlatformMobile}, Visitor{UserAgent: iPhoneUserAgent}.Platforms())
+	assert.Equal(t, []Platform{PlatformAndroid, PlatformMobile}, Visitor{UserAgent: androidUserAgent}.Platforms())
+	assert.Equal(t, []Platform{PlatformDesktop}, Visitor{UserAgent: desktopUserAgent}.Platforms())
+	assert.Empty(t, Visitor{}.Platforms())
+}
+
+func TestVisitorLanguage(t *testing.T) {
+	testCases := map[string]string{
+		"":                           "",
+		"ar":                         "ar",
+		"en-GB,en;q=0.9":             "en-GB",
+		"fr;q=0.5, ar;q=0.8, *;q=1":  "ar",
+		"de;q=0.7,pt-BR;q=0.7,en;q=": "en",
+	}
+
+	for acceptLanguage, expected := range testCases {
+		assert.Equal(t, expected, Visitor{AcceptLanguage: acceptLanguage}.Language(), "Accept-Language: %q", acceptLanguage)
+	}
+}
+
+func TestVisitorMatches(t *testing.T) {
+	visitor := newVisitor(http.Header{
+		"User-Agent":                {iPhoneUserAgent},
+		"Accept-Language":           {"en-GB,en;q=0.9"},
+		"Cloudfront-Viewer-Country": {"ae"},
+	}, "192.0.2.1:1234", "CloudFront-Viewer-Country")
+
+	assert.True(t, visitor.Matches(u.TargetingRule{Platform: "mobile"}))
+	assert.True(t, visitor.Matches(u.TargetingRule{Platform: "ios", Language: "en", Country: "AE"}))
+	assert.False(t, visitor.Matches(u.TargetingRule{Platform: "android"}))
+	assert.False(t, visitor.Matches(u.TargetingRule{Platform: "ios", Language: "ar"}))
+	assert.False(t, visitor.Matches(u.TargetingRule{Language: "en-US"}))
+	assert.False(t, visitor.Matches(u.TargetingRule{Country: "SA"}))
+}
+
+func TestTargetLongURL(t *testing.T) {
+	record := &u.URLRecord{
+		LongURL: "https://example.com",
+		TargetingRules: []u.TargetingRule{
+			{Platform: "ios", LongURL: "https://apps.apple.com/app/example"},
+			{Platform: "mobile", LongURL: "https://m.example.com"},
+		},
+	}
+
+	targetLongURL := func(visitor Visitor) string {
+		longURL, _ := selectLongURL(record, visitor)
+		return longURL
+	}
+
+	assert.Equal(t, "https://apps.apple.com/app/example", targetLongURL(Visitor{UserAgent: iPhoneUserAgent}))
+	assert.Equal(t, "https://m.example.com", targetLongURL(Visitor{UserAgent: androidUserAgent}))
+	assert.Equal(t, "https://example.com", targetLongURL(Visitor{UserAgent: desktopUserAgent}))
+	assert.Equal(t, "https://example.com", targetLongURL(Visitor{}))
+}
+
+func TestTargetingRuleValidate(t *testing.T) {
+	valid := []TargetingRule{
+		{Platform: PlatformAndroid, LongURL: "https://play.google.com/store/apps/details?id=com.example"},
+		{Language: "pt-BR", LongURL: "https://example.com/pt"},
+		{Country: "ae", LongURL: "https://example.com/ae"},
+	}
+	for _, rule := range valid {
+		assert.Nil(t, rule.Validate(ShortenURLValidation), "Expected %v to be valid", rule)
+	}
+
+	invalid := []TargetingRule{
+		{LongURL: "https://example.com"},
+		{Platform: "windows", LongURL: "https://example.com"},
+		{Language: "english!", LongURL: "https://example.com"},
+		{Country: "UAE", LongURL: "https://example.com"},
+		{Platform: PlatformIOS, LongURL: "/relative"},
+	}
+	for _, rule := range invalid {
+		err := rule.Validate(ShortenURLValidation)
+		if assert.NotNil(t, err, "Expected %v to be invalid", rule) {
+			assert.Equal(t, ShortenURLValidation, int(err.Code()))
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+)
+
+type UpdateURLUseCase struct {
+	repo     u.URLRepository
+	domains  Domains
+	policy   DestinationPolicy
+	screener URLScreener
+}
+
+// NewUpdateURLUseCase creates the use case. The policy and screener (which may be nil)
+// are applied to long urls added by the update, e.g. in targeting rules.
+func NewUpdateURLUseCase(repo u.URLRepository, domains Domains, policy DestinationPolicy, screener URLScreener) *UpdateURLUseCase {
+	return &UpdateURLUseCase{
+		repo,
+		domains,
+		policy,
+		screener,
+	}
+}
+
+func (s *UpdateURLUseCase) Execute(updateReq UpdateURLRequest) (UpdateURLResponse, domain.Err) {
+
+	record, err := findRecord(s.repo, s.domains, updateReq.Domain, updateReq.ShortID)
+	if err != nil {
+		return UpdateURLResponse{}, NewError(
+			UpdateURLNotFound,
+			fmt.Sprintf("No URL for %s", updateReq.ShortID),
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	if updateReq.Disabled != nil {
+		record.Disabled = *updateReq.Disabled
+		if !record.Disabled {
+			record.DisabledReason = ""
+		}
+	}
+	if updateReq.DisabledReason != nil {
+		record.DisabledReason = *updateReq.DisabledReason
+	}
+	if updateReq.RedirectStatus != nil {
+		record.RedirectStatus = *updateReq.RedirectStatus
+	}
+	if updateReq.QueryMerge != nil {
+		record.QueryMerge = string(*updateReq.QueryMerge)
+	}
+	if updateReq.Wildcard != nil {
+		record.Wildcard = *updateReq.Wildcard
+	}
+	if updateReq.Targeting != nil {
+		if err := checkAlternativeLongURLs(UpdateURLValidation, *updateReq.Targeting, nil, s.policy, s.screener); err != nil {
+			return UpdateURLResponse{}, err
+		}
+		record.TargetingRules = toTargetingRecords(*updateReq.Targeting)
+	}
+	if updateReq.Destinations != nil {
+		if err := checkAlternativeLongURLs(UpdateURLValidation, nil, *updateReq.Destinations, s.policy, s.screener); err != nil {
+			return UpdateURLResponse{}, err
+		}
+		record.Destinations = toDestinationRecords(*updateReq.Destinations)
+	}
+
+	if updateReq.Tags != nil {
+		record.Tags = *updateReq.Tags
+	}
+	if len(updateReq.Metadata) > 0 {
+		metadata := map[string]string{}
+		for key, value := range record.Metadata {
+			metadata[key] = value
+		}
+		for key, value := range updateReq.Metadata {
+			if value == nil {
+				delete(metadata, key)
+			} else {
+				metadata[key] = *value
+			}
+		}
+		if len(metadata) > MaxMetadataEntries {
+			return UpdateURLResponse{}, NewError(
+				UpdateURLValidation,
+				fmt.Sprintf("`metadata` can have at most %d keys", MaxMetadataEntries),
+				nil,
+			)
+		}
+		record.Metadata = metadata
+	}
+
+	if err = s.repo.UpdateRecord(record); err != nil {
+		return UpdateURLResponse{}, NewError(
+			UpdateURLFailedToSave,
+			fmt.Sprintf("Failed to update %s", updateReq.ShortID),
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	return UpdateURLResponse{
+		ShortID:        record.ShortID,
+		LongURL:        record.LongURL,
+		Disabled:       record.Disabled,
+		DisabledReason: record.DisabledReason,
+		RedirectStatus: record.RedirectStatus,
+		QueryMerge:     QueryMerge(record.QueryMerge),
+		Wildcard:       record.Wildcard,
+		Targeting:      fromTargetingRecords(record.TargetingRules),
+		Destinations:   fromDestinationRecords(record.Destinations),
+		Tags:           record.Tags,
+		Metadata:       record.Metadata,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"encoding/json"
+	"github.com/w-k-s/short-url/domain"
+	"net/http"
+)
+
+// UpdateURLRequest changes the fields of an existing record.
+// Fields that are omitted (nil) are left unchanged.
+type UpdateURLRequest struct {
+	ShortID string `json:"-"`
+	// Domain is the host of the domain the shortId is on, read from the query string e.g. `?domain=brand.ly`.
+	// The default domain is used if it is empty.
+	Domain         string      `json:"-"`
+	Disabled       *bool       `json:"disabled"`
+	DisabledReason *string     `json:"disabledReason"`
+	RedirectStatus *int        `json:"redirectStatus"`
+	QueryMerge     *QueryMerge `json:"queryMerge"`
+	Wildcard       *bool       `json:"wildcard"`
+	// Targeting replaces all targeting rules. An empty list removes them.
+	Targeting *[]TargetingRule `json:"targeting"`
+	// Destinations replaces the destinations that visitors are split across. An empty list removes them.
+	Destinations *[]Destination `json:"destinations"`
+	// Tags replaces all tags. An empty list removes them.
+	Tags *[]string `json:"tags"`
+	// Metadata is merged into the existing metadata. Keys set to null are removed.
+	Metadata map[string]*string `json:"metadata"`
+}
+
+func NewUpdateURLRequest(shortID string, req *http.Request) (UpdateURLRequest, domain.Err) {
+
+	if len(shortID) == 0 {
+		return UpdateURLRequest{}, NewError(
+			UpdateURLValidation,
+			"`shortId` is required",
+			nil,
+		)
+	}
+
+	decoder := json.NewDecoder(req.Body)
+
+	var updateReq UpdateURLRequest
+	err := decoder.Decode(&updateReq)
+	if err != nil {
+		return UpdateURLRequest{}, NewError(
+			UpdateURLDecoding,
+			"JSON Body must include the fields to update",
+			map[string]string{"error": err.Error()},
+		)
+	}
+
+	if updateReq.Disabled == nil &&
+		updateReq.DisabledReason == nil &&
+		updateReq.RedirectStatus == nil &&
+		updateReq.QueryMerge == nil &&
+		updateReq.Wildcard == nil &&
+		updateReq.Targeting == nil &&
+		updateReq.Destinations == nil &&
+		updateReq.Tags == nil &&
+		len(updateReq.Metadata) == 0 {
+		return UpdateURLRequest{}, NewError(
+			UpdateURLValidation,
+			"At least one field must be updated",
+			nil,
+		)
+	}
+
+	if updateReq.RedirectStatus != nil {
+		if err := validateRedirectStatus(UpdateURLValidation, *updateReq.RedirectStatus); err != nil {
+			return UpdateURLRequest{}, err
+		}
+	}
+
+	if updateReq.QueryMerge != nil {
+		if err := validateQueryMerge(UpdateURLValidation, *updateReq.QueryMerge); err != nil {
+			return UpdateURLRequest{}, err
+		}
+	}
+
+	if updateReq.Targeting != nil {
+		if err := validateTargetingRules(UpdateURLValidation, *updateReq.Targeting); err != nil {
+			return UpdateURLRequest{}, err
+		}
+	}
+
+	if updateReq.Destinations != nil {
+		if err := validateDestinations(UpdateURLValidation, *updateReq.Destinations); err != nil {
+			return UpdateURLRequest{}, err
+		}
+	}
+
+	if updateReq.Tags != nil {
+		tags, err := normalizeTags(UpdateURLValidation, *updateReq.Tags)
+		if err != nil {
+			return UpdateURLRequest{}, err
+		}
+		updateReq.Tags = &tags
+	}
+
+	if len(updateReq.Metadata) > 0 {
+		// Removed keys need no validation; the number of keys is checked once the metadata is merged
+		set := map[string]string{}
+		for key, value := range updateReq.Metadata {
+			if value != nil {
+				set[key] = *value
+			}
+		}
+		if err := validateMetadata(UpdateURLValidation, set); err != nil {
+			return UpdateURLRequest{}, err
+		}
+	}
+
+	updateReq.ShortID = shortID
+	updateReq.Domain = req.URL.Query().Get("domain")
+	return updateReq, nil
+}
@@ -0,0 +1,17 @@
+package usecase
+
+import "github.com/w-k-s/short-url/codec"
+
+type UpdateURLResponse struct {
+	ShortID        string          `json:"shortId" xml:"shortId"`
+	LongURL        string          `json:"longUrl" xml:"longUrl"`
+	Disabled       bool            `json:"disabled" xml:"disabled"`
+	DisabledReason string          `json:"disabledReason,omitempty" xml:"disabledReason,omitempty"`
+	RedirectStatus int             `json:"redirectStatus,omitempty" xml:"redirectStatus,omitempty"`
+	QueryMerge     QueryMerge      `json:"queryMerge,omitempty" xml:"queryMerge,omitempty"`
+	Wildcard       bool            `json:"wildcard" xml:"wildcard"`
+	Targeting      []TargetingRule `json:"targeting,omitempty" xml:"targeting>item,omitempty"`
+	Destinations   []Destination   `json:"destinations,omitempty" xml:"destinations>item,omitempty"`
+	Tags           []string        `json:"tags,omitempty" xml:"tags>item,omitempty"`
+	Metadata       codec.StringMap `json:"metadata,omitempty" xml:"metadata,omitempty"`
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/w-k-s/short-url/codec"
+	u "github.com/w-k-s/short-url/domain/urlshortener"
+	"testing"
+	"time"
+)
+
+type UpdateURLUseCaseTestSuite struct {
+	suite.Suite
+	urlRepo *MockURLRepository
+	record  *u.URLRecord
+	useCase *UpdateURLUseCase
+}
+
+func (suite *UpdateURLUseCaseTestSuite) SetupTest() {
+	suite.record = &u.URLRecord{
+		LongURL:    savedLongURL,
+		ShortID:    savedShortID,
+		CreateTime: time.Now(),
+	}
+
+	suite.urlRepo = &MockURLRepository{}
+	suite.useCase = NewUpdateURLUseCase(suite.urlRepo, NewDomains(nil, nil, nil), DefaultDestinationPolicy(), nil)
+}
+
+func TestUpdateURLUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(UpdateURLUseCaseTestSuite))
+}
+
+func (suite *UpdateURLUseCaseTestSuite) TestGivenRecordExists_WhenDisabling_ThenRecordDisabled() {
+
+	//Given
+	disabled := true
+	reason := "phishing"
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	response, err := suite.useCase.Execute(UpdateURLRequest{
+		ShortID:        savedShortID,
+		Disabled:       &disabled,
+		DisabledReason: &reason,
+	})
+
+	//Then
+	assert.Nil(suite.T(), err, "UpdateURL: Expected no error, got %v", err)
+	assert.True(suite.T(), response.Disabled)
+	assert.Equal(suite.T(), reason, response.DisabledReason)
+	assert.True(suite.T(), suite.record.Disabled)
+}
+
+func (suite *UpdateURLUseCaseTestSuite) TestGivenRecordDisabled_WhenEnabling_ThenReasonCleared() {
+
+	//Given
+	enabled := false
+	suite.record.Disabled = true
+	suite.record.DisabledReason = "phishing"
+	suite.urlRepo.LongURLRecordResult = suite.record
+
+	//When
+	response, err := suite.useCase.Execute(UpdateURLRequest{
+		ShortID:  savedShortID,
+		Disabled: &enabled,
+	})
+
+	//Then
+	assert.Nil(suite.T(), err, "UpdateURL: Expected no error, got %v", err)
+	assert.False(suite.T(), response.Disabled)
+	assert.Equal(suite.T(), "", response.DisabledReason)
+}
+
+func (suite *UpdateURLUseCaseTestSuite) TestGivenRecordDoesNotExist_WhenUpdating_ThenReturnError() {
+
+	//Given
+	disabled := true
+	suite.urlRepo.ReturnError = true
+	suite.urlRepo.LongURLRecordError = errors.New("Not Found")
+
+	//When
+	_, err := suite.useCase.Execute(UpdateURLRequest{
+		ShortID:  "nil",
+		Disabled: &disabled,
+	})
+
+	//Then
+	expectation := UpdateURLNotFound
+	assert.NotNil(suite.T(), err, "UpdateURL: Expected Error, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "UpdateURL wrong error code. Expected '%d'. Got: %d", expectation, int(err.Code()))
+}
+
+func (suite *UpdateURLUseCaseTestSuite) TestGivenRecordExists_WhenUpdatingTargeting_ThenRulesReplaced() {
+
+	//Given
+	suite.record.TargetingRules = []u.TargetingRule{{Platform: "ios", LongURL: "https://apps.apple.com/app/example"}}
+	suite.urlRepo.LongURLRecordResult = suite.record
+	targeting := []TargetingRule{{Country: "ae", LongURL: "https://example.ae"}}
+
+	//When
+	response, err := suite.useCase.Execute(UpdateURLRequest{
+		ShortID:   savedShortID,
+		Targeting: &targeting,
+	})
+
+	//Then
+	assert.Nil(suite.T(), err, "UpdateURL: Expected no error, got %v", err)
+	assert.Equal(suite.T(), []u.TargetingRule{{Country: "AE", LongURL: "https://example.ae"}}, suite.record.TargetingRules)
+	assert.Equal(suite.T(), "AE", response.Targeting[0].Country)
+}
+
+func (suite *UpdateURLUseCaseTestSuite) TestGivenTargetingRuleToPrivateHost_WhenUpdating_ThenReturnError() {
+
+	//Given
+	suite.urlRepo.LongURLRecordResult = suite.record
+	targeting := []TargetingRule{{Platform: PlatformDesktop, LongURL: "http://192.168.0.1/admin"}}
+
+	//When
+	_, err := suite.useCase.Execute(UpdateURLRequest{
+		ShortID:   savedShortID,
+		Targeting: &targeting,
+	})
+
+	//Then
+	expectation := UpdateURLValidation
+	assert.NotNil(suite.T(), err, "UpdateURL: Expected Error, got nil")
+	assert.Equal(suite.T(), expectation, int(err.Code()), "UpdateURL wrong error code. Expected '%d'. Got: %d", expectation, int(err.Code()))
+	assert.Nil(suite.T(), suite.record.TargetingRules)
+}
+
+func (suite *UpdateURLUseCaseTestSuite) TestGivenMetadata_WhenUpdatingMetadata_ThenMetadataMerged() {
+
+	//Given
+	suite.record.Tags = []string{"old"}
+	suite.record.Metadata = map[string]string{"team": "growth", "campaign": "spring"}
+	suite.urlRepo.LongURLRecordResult = suite.record
+	summer := "summer"
+	tags := []string{"email", "summer"}
+
+	//When
+	response, err := suite.useCase.Execute(UpdateURLRequest{
+		ShortID:  savedShortID,
+		Tags:     &tags,
+		Metadata: map[string]*string{"campaign": &summer, "team": nil},
+	})
+
+	//Then
+	assert.Nil(suite.T(), err, "UpdateURL: Expected no error, got %v", err)
+	assert.Equal(suite.T(), tags, response.Tags)
+	assert.Equal(suite.T(), codec.StringMap{"campaign": "summer"}, response.Metadata)
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/w-k-s/short-url/domain"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+const maxUTMParameterLength = 256
+
+// UTMParameters are the campaign parameters used by analytics tools to attribute visits
+type UTMParameters struct {
+	Source   string `json:"source" xml:"source"`
+	Medium   string `json:"medium" xml:"medium"`
+	Campaign string `json:"campaign" xml:"campaign"`
+	Term     string `json:"term,omitempty" xml:"term,omitempty"`
+	Content  string `json:"content,omitempty" xml:"content,omitempty"`
+}
+
+func (p UTMParameters) parameters() [][2]string {
+	return [][2]string{
+		{"utm_source", p.Source},
+		{"utm_medium", p.Medium},
+		{"utm_campaign", p.Campaign},
+		{"utm_term", p.Term},
+		{"utm_content", p.Content},
+	}
+}
+
+// Validate checks that the source, medium and campaign are provided
+// and that no parameter is too long or contains control characters.
+func (p UTMParameters) Validate() domain.Err {
+	fields := map[string]string{}
+
+	for i, parameter := range p.parameters() {
+		name, value := parameter[0], parameter[1]
+		switch {
+		case i < 3 && len(strings.TrimSpace(value)) == 0:
+			fields[name] = "is required"
+		case len(value) > maxUTMParameterLength:
+			fields[name] = fmt.Sprintf("must not be longer than %d characters", maxUTMParameterLength)
+		case strings.IndexFunc(value, unicode.IsControl) >= 0:
+			fields[name] = "must not contain control characters"
+		}
+	}
+
+	if len(fields) > 0 {
+		return NewError(
+			ShortenURLValidation,
+			"Invalid `utm` parameters",
+			fields,
+		)
+	}
+	return nil
+}
+
+// Apply returns a copy of longURL with the utm parameters added to its query.
+// Parameters already in the query are replaced; empty parameters are left unchanged.
+func (p UTMParameters) Apply(longURL *url.URL) *url.URL {
+	tagged := *longURL
+	query := tagged.Query()
+	for _, parameter := range p.parameters() {
+		if name, value := parameter[0], strings.TrimSpace(parameter[1]); len(value) > 0 {
+			query.Set(name, value)
+		}
+	}
+	tagged.RawQuery = query.Encode()
+	return &tagged
+}
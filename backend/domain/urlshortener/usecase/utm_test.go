@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestUTMParametersApply(t *testing.T) {
+
+	longURL, _ := url.Parse("https://www.example.com/landing?utm_source=old&page=2")
+	utm := UTMParameters{
+		Source:   "newsletter",
+		Medium:   "email",
+		Campaign: "spring sale",
+	}
+
+	tagged := utm.Apply(longURL)
+
+	assert.Equal(t, "https://www.example.com/landing?page=2&utm_campaign=spring+sale&utm_medium=email&utm_source=newsletter", tagged.String())
+	assert.Equal(t, "https://www.example.com/landing?utm_source=old&page=2", longURL.String(), "Apply must not modify the original url")
+}
+
+func TestUTMParametersValidate(t *testing.T) {
+
+	valid := UTMParameters{Source: "newsletter", Medium: "email", Campaign: "spring", Term: "shoes"}
+	assert.Nil(t, valid.Validate())
+
+	missing := UTMParameters{Source: "newsletter"}
+	err := missing.Validate()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ShortenURLValidation, int(err.Code()))
+		assert.Contains(t, err.Fields(), "utm_medium")
+		assert.Contains(t, err.Fields(), "utm_campaign")
+		assert.NotContains(t, err.Fields(), "utm_source")
+	}
+
+	tooLong := UTMParameters{Source: "newsletter", Medium: "email", Campaign: "spring", Content: strings.Repeat("a", 300)}
+	err = tooLong.Validate()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Fields(), "utm_content")
+	}
+
+	controlCharacters := UTMParameters{Source: "news\nletter", Medium: "email", Campaign: "spring"}
+	assert.NotNil(t, controlCharacters.Validate())
+}
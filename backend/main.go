@@ -2,6 +2,7 @@ package main
 
 import (
 	dep "github.com/w-k-s/short-url/adapters/dependencies"
+	"github.com/w-k-s/short-url/adapters/rpc"
 	"github.com/w-k-s/short-url/adapters/web"
 	"github.com/w-k-s/short-url/adapters/web/controllers"
 	"github.com/w-k-s/short-url/config"
@@ -17,14 +18,37 @@ func init() {
 }
 
 func main() {
+	defer dep.Close()
 
-	app = web.Init(config.Settings.ListenAddress)
+	app = web.Init(config.Settings.ListenAddress, config.Settings.GetPathPrefix(), config.Settings.RequestTimeout)
 
-	app.Register(controllers.GetHealthCheckHandler(dep.Db))
-	app.Register(controllers.GetShortenURLHandler(dep.ShortenURLUseCase, dep.JsonFmt))
-	app.Register(controllers.GetRetrieveOriginalURLHandler(dep.RetrieveOriginalURLUseCase, dep.JsonFmt))
-	app.Register(controllers.GetRedirectToOriginalURLHandler(dep.RetrieveOriginalURLUseCase, dep.JsonFmt))
+	app.RegisterAtRoot(controllers.GetHealthCheckHandler(dep.Db))
+	app.Register(controllers.GetOpenAPIHandler(dep.JsonFmt))
+	app.Register(controllers.GetShortenURLHandler(dep.ShortenURLUseCase, dep.ResponseFmt))
+	app.RegisterStreaming(controllers.GetBatchShortenURLHandler(dep.BatchShortenURLUseCase, dep.JsonFmt, dep.ResponseFmt))
+	app.Register(controllers.GetListURLsHandler(dep.ListURLsUseCase, config.Settings.AdminToken, dep.ResponseFmt))
+	app.Register(controllers.GetRetrieveOriginalURLHandler(dep.RetrieveOriginalURLUseCase, dep.ResponseFmt))
+	app.Register(controllers.GetRetrieveShortIDHandler(dep.RetrieveOriginalURLUseCase, dep.ResponseFmt))
+	app.Register(controllers.GetUpdateURLHandler(dep.UpdateURLUseCase, config.Settings.AdminToken, dep.ResponseFmt))
+	app.Register(controllers.GetClickStatsHandler(dep.ClickStatsUseCase, config.Settings.AdminToken, dep.ResponseFmt))
+	app.Register(controllers.GetQRCodeHandler(dep.QRCodeUseCase, dep.ResponseFmt))
+	app.RegisterRedirects(controllers.GetRedirectToOriginalURLHandler(dep.RetrieveOriginalURLUseCase, dep.RedirectCachePolicy, dep.ResponseFmt))
 	app.Register(controllers.GetLogRequestMiddleware(dep.LogRepository))
+	app.EnableCORS(dep.CORSPolicy)
+
+	if len(config.Settings.GRPCListenAddress) > 0 {
+		grpcApp := rpc.Init(config.Settings.GRPCListenAddress, rpc.NewServer(
+			dep.ShortenURLUseCase,
+			dep.BatchShortenURLUseCase,
+			dep.RetrieveOriginalURLUseCase,
+			dep.UpdateURLUseCase,
+			dep.ClickStatsUseCase,
+			config.Settings.AdminToken,
+		))
+		go func() {
+			log.Panic(grpcApp.ListenAndServe())
+		}()
+	}
 
 	log.Panic(app.ListenAndServe())
 }
@@ -16,10 +16,40 @@ SET xmloption = content;
 SET client_min_messages = warning;
 SET row_security = off;
 
+--
+-- Name: pg_trgm; Type: EXTENSION; Schema: -; Owner: -
+--
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+
+--
+-- Name: EXTENSION pg_trgm; Type: COMMENT; Schema: -; Owner: 
+--
+
+COMMENT ON EXTENSION pg_trgm IS 'text similarity measurement and index searching based on trigrams';
+
+
 SET default_tablespace = '';
 
 SET default_with_oids = false;
 
+--
+-- Name: idempotency_keys; Type: TABLE; Schema: public; Owner: shorturl
+--
+
+CREATE TABLE public.idempotency_keys (
+    owner character varying(128) DEFAULT ''::character varying NOT NULL,
+    key character varying(255) NOT NULL,
+    fingerprint character varying(64) NOT NULL,
+    response text,
+    create_time timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    expire_time timestamp with time zone NOT NULL
+);
+
+
+ALTER TABLE public.idempotency_keys OWNER TO shorturl;
+
 --
 -- Name: logs; Type: TABLE; Schema: public; Owner: shorturl
 --
@@ -42,19 +72,241 @@ ALTER TABLE public.logs OWNER TO shorturl;
 
 CREATE TABLE public.url_records (
     long_url text,
+    domain character varying(255) NOT NULL,
     short_id character varying(128) NOT NULL,
-    create_time timestamp with time zone DEFAULT CURRENT_TIMESTAMP
+    owner character varying(128) DEFAULT ''::character varying NOT NULL,
+    create_time timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+    update_time timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    disabled boolean DEFAULT false NOT NULL,
+    disabled_reason text DEFAULT ''::text NOT NULL,
+    redirect_status smallint DEFAULT 0 NOT NULL,
+    query_merge character varying(16) DEFAULT ''::character varying NOT NULL,
+    wildcard boolean DEFAULT false NOT NULL,
+    social_title text DEFAULT ''::text NOT NULL,
+    social_description text DEFAULT ''::text NOT NULL,
+    social_image text DEFAULT ''::text NOT NULL,
+    long_url_host character varying(255) DEFAULT ''::character varying NOT NULL
 );
 
 
 ALTER TABLE public.url_records OWNER TO shorturl;
 
+--
+-- Name: url_clicks; Type: TABLE; Schema: public; Owner: shorturl
+--
+
+CREATE TABLE public.url_clicks (
+    domain character varying(255) NOT NULL,
+    short_id character varying(128) NOT NULL,
+    variant character varying(64) DEFAULT ''::character varying NOT NULL,
+    create_time timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+
+ALTER TABLE public.url_clicks OWNER TO shorturl;
+
+--
+-- Name: url_destinations; Type: TABLE; Schema: public; Owner: shorturl
+--
+
+CREATE TABLE public.url_destinations (
+    domain character varying(255) NOT NULL,
+    short_id character varying(128) NOT NULL,
+    "position" smallint NOT NULL,
+    variant character varying(64) NOT NULL,
+    long_url text NOT NULL,
+    weight integer NOT NULL
+);
+
+
+ALTER TABLE public.url_destinations OWNER TO shorturl;
+
+--
+-- Name: url_metadata; Type: TABLE; Schema: public; Owner: shorturl
+--
+
+CREATE TABLE public.url_metadata (
+    domain character varying(255) NOT NULL,
+    short_id character varying(128) NOT NULL,
+    key character varying(64) NOT NULL,
+    value text NOT NULL
+);
+
+
+ALTER TABLE public.url_metadata OWNER TO shorturl;
+
+--
+-- Name: url_tags; Type: TABLE; Schema: public; Owner: shorturl
+--
+
+CREATE TABLE public.url_tags (
+    domain character varying(255) NOT NULL,
+    short_id character varying(128) NOT NULL,
+    tag character varying(64) NOT NULL
+);
+
+
+ALTER TABLE public.url_tags OWNER TO shorturl;
+
+--
+-- Name: url_targeting_rules; Type: TABLE; Schema: public; Owner: shorturl
+--
+
+CREATE TABLE public.url_targeting_rules (
+    domain character varying(255) NOT NULL,
+    short_id character varying(128) NOT NULL,
+    "position" smallint NOT NULL,
+    platform character varying(16) DEFAULT ''::character varying NOT NULL,
+    language character varying(35) DEFAULT ''::character varying NOT NULL,
+    country character varying(2) DEFAULT ''::character varying NOT NULL,
+    long_url text NOT NULL
+);
+
+
+ALTER TABLE public.url_targeting_rules OWNER TO shorturl;
+
+--
+-- Name: idempotency_keys idempotency_keys_pkey; Type: CONSTRAINT; Schema: public; Owner: shorturl
+--
+
+ALTER TABLE ONLY public.idempotency_keys
+    ADD CONSTRAINT idempotency_keys_pkey PRIMARY KEY (owner, key);
+
+
+--
+-- Name: url_destinations url_destinations_pkey; Type: CONSTRAINT; Schema: public; Owner: shorturl
+--
+
+ALTER TABLE ONLY public.url_destinations
+    ADD CONSTRAINT url_destinations_pkey PRIMARY KEY (domain, short_id, "position");
+
+
+--
+-- Name: url_metadata url_metadata_pkey; Type: CONSTRAINT; Schema: public; Owner: shorturl
+--
+
+ALTER TABLE ONLY public.url_metadata
+    ADD CONSTRAINT url_metadata_pkey PRIMARY KEY (domain, short_id, key);
+
+
 --
 -- Name: url_records url_records_pkey; Type: CONSTRAINT; Schema: public; Owner: shorturl
 --
 
 ALTER TABLE ONLY public.url_records
-    ADD CONSTRAINT url_records_pkey PRIMARY KEY (short_id);
+    ADD CONSTRAINT url_records_pkey PRIMARY KEY (domain, short_id);
+
+
+--
+-- Name: url_tags url_tags_pkey; Type: CONSTRAINT; Schema: public; Owner: shorturl
+--
+
+ALTER TABLE ONLY public.url_tags
+    ADD CONSTRAINT url_tags_pkey PRIMARY KEY (domain, short_id, tag);
+
+
+--
+-- Name: url_targeting_rules url_targeting_rules_pkey; Type: CONSTRAINT; Schema: public; Owner: shorturl
+--
+
+ALTER TABLE ONLY public.url_targeting_rules
+    ADD CONSTRAINT url_targeting_rules_pkey PRIMARY KEY (domain, short_id, "position");
+
+
+--
+-- Name: idempotency_keys_expire_time_idx; Type: INDEX; Schema: public; Owner: shorturl
+--
+
+CREATE INDEX idempotency_keys_expire_time_idx ON public.idempotency_keys USING btree (expire_time);
+
+
+--
+-- Name: url_clicks_domain_short_id_idx; Type: INDEX; Schema: public; Owner: shorturl
+--
+
+CREATE INDEX url_clicks_domain_short_id_idx ON public.url_clicks USING btree (domain, short_id);
+
+
+--
+-- Name: url_records_create_time_idx; Type: INDEX; Schema: public; Owner: shorturl
+--
+
+CREATE INDEX url_records_create_time_idx ON public.url_records USING btree (create_time, short_id);
+
+
+--
+-- Name: url_records_long_url_host_idx; Type: INDEX; Schema: public; Owner: shorturl
+--
+
+CREATE INDEX url_records_long_url_host_idx ON public.url_records USING btree (long_url_host, create_time, short_id);
+
+
+--
+-- Name: url_records_long_url_owner_idx; Type: INDEX; Schema: public; Owner: shorturl
+--
+
+CREATE INDEX url_records_long_url_owner_idx ON public.url_records USING btree (long_url, owner);
+
+
+--
+-- Name: url_records_long_url_trgm_idx; Type: INDEX; Schema: public; Owner: shorturl
+--
+
+CREATE INDEX url_records_long_url_trgm_idx ON public.url_records USING gin (lower(long_url) public.gin_trgm_ops);
+
+
+--
+-- Name: url_records_owner_create_time_idx; Type: INDEX; Schema: public; Owner: shorturl
+--
+
+CREATE INDEX url_records_owner_create_time_idx ON public.url_records USING btree (owner, create_time, short_id);
+
+
+--
+-- Name: url_tags_tag_idx; Type: INDEX; Schema: public; Owner: shorturl
+--
+
+CREATE INDEX url_tags_tag_idx ON public.url_tags USING btree (tag, domain, short_id);
+
+
+--
+-- Name: url_clicks url_clicks_domain_short_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: shorturl
+--
+
+ALTER TABLE ONLY public.url_clicks
+    ADD CONSTRAINT url_clicks_domain_short_id_fkey FOREIGN KEY (domain, short_id) REFERENCES public.url_records(domain, short_id) ON DELETE CASCADE;
+
+
+--
+-- Name: url_destinations url_destinations_domain_short_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: shorturl
+--
+
+ALTER TABLE ONLY public.url_destinations
+    ADD CONSTRAINT url_destinations_domain_short_id_fkey FOREIGN KEY (domain, short_id) REFERENCES public.url_records(domain, short_id) ON DELETE CASCADE;
+
+
+--
+-- Name: url_metadata url_metadata_domain_short_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: shorturl
+--
+
+ALTER TABLE ONLY public.url_metadata
+    ADD CONSTRAINT url_metadata_domain_short_id_fkey FOREIGN KEY (domain, short_id) REFERENCES public.url_records(domain, short_id) ON DELETE CASCADE;
+
+
+--
+-- Name: url_tags url_tags_domain_short_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: shorturl
+--
+
+ALTER TABLE ONLY public.url_tags
+    ADD CONSTRAINT url_tags_domain_short_id_fkey FOREIGN KEY (domain, short_id) REFERENCES public.url_records(domain, short_id) ON DELETE CASCADE;
+
+
+--
+-- Name: url_targeting_rules url_targeting_rules_domain_short_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: shorturl
+--
+
+ALTER TABLE ONLY public.url_targeting_rules
+    ADD CONSTRAINT url_targeting_rules_domain_short_id_fkey FOREIGN KEY (domain, short_id) REFERENCES public.url_records(domain, short_id) ON DELETE CASCADE;
 
 
 --